@@ -0,0 +1,256 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/patipolchat/html2pdf/html2pdf"
+)
+
+// benchConfig holds the bench command flags.
+type benchConfig struct {
+	concurrency int
+	duration    time.Duration
+	input       string
+	soak        bool
+}
+
+// benchStats summarizes a benchmark run.
+type benchStats struct {
+	conversions int
+	failures    int
+	elapsed     time.Duration
+	latencies   []time.Duration
+	// chromeStart, chromePeak and chromeEnd are the resident memory of all
+	// Chrome processes in bytes at the start, at the peak, and at the end.
+	chromeStart, chromePeak, chromeEnd int64
+}
+
+func runBench(ctx context.Context, args []string) error {
+	var cfg benchConfig
+	fs := flag.NewFlagSet("bench", flag.ContinueOnError)
+	fs.IntVar(&cfg.concurrency, "concurrency", 4, "number of concurrent conversions")
+	fs.DurationVar(&cfg.duration, "duration", 30*time.Second, "how long to run")
+	fs.StringVar(&cfg.input, "input", "", "directory of .html documents to convert")
+	fs.BoolVar(&cfg.soak, "soak", false, "convert serially and fail if goroutines, temp files or browser processes leak")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if cfg.input == "" || cfg.concurrency < 1 || cfg.duration <= 0 {
+		return errors.New("--input, a positive --concurrency and a positive --duration are required")
+	}
+
+	corpus, err := loadCorpus(cfg.input)
+	if err != nil {
+		return err
+	}
+	if cfg.soak {
+		return soak(ctx, cfg, corpus, os.Stdout)
+	}
+	stats := bench(ctx, cfg, corpus, html2pdf.ConvertHtmlToPdf)
+	stats.print(os.Stdout)
+	return nil
+}
+
+// loadCorpus reads every .html file below dir.
+func loadCorpus(dir string) ([]string, error) {
+	var docs []string
+	err := filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+		if err != nil || d.IsDir() || !strings.EqualFold(filepath.Ext(path), ".html") {
+			return err
+		}
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		docs = append(docs, string(b))
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(docs) == 0 {
+		return nil, fmt.Errorf("no .html files in %s", dir)
+	}
+	return docs, nil
+}
+
+// soak runs html2pdf.Soak over the corpus for cfg.duration and prints the
+// resource usage before and after.
+func soak(ctx context.Context, cfg benchConfig, corpus []string, w io.Writer) error {
+	ctx, cancel := context.WithTimeout(ctx, cfg.duration)
+	defer cancel()
+
+	report, err := html2pdf.Soak(ctx, corpus, 0, html2pdf.WithLogger(func(string, ...interface{}) {}))
+	if report != nil {
+		fmt.Fprintf(w, "conversions:  %d\n", report.Conversions)
+		fmt.Fprintf(w, "baseline:     %+v\n", report.Baseline)
+		fmt.Fprintf(w, "final:        %+v\n", report.Final)
+	}
+	return err
+}
+
+// convertFunc converts one document; it matches html2pdf.ConvertHtmlToPdf.
+type convertFunc func(ctx context.Context, html string, opts ...html2pdf.Option) ([]byte, error)
+
+// bench converts the corpus round-robin on cfg.concurrency workers until
+// cfg.duration passes or ctx is canceled.
+func bench(ctx context.Context, cfg benchConfig, corpus []string, convert convertFunc) *benchStats {
+	ctx, cancel := context.WithTimeout(ctx, cfg.duration)
+	defer cancel()
+
+	stats := &benchStats{chromeStart: chromeMemory()}
+	stats.chromePeak = stats.chromeStart
+	done := make(chan struct{})
+	var sampler sync.WaitGroup
+	sampler.Add(1)
+	go func() {
+		defer sampler.Done()
+		ticker := time.NewTicker(time.Second)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				if m := chromeMemory(); m > stats.chromePeak {
+					stats.chromePeak = m
+				}
+			}
+		}
+	}()
+
+	var (
+		mu      sync.Mutex
+		next    int
+		workers sync.WaitGroup
+	)
+	start := time.Now()
+	for w := 0; w < cfg.concurrency; w++ {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			for ctx.Err() == nil {
+				mu.Lock()
+				doc := corpus[next%len(corpus)]
+				next++
+				mu.Unlock()
+
+				t := time.Now()
+				_, err := convert(ctx, doc, html2pdf.WithLogger(func(string, ...interface{}) {}))
+				latency := time.Since(t)
+
+				mu.Lock()
+				switch {
+				case err == nil:
+					stats.conversions++
+					stats.latencies = append(stats.latencies, latency)
+				case ctx.Err() == nil:
+					// Conversions cut off by the end of the run are not failures.
+					stats.failures++
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+	workers.Wait()
+	stats.elapsed = time.Since(start)
+	close(done)
+	sampler.Wait()
+	stats.chromeEnd = chromeMemory()
+	return stats
+}
+
+// percentile returns the p-th percentile (0-100) of sorted latencies using
+// the nearest-rank method.
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
+
+// print writes the report.
+func (s *benchStats) print(w io.Writer) {
+	sorted := append([]time.Duration(nil), s.latencies...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	throughput := 0.0
+	if s.elapsed > 0 {
+		throughput = float64(s.conversions) / s.elapsed.Seconds()
+	}
+	fmt.Fprintf(w, "conversions:  %d (%d failed)\n", s.conversions, s.failures)
+	fmt.Fprintf(w, "throughput:   %.2f docs/s\n", throughput)
+	fmt.Fprintf(w, "latency p50:  %v\n", percentile(sorted, 50).Round(time.Millisecond))
+	fmt.Fprintf(w, "latency p95:  %v\n", percentile(sorted, 95).Round(time.Millisecond))
+	fmt.Fprintf(w, "chrome rss:   start %s, peak %s, end %s (growth %s)\n",
+		formatBytes(s.chromeStart), formatBytes(s.chromePeak), formatBytes(s.chromeEnd), formatBytes(s.chromeEnd-s.chromeStart))
+}
+
+// formatBytes formats n in MiB.
+func formatBytes(n int64) string {
+	return strconv.FormatFloat(float64(n)/(1<<20), 'f', 1, 64) + "MiB"
+}
+
+// procRoot is where the proc filesystem is mounted.
+var procRoot = "/proc"
+
+// chromeMemory returns the total resident memory of Chrome processes in
+// bytes, or 0 where /proc is not available.
+func chromeMemory() int64 {
+	entries, err := os.ReadDir(procRoot)
+	if err != nil {
+		return 0
+	}
+	var total int64
+	for _, e := range entries {
+		if _, err := strconv.Atoi(e.Name()); err != nil {
+			continue
+		}
+		b, err := os.ReadFile(filepath.Join(procRoot, e.Name(), "status"))
+		if err != nil {
+			continue
+		}
+		total += chromeRSS(string(b))
+	}
+	return total
+}
+
+// chromeRSS returns the VmRSS in bytes from a /proc/<pid>/status file if the
+// process is Chrome, and 0 otherwise.
+func chromeRSS(status string) int64 {
+	var name string
+	var rss int64
+	for _, line := range strings.Split(status, "\n") {
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		switch key {
+		case "Name":
+			name = value
+		case "VmRSS":
+			kb, _ := strconv.ParseInt(strings.TrimSuffix(value, " kB"), 10, 64)
+			rss = kb << 10
+		}
+	}
+	if !strings.Contains(strings.ToLower(name), "chrom") {
+		return 0
+	}
+	return rss
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/patipolchat/html2pdf/html2pdf"
+)
+
+func TestPercentile(t *testing.T) {
+	var sorted []time.Duration
+	for i := 1; i <= 20; i++ {
+		sorted = append(sorted, time.Duration(i)*time.Millisecond)
+	}
+	tests := []struct {
+		p    float64
+		want time.Duration
+	}{
+		{p: 50, want: 10 * time.Millisecond},
+		{p: 95, want: 19 * time.Millisecond},
+		{p: 100, want: 20 * time.Millisecond},
+		{p: 0, want: 1 * time.Millisecond},
+	}
+	for _, tt := range tests {
+		if got := percentile(sorted, tt.p); got != tt.want {
+			t.Errorf("percentile(%v) = %v, want %v", tt.p, got, tt.want)
+		}
+	}
+	if got := percentile(nil, 50); got != 0 {
+		t.Errorf("percentile(nil) = %v, want 0", got)
+	}
+}
+
+func TestChromeRSS(t *testing.T) {
+	if got := chromeRSS("Name:\tchrome\nVmRSS:\t  2048 kB\n"); got != 2<<20 {
+		t.Errorf("Expected 2MiB for chrome, got %d", got)
+	}
+	if got := chromeRSS("Name:\tbash\nVmRSS:\t  2048 kB\n"); got != 0 {
+		t.Errorf("Expected 0 for other processes, got %d", got)
+	}
+}
+
+func TestLoadCorpus(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "a.html"), []byte("<p>a</p>"), 0o644)
+	os.MkdirAll(filepath.Join(dir, "nested"), 0o755)
+	os.WriteFile(filepath.Join(dir, "nested", "b.HTML"), []byte("<p>b</p>"), 0o644)
+	os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("skip"), 0o644)
+
+	docs, err := loadCorpus(dir)
+	if err != nil {
+		t.Fatalf("loadCorpus() error = %v", err)
+	}
+	if len(docs) != 2 {
+		t.Errorf("Expected 2 documents, got %d", len(docs))
+	}
+	if _, err := loadCorpus(t.TempDir()); err == nil {
+		t.Error("Expected an error for an empty corpus")
+	}
+}
+
+func TestBench(t *testing.T) {
+	calls := 0
+	convert := func(ctx context.Context, html string, opts ...html2pdf.Option) ([]byte, error) {
+		time.Sleep(time.Millisecond)
+		calls++
+		if strings.Contains(html, "bad") {
+			return nil, errors.New("conversion failed")
+		}
+		return []byte("%PDF"), nil
+	}
+
+	stats := bench(context.Background(), benchConfig{concurrency: 1, duration: 50 * time.Millisecond},
+		[]string{"<p>good</p>", "<p>bad</p>"}, convert)
+	if stats.conversions == 0 || stats.failures == 0 {
+		t.Errorf("Expected successes and failures, got %d and %d", stats.conversions, stats.failures)
+	}
+	if stats.conversions+stats.failures > calls {
+		t.Errorf("Counted %d results for %d calls", stats.conversions+stats.failures, calls)
+	}
+
+	var out bytes.Buffer
+	stats.print(&out)
+	for _, want := range []string{"throughput:", "latency p50:", "latency p95:", "chrome rss:"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("Expected report to contain %q, got:\n%s", want, out.String())
+		}
+	}
+}
@@ -0,0 +1,34 @@
+// Command html2pdf provides operational tooling for the html2pdf library.
+//
+// Usage:
+//
+//	html2pdf bench --concurrency 8 --duration 60s --input corpus/
+//	html2pdf bench --soak --duration 1h --input corpus/
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+)
+
+// commands maps subcommand names to their implementations.
+var commands = map[string]func(ctx context.Context, args []string) error{
+	"bench": runBench,
+}
+
+func main() {
+	if len(os.Args) < 2 || commands[os.Args[1]] == nil {
+		fmt.Fprintln(os.Stderr, "usage: html2pdf <command> [flags]")
+		fmt.Fprintln(os.Stderr, "commands: bench")
+		os.Exit(2)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	if err := commands[os.Args[1]](ctx, os.Args[2:]); err != nil {
+		fmt.Fprintf(os.Stderr, "html2pdf %s: %v\n", os.Args[1], err)
+		os.Exit(1)
+	}
+}
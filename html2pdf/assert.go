@@ -0,0 +1,97 @@
+package html2pdf
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/patipolchat/html2pdf/html2pdf/internal/pdf"
+)
+
+// ErrAssertionFailed is returned when a function registered with
+// WithPostRenderAssertion rejects the rendered document.
+var ErrAssertionFailed = fmt.Errorf("post-render assertion failed")
+
+// TextIndex holds the text of a converted document.
+type TextIndex struct {
+	text       string
+	normalized string
+}
+
+// newTextIndex indexes text, collapsing runs of whitespace for matching.
+func newTextIndex(text string) TextIndex {
+	return TextIndex{text: text, normalized: strings.Join(strings.Fields(text), " ")}
+}
+
+// Text returns the text as laid out on the page, with line breaks.
+func (t TextIndex) Text() string {
+	return t.text
+}
+
+// Contains reports whether s appears in the text. Runs of whitespace in both
+// s and the text match each other, so line breaks do not matter.
+func (t TextIndex) Contains(s string) bool {
+	return strings.Contains(t.normalized, strings.Join(strings.Fields(s), " "))
+}
+
+// Count returns the number of non-overlapping occurrences of s, matching
+// whitespace like Contains.
+func (t TextIndex) Count(s string) int {
+	s = strings.Join(strings.Fields(s), " ")
+	if s == "" {
+		return 0
+	}
+	return strings.Count(t.normalized, s)
+}
+
+// WithPostRenderAssertion registers a function that inspects the text of
+// the finished PDF, extracted after all post-processing, e.g. to check that
+// the grand total appears. When it returns an error the conversion fails
+// with ErrAssertionFailed wrapping that error, so a document missing
+// critical values is never delivered. Text drawn as images, such as with
+// WithRasterizeOutput without searchable text, is not seen.
+func WithPostRenderAssertion(fn func(doc TextIndex) error) Option {
+	return func(o *options) {
+		o.assertions = append(o.assertions, fn)
+	}
+}
+
+// checkAssertions extracts the text of the finished PDF in buf and passes
+// it to each assertion.
+func checkAssertions(buf []byte, assertions []func(TextIndex) error) error {
+	if len(assertions) == 0 {
+		return nil
+	}
+	text, err := pdfText(buf)
+	if err != nil {
+		return fmt.Errorf("failed to extract text for assertions: %w", err)
+	}
+	doc := newTextIndex(text)
+	for _, fn := range assertions {
+		if err := fn(doc); err != nil {
+			return fmt.Errorf("%w: %w", ErrAssertionFailed, err)
+		}
+	}
+	return nil
+}
+
+// pdfText returns the text of every page of the PDF in buf, one line per
+// line of text and pages separated by a line break.
+func pdfText(buf []byte) (string, error) {
+	doc, err := pdf.Parse(buf)
+	if err != nil {
+		return "", err
+	}
+	pages, err := doc.Pages()
+	if err != nil {
+		return "", err
+	}
+	texts := make([]string, 0, len(pages))
+	for _, p := range pages {
+		text, err := doc.PageText(p)
+		if err != nil {
+			return "", err
+		}
+		texts = append(texts, text)
+	}
+	return strings.Join(texts, "\n"), nil
+}
@@ -0,0 +1,111 @@
+package html2pdf
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestTextIndex(t *testing.T) {
+	doc := newTextIndex("Invoice 42\n\nSubtotal\t$90.00\nGrand total\n$100.00\nThank you, thank you")
+
+	tests := []struct {
+		name string
+		s    string
+		want bool
+	}{
+		{name: "single line", s: "Invoice 42", want: true},
+		{name: "across line break", s: "Grand total $100.00", want: true},
+		{name: "extra whitespace in query", s: "Subtotal   $90.00", want: true},
+		{name: "missing", s: "$110.00", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := doc.Contains(tt.s); got != tt.want {
+				t.Errorf("Contains(%q) = %v, want %v", tt.s, got, tt.want)
+			}
+		})
+	}
+
+	if got := doc.Count("thank you"); got != 1 {
+		t.Errorf("Count() = %d, want 1 (case-sensitive)", got)
+	}
+	if got := doc.Count(" "); got != 0 {
+		t.Errorf("Count() of whitespace = %d, want 0", got)
+	}
+	if doc.Text() == "" {
+		t.Error("Text() returned empty text")
+	}
+}
+
+func TestWithPostRenderAssertion(t *testing.T) {
+	opts := getDefaultOptions()
+	check := func(TextIndex) error { return nil }
+	WithPostRenderAssertion(check)(opts)
+	WithPostRenderAssertion(check)(opts)
+	if len(opts.assertions) != 2 {
+		t.Errorf("Expected 2 assertions, got %d", len(opts.assertions))
+	}
+}
+
+func TestCheckAssertions(t *testing.T) {
+	buf := testPDFWithContents(t,
+		"BT /F1 12 Tf 72 720 Td (Invoice 42) Tj 0 -14 Td (Grand total:) Tj 90 0 Td ($100.00) Tj ET",
+		"BT /F1 12 Tf 72 720 Td (Thank you) Tj ET",
+	)
+	require := func(s string) func(TextIndex) error {
+		return func(doc TextIndex) error {
+			if !doc.Contains(s) {
+				return fmt.Errorf("%q not found", s)
+			}
+			return nil
+		}
+	}
+
+	tests := []struct {
+		name       string
+		assertions []func(TextIndex) error
+		wantErr    bool
+	}{
+		{name: "none"},
+		{name: "same line", assertions: []func(TextIndex) error{require("Grand total: $100.00")}},
+		{name: "across pages", assertions: []func(TextIndex) error{require("Invoice 42"), require("$100.00 Thank you")}},
+		{name: "missing", assertions: []func(TextIndex) error{require("Invoice 42"), require("$999.00")}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkAssertions(buf, tt.assertions)
+			if tt.wantErr != errors.Is(err, ErrAssertionFailed) || (!tt.wantErr && err != nil) {
+				t.Errorf("checkAssertions() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	if err := checkAssertions([]byte("not a pdf"), []func(TextIndex) error{require("x")}); err == nil || errors.Is(err, ErrAssertionFailed) {
+		t.Errorf("Expected an extraction error for a broken PDF, got %v", err)
+	}
+}
+
+func TestConvertHtmlToPdfWithPostRenderAssertion(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	html := `<html><body><p>Grand total:</p><p>$100.00</p><p style="display:none">$999.00</p></body></html>`
+	requireTotal := func(total string) Option {
+		return WithPostRenderAssertion(func(doc TextIndex) error {
+			if !doc.Contains("Grand total: " + total) {
+				return fmt.Errorf("grand total %s not found", total)
+			}
+			return nil
+		})
+	}
+
+	if _, err := ConvertHtmlToPdf(ctx, html, requireTotal("$100.00")); err != nil {
+		t.Fatalf("ConvertHtmlToPdf() error = %v", err)
+	}
+	if _, err := ConvertHtmlToPdf(ctx, html, requireTotal("$999.00")); !errors.Is(err, ErrAssertionFailed) {
+		t.Errorf("Expected ErrAssertionFailed for hidden text, got %v", err)
+	}
+}
@@ -0,0 +1,34 @@
+package html2pdf
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+)
+
+// WithAssetDir serves dir over a loopback HTTP server while HTML content is
+// converted and resolves the document's relative URLs against it, so images,
+// fonts and style sheets stored next to an HTML file load as they would in a
+// browser. Files outside dir are not reachable. WithBaseURL takes precedence,
+// and pages loaded from a URL are not affected.
+func WithAssetDir(dir string) Option {
+	return func(o *options) {
+		o.assetDir = dir
+	}
+}
+
+// serveAssetDir starts a loopback server for the files in dir.
+func serveAssetDir(dir string) (*assetServer, error) {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open asset directory %s: %w", dir, err)
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("failed to open asset directory %s: not a directory", dir)
+	}
+	srv, err := newAssetServer(http.FileServer(http.FS(os.DirFS(dir))))
+	if err != nil {
+		return nil, fmt.Errorf("failed to start asset server: %w", err)
+	}
+	return srv, nil
+}
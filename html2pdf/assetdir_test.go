@@ -0,0 +1,85 @@
+package html2pdf
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestServeAssetDir(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "style.css"), []byte("body { color: red; }"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	srv, err := serveAssetDir(dir)
+	if err != nil {
+		t.Fatalf("serveAssetDir() error = %v", err)
+	}
+	defer srv.Close()
+
+	tests := []struct {
+		path       string
+		wantStatus int
+		wantBody   string
+	}{
+		{path: "style.css", wantStatus: http.StatusOK, wantBody: "body { color: red; }"},
+		{path: "missing.png", wantStatus: http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			resp, err := http.Get(srv.URL(tt.path))
+			if err != nil {
+				t.Fatalf("GET %s error = %v", tt.path, err)
+			}
+			defer resp.Body.Close()
+			if resp.StatusCode != tt.wantStatus {
+				t.Errorf("Expected status %d, got %d", tt.wantStatus, resp.StatusCode)
+			}
+			body, _ := io.ReadAll(resp.Body)
+			if tt.wantBody != "" && string(body) != tt.wantBody {
+				t.Errorf("Expected body %q, got %q", tt.wantBody, body)
+			}
+		})
+	}
+}
+
+func TestServeAssetDirErrors(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "page.html")
+	if err := os.WriteFile(file, []byte("<p>Hi</p>"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, dir := range []string{filepath.Join(t.TempDir(), "missing"), file} {
+		if srv, err := serveAssetDir(dir); err == nil {
+			srv.Close()
+			t.Errorf("Expected an error for %s", dir)
+		}
+	}
+}
+
+func TestConvertHtmlToPdfWithAssetDir(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "logo.png"), testPNG(t, 20, 20), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	res, err := ConvertHtmlToResult(ctx, `<html><body><img src="logo.png"></body></html>`, WithAssetDir(dir), WithQualityReport(true))
+	if err != nil {
+		t.Fatalf("ConvertHtmlToResult() error = %v", err)
+	}
+	if len(res.Quality.MissingImages) > 0 {
+		t.Errorf("Expected images to load from the asset directory, missing %v", res.Quality.MissingImages)
+	}
+
+	if _, err := ConvertHtmlToPdf(ctx, "<p>Hi</p>", WithAssetDir(filepath.Join(dir, "missing"))); err == nil {
+		t.Error("Expected an error for a missing asset directory")
+	}
+}
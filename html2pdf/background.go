@@ -0,0 +1,86 @@
+package html2pdf
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/patipolchat/html2pdf/html2pdf/internal/pdf"
+)
+
+// FitMode controls how an image is sized to a page.
+type FitMode int
+
+const (
+	// FitStretch stretches the image to cover the page exactly.
+	FitStretch FitMode = iota
+	// FitContain scales the image to fit inside the page, centered.
+	FitContain
+	// FitCover scales the image to cover the whole page, centered and
+	// cropped at the page edges.
+	FitCover
+)
+
+// backgroundName is the resource name of the page background image.
+const backgroundName = "Html2pdfBackground"
+
+// WithPageBackground adds image (JPEG, PNG or GIF) to every page of the
+// output, e.g. a security pattern or the scan of a pre-printed form,
+// independent of the HTML content. Chrome paints pages white, so the image is
+// multiplied onto the page: it shows through blank areas while text and
+// other dark content stay on top. A nil image disables the background.
+func WithPageBackground(image []byte, fit FitMode) Option {
+	return func(o *options) {
+		o.pageBackground = image
+		o.pageBackgroundFit = fit
+	}
+}
+
+// addPageBackground draws image on every page of doc.
+func addPageBackground(image []byte, fit FitMode) func(*pdf.Document) error {
+	return func(doc *pdf.Document) error {
+		pages, err := doc.Pages()
+		if err != nil {
+			return err
+		}
+		img, w, h, err := doc.AddImage(image)
+		if err != nil {
+			return fmt.Errorf("failed to embed page background: %w", err)
+		}
+		gs := doc.Add(pdf.Dict{"Type": pdf.Name("ExtGState"), "BM": pdf.Name("Multiply")})
+		for _, p := range pages {
+			box := doc.PageBox(p, "CropBox")
+			m := backgroundMatrix(box, w, h, fit)
+			content := fmt.Sprintf("q /%s gs %s re W n %s cm /%s Do Q\n",
+				backgroundName, rectOperands(box), m, backgroundName)
+			doc.AddPageResource(p, "XObject", backgroundName, img)
+			doc.AddPageResource(p, "ExtGState", backgroundName, gs)
+			doc.AppendPageContent(p, []byte(content))
+		}
+		return nil
+	}
+}
+
+// backgroundMatrix maps the unit square an image is drawn into onto box,
+// sized by fit for an image of w by h pixels.
+func backgroundMatrix(box pdf.Box, w, h int, fit FitMode) pdf.Matrix {
+	dw, dh := box.Width(), box.Height()
+	if fit != FitStretch && w > 0 && h > 0 {
+		sx, sy := box.Width()/float64(w), box.Height()/float64(h)
+		s := math.Min(sx, sy)
+		if fit == FitCover {
+			s = math.Max(sx, sy)
+		}
+		dw, dh = float64(w)*s, float64(h)*s
+	}
+	x := box.LLX + (box.Width()-dw)/2
+	y := box.LLY + (box.Height()-dh)/2
+	return pdf.Scale(dw, dh).Then(pdf.Translate(x, y))
+}
+
+// rectOperands returns the operands of the re operator for box.
+func rectOperands(box pdf.Box) string {
+	return strings.Join([]string{
+		formatNumber(box.LLX), formatNumber(box.LLY), formatNumber(box.Width()), formatNumber(box.Height()),
+	}, " ")
+}
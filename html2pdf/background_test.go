@@ -0,0 +1,85 @@
+package html2pdf
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"strings"
+	"testing"
+
+	"github.com/patipolchat/html2pdf/html2pdf/internal/pdf"
+)
+
+// testPNG returns a w x h PNG image.
+func testPNG(t *testing.T, w, h int) []byte {
+	t.Helper()
+	img := image.NewNRGBA(image.Rect(0, 0, w, h))
+	for i := range img.Pix {
+		img.Pix[i] = 0x80
+	}
+	img.Set(0, 0, color.NRGBA{R: 0xff, A: 0xff})
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatalf("Failed to encode PNG: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestBackgroundMatrix(t *testing.T) {
+	box := pdf.Box{URX: 600, URY: 800}
+	tests := []struct {
+		name string
+		fit  FitMode
+		want pdf.Matrix
+	}{
+		{name: "stretch", fit: FitStretch, want: pdf.Matrix{600, 0, 0, 800, 0, 0}},
+		{name: "contain", fit: FitContain, want: pdf.Matrix{600, 0, 0, 300, 0, 250}},
+		{name: "cover", fit: FitCover, want: pdf.Matrix{1600, 0, 0, 800, -500, 0}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := backgroundMatrix(box, 200, 100, tt.fit); got != tt.want {
+				t.Errorf("Expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestWithPageBackground(t *testing.T) {
+	out, err := postProcess(testPDF(t, 2), newOptions([]Option{WithPageBackground(testPNG(t, 4, 2), FitContain)}))
+	if err != nil {
+		t.Fatalf("postProcess() error = %v", err)
+	}
+	doc, err := pdf.Parse(out)
+	if err != nil {
+		t.Fatalf("Failed to parse output: %v", err)
+	}
+	pages, _ := doc.Pages()
+	if len(pages) != 2 {
+		t.Fatalf("Expected 2 pages, got %d", len(pages))
+	}
+	for i, p := range pages {
+		content, err := doc.PageContent(p)
+		if err != nil {
+			t.Fatalf("Page %d: failed to read content: %v", i+1, err)
+		}
+		if !strings.Contains(string(content), "/"+backgroundName+" Do") {
+			t.Errorf("Page %d: expected background to be drawn, got %q", i+1, content)
+		}
+		resources, _ := doc.PageAttr(p, "Resources").(pdf.Dict)
+		xobjects, _ := doc.Resolve(resources["XObject"]).(pdf.Dict)
+		img, ok := doc.Resolve(xobjects[backgroundName]).(*pdf.Stream)
+		if !ok {
+			t.Fatalf("Page %d: expected background image resource", i+1)
+		}
+		if img.Dict["Width"] != 4 || img.Dict["Height"] != 2 {
+			t.Errorf("Page %d: expected 4x2 image, got %vx%v", i+1, img.Dict["Width"], img.Dict["Height"])
+		}
+	}
+
+	if _, err := postProcess(testPDF(t, 1), newOptions([]Option{WithPageBackground([]byte("not an image"), FitStretch)})); err == nil {
+		t.Error("Expected an error for invalid image data")
+	}
+}
@@ -0,0 +1,48 @@
+package html2pdf
+
+import (
+	"fmt"
+	"html"
+	"net/url"
+	"regexp"
+)
+
+// baseInsertPatterns match the tag a <base> element is inserted after: the
+// opening <head> tag, else the opening <html> tag, else the doctype.
+var baseInsertPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`(?i)<head\b[^>]*>`),
+	regexp.MustCompile(`(?i)<html\b[^>]*>`),
+	regexp.MustCompile(`(?i)<!doctype\b[^>]*>`),
+}
+
+// WithBaseURL resolves relative URLs in HTML content, e.g. images and style
+// sheets, against base, such as "https://cdn.example.com/assets/". Without it
+// they resolve against about:blank and fail to load. The base must be an
+// absolute URL, or the conversion fails with ErrInvalidURL. It has no effect
+// on pages loaded from a URL.
+func WithBaseURL(base string) Option {
+	return func(o *options) {
+		o.baseURL = base
+	}
+}
+
+// validateBaseURL reports ErrInvalidURL unless base is absolute.
+func validateBaseURL(base string) error {
+	u, err := url.Parse(base)
+	if err != nil || !u.IsAbs() {
+		return fmt.Errorf("%w: base %q", ErrInvalidURL, base)
+	}
+	return nil
+}
+
+// withBaseURL inserts a <base> element for base at the start of the document
+// head, before any element whose URLs it has to resolve.
+func withBaseURL(htmlContent, base string) string {
+	tag := `<base href="` + html.EscapeString(base) + `">`
+	for _, pattern := range baseInsertPatterns {
+		if loc := pattern.FindStringIndex(htmlContent); loc != nil {
+			return htmlContent[:loc[1]] + tag + htmlContent[loc[1]:]
+		}
+	}
+	return tag + htmlContent
+}
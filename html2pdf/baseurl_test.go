@@ -0,0 +1,70 @@
+package html2pdf
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/chromedp/chromedp"
+)
+
+func TestWithBaseURLInsertion(t *testing.T) {
+	const tag = `<base href="https://cdn.example.com/a?x=1&amp;y=2">`
+	tests := []struct {
+		name string
+		html string
+		want string
+	}{
+		{name: "head", html: `<!DOCTYPE html><html><head><title>T</title></head></html>`, want: `<!DOCTYPE html><html><head>` + tag + `<title>T</title></head></html>`},
+		{name: "head with attributes", html: `<HEAD lang="en"><link href="s.css">`, want: `<HEAD lang="en">` + tag + `<link href="s.css">`},
+		{name: "no head", html: `<!doctype html><html lang="en"><body></body></html>`, want: `<!doctype html><html lang="en">` + tag + `<body></body></html>`},
+		{name: "doctype only", html: `<!DOCTYPE html><p>x</p>`, want: `<!DOCTYPE html>` + tag + `<p>x</p>`},
+		{name: "fragment", html: `<img src="logo.png">`, want: tag + `<img src="logo.png">`},
+		{name: "header is not head", html: `<header>x</header>`, want: tag + `<header>x</header>`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := withBaseURL(tt.html, "https://cdn.example.com/a?x=1&y=2"); got != tt.want {
+				t.Errorf("Expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestConvertHtmlToPdfWithInvalidBaseURL(t *testing.T) {
+	for _, base := range []string{"assets/", "//cdn.example.com/", "http://[::1"} {
+		_, err := ConvertHtmlToPdf(context.Background(), "<p>x</p>", WithBaseURL(base))
+		if !errors.Is(err, ErrInvalidURL) {
+			t.Errorf("WithBaseURL(%q): expected ErrInvalidURL, got %v", base, err)
+		}
+	}
+}
+
+func TestConvertHtmlToPdfWithBaseURL(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/assets/style.css" {
+			w.Header().Set("Content-Type", "text/css")
+			w.Write([]byte(`h1 { color: rgb(255, 0, 0); }`))
+			return
+		}
+		http.NotFound(w, r)
+	}))
+	defer srv.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	var color string
+	_, err := ConvertHtmlToPdf(ctx, `<html><head><link rel="stylesheet" href="style.css"></head><body><h1>Styled</h1></body></html>`,
+		WithBaseURL(srv.URL+"/assets/"),
+		WithPostRenderActions(chromedp.Evaluate(`getComputedStyle(document.querySelector("h1")).color`, &color)))
+	if err != nil {
+		t.Fatalf("ConvertHtmlToPdf() error = %v", err)
+	}
+	if color != "rgb(255, 0, 0)" {
+		t.Errorf("Expected the style sheet to load from the base URL, got color %q", color)
+	}
+}
@@ -0,0 +1,131 @@
+package html2pdf
+
+import (
+	"context"
+	"fmt"
+	"html/template"
+	"runtime"
+	"sync"
+
+	"github.com/chromedp/chromedp"
+)
+
+// BatchResult is the outcome of one record of BatchRender.
+type BatchResult struct {
+	// PDF is the document of the record; it is nil when Err is set.
+	PDF []byte
+	// Err is why the record could not be rendered.
+	Err error
+}
+
+// WithBatchConcurrency sets how many records BatchRender converts at once.
+// It defaults to what the container's resource limits allow, or the number
+// of CPUs when there are none.
+func WithBatchConcurrency(n int) Option {
+	return func(o *options) {
+		o.batchConcurrency = n
+	}
+}
+
+// BatchRender executes tmpl once per record and converts each output to PDF,
+// e.g. to generate 500 personalized letters. Records are converted
+// concurrently in tabs of a single browser, see WithBatchConcurrency. Unlike
+// MailMerge, a failed record does not stop the others: the result at each
+// index holds the record's PDF or its error. The returned error is only set
+// when the browser cannot be started.
+func BatchRender(ctx context.Context, tmpl *template.Template, records []interface{}, opts ...Option) ([]BatchResult, error) {
+	options := newOptions(opts)
+	validator, err := newDataValidator(options.dataSchema)
+	if err != nil {
+		return nil, err
+	}
+
+	shared := opts[:len(opts):len(opts)]
+	if options.fixtureDir == "" || options.fixtureMode != FixtureReplay {
+		browser, cancel, err := startBrowser(ctx, options)
+		if err != nil {
+			return nil, err
+		}
+		defer cancel()
+		shared = append(shared, withBrowser(browser))
+	}
+
+	workers := options.batchConcurrency
+	if workers <= 0 {
+		if limits := DetectResourceLimits(); limits.Limited() {
+			workers = limits.Concurrency()
+		} else {
+			workers = runtime.NumCPU()
+		}
+	}
+	results := make([]BatchResult, len(records))
+	sem := make(chan struct{}, workers)
+	var wg sync.WaitGroup
+	for i, record := range records {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			pdf, err := renderRecord(ctx, tmpl, record, validator, options, shared)
+			if err != nil {
+				err = fmt.Errorf("record %d: %w", i+1, err)
+			}
+			results[i] = BatchResult{PDF: pdf, Err: err}
+		}()
+	}
+	wg.Wait()
+	return results, nil
+}
+
+// renderRecord validates record, executes tmpl with it and converts the
+// output with opts.
+func renderRecord(ctx context.Context, tmpl *template.Template, record interface{}, validator *dataValidator, options *options, opts []Option) ([]byte, error) {
+	if err := validator.validate(record); err != nil {
+		return nil, err
+	}
+	html, err := executeTemplate(tmpl, record, options)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render template: %w", err)
+	}
+	return ConvertHtmlToPdf(ctx, html, opts...)
+}
+
+// withBrowser makes conversions open a tab in the browser of ctx, as
+// returned by startBrowser, instead of launching their own.
+func withBrowser(ctx context.Context) Option {
+	return func(o *options) {
+		o.browser = ctx
+	}
+}
+
+// startBrowser launches Chrome with the allocator options of options and
+// returns a context for opening tabs in it, valid until cancel is called.
+func startBrowser(ctx context.Context, options *options) (context.Context, context.CancelFunc, error) {
+	allocCtx, cancelAlloc := chromedp.NewExecAllocator(ctx, allocatorOptions(options)...)
+	browser, cancelBrowser := chromedp.NewContext(allocCtx, chromedp.WithDebugf(conversionLogger(options.logger)))
+	cancel := func() {
+		cancelBrowser()
+		cancelAlloc()
+	}
+	if err := chromedp.Run(browser); err != nil {
+		cancel()
+		return nil, nil, fmt.Errorf("failed to start browser: %w", err)
+	}
+	return browser, cancel, nil
+}
+
+// sharedBrowser is a conversion context that opens its tab in an already
+// running browser. Cancellation, deadline and values come from the
+// conversion's own context; only the browser is looked up in the other.
+type sharedBrowser struct {
+	context.Context
+	browser context.Context
+}
+
+func (c sharedBrowser) Value(key interface{}) interface{} {
+	if v := c.Context.Value(key); v != nil {
+		return v
+	}
+	return c.browser.Value(key)
+}
@@ -0,0 +1,67 @@
+package html2pdf
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"html/template"
+	"testing"
+	"time"
+)
+
+func TestBatchRenderFromFixtures(t *testing.T) {
+	dir := t.TempDir()
+	tmpl := template.Must(template.New("letter").Option("missingkey=error").Parse(`<p>Dear {{.name}}</p>`))
+	for _, name := range []string{"Ada", "Grace"} {
+		html := "<p>Dear " + name + "</p>"
+		opts := newOptions([]Option{WithFixtures(dir, FixtureRecord)})
+		if err := recordFixture(document{html: html}, opts, newResult([]byte("%PDF-1.4 "+name))); err != nil {
+			t.Fatalf("recordFixture() error = %v", err)
+		}
+	}
+
+	records := []interface{}{
+		map[string]interface{}{"name": "Ada"},
+		map[string]interface{}{"id": 2},
+		map[string]interface{}{"name": "Grace"},
+		map[string]interface{}{"name": "Linus"},
+	}
+	results, err := BatchRender(context.Background(), tmpl, records, WithFixtures(dir, FixtureReplay), WithBatchConcurrency(2))
+	if err != nil {
+		t.Fatalf("BatchRender() error = %v", err)
+	}
+	if len(results) != len(records) {
+		t.Fatalf("Expected %d results, got %d", len(records), len(results))
+	}
+	if !bytes.Equal(results[0].PDF, []byte("%PDF-1.4 Ada")) || results[0].Err != nil {
+		t.Errorf("Expected the first letter, got %+v", results[0])
+	}
+	if results[1].Err == nil {
+		t.Error("Expected an error for a record missing a template field")
+	}
+	if !bytes.Equal(results[2].PDF, []byte("%PDF-1.4 Grace")) || results[2].Err != nil {
+		t.Errorf("Expected the third letter, got %+v", results[2])
+	}
+	if !errors.Is(results[3].Err, ErrFixtureNotFound) {
+		t.Errorf("Expected ErrFixtureNotFound for the last record, got %v", results[3].Err)
+	}
+}
+
+func TestBatchRender(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	tmpl := template.Must(template.New("letter").Parse(`<h1>Dear {{.}}</h1>`))
+	records := []interface{}{"Ada", "Grace", "Linus", "Margaret", "Ken"}
+	results, err := BatchRender(ctx, tmpl, records, WithBatchConcurrency(3))
+	if err != nil {
+		t.Fatalf("BatchRender() error = %v", err)
+	}
+	for i, r := range results {
+		if r.Err != nil {
+			t.Errorf("Record %d: %v", i+1, r.Err)
+		} else if !bytes.HasPrefix(r.PDF, []byte("%PDF")) {
+			t.Errorf("Record %d is not a PDF", i+1)
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package html2pdf
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/chromedp/chromedp"
+)
+
+// ErrBackendUnavailable is returned while the circuit breaker enabled with
+// WithCircuitBreaker is open.
+var ErrBackendUnavailable = fmt.Errorf("rendering backend unavailable")
+
+// WithCircuitBreaker makes conversions fail fast with ErrBackendUnavailable
+// for cooldown after threshold consecutive conversions failed because Chrome
+// could not be launched or the connection to it broke. After the cooldown a
+// single conversion is let through; its success closes the breaker and its
+// failure opens it again. The breaker is shared by all conversions using
+// this option. Errors of the document, the options or the caller's context,
+// such as ErrCPUBudgetExceeded or ErrInvalidPageRange, are not counted.
+func WithCircuitBreaker(threshold int, cooldown time.Duration) Option {
+	return func(o *options) {
+		o.breakerThreshold = threshold
+		o.breakerCooldown = cooldown
+	}
+}
+
+// circuitBreaker tracks consecutive backend failures.
+type circuitBreaker struct {
+	mu        sync.Mutex
+	failures  int
+	openUntil time.Time
+	probing   bool
+	now       func() time.Time
+}
+
+// backend is the breaker shared by all calls with WithCircuitBreaker enabled.
+var backend = &circuitBreaker{now: time.Now}
+
+// allow reports whether a conversion may run. Once the cooldown has passed,
+// only one probing conversion is allowed until its outcome is recorded;
+// probe reports whether the caller is that conversion.
+func (b *circuitBreaker) allow() (probe bool, err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.openUntil.IsZero() {
+		return false, nil
+	}
+	if b.probing || b.now().Before(b.openUntil) {
+		return false, ErrBackendUnavailable
+	}
+	b.probing = true
+	return true, nil
+}
+
+// record updates the breaker with the outcome of a conversion allowed by allow.
+func (b *circuitBreaker) record(ctx context.Context, probe bool, err error, threshold int, cooldown time.Duration) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if probe {
+		b.probing = false
+	}
+	switch {
+	case err == nil:
+		b.failures = 0
+		b.openUntil = time.Time{}
+	case !backendFailure(ctx, err):
+		// Not the backend's fault; a probe will be retried by the next call.
+	default:
+		b.failures++
+		if probe || b.failures >= threshold {
+			b.openUntil = b.now().Add(cooldown)
+		}
+	}
+}
+
+// backendError marks a failure of Chrome itself: it could not be launched,
+// or the connection to it broke during the conversion.
+type backendError struct {
+	err error
+}
+
+func (e *backendError) Error() string { return e.err.Error() }
+
+func (e *backendError) Unwrap() error { return e.err }
+
+// backendFailure reports whether err indicates a problem with Chrome rather
+// than with the document, the options or the caller's context. Only errors
+// marked as backendError qualify.
+func backendFailure(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	var be *backendError
+	return errors.As(err, &be)
+}
+
+// transportFailure reports whether err comes from the connection to Chrome
+// rather than from a command it executed.
+func transportFailure(err error) bool {
+	var netErr net.Error
+	return errors.Is(err, chromedp.ErrChannelClosed) || errors.Is(err, chromedp.ErrInvalidWebsocketMessage) ||
+		errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) || errors.As(err, &netErr)
+}
@@ -0,0 +1,121 @@
+package html2pdf
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/chromedp/chromedp"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	now := time.Unix(0, 0)
+	b := &circuitBreaker{now: func() time.Time { return now }}
+	ctx := context.Background()
+	failure := fmt.Errorf("failed to convert: %w", &backendError{fmt.Errorf("chrome failed to start")})
+
+	for i := 0; i < 2; i++ {
+		if _, err := b.allow(); err != nil {
+			t.Fatalf("allow() before threshold = %v", err)
+		}
+		b.record(ctx, false, failure, 3, time.Minute)
+	}
+	// Failures caused by the document do not count.
+	b.record(ctx, false, fmt.Errorf("wrapped: %w", ErrCPUBudgetExceeded), 3, time.Minute)
+	b.record(ctx, false, fmt.Errorf("wrapped: %w", ErrAssertionFailed), 3, time.Minute)
+	if _, err := b.allow(); err != nil {
+		t.Fatalf("allow() after document failure = %v", err)
+	}
+	b.record(ctx, false, failure, 3, time.Minute)
+
+	if _, err := b.allow(); !errors.Is(err, ErrBackendUnavailable) {
+		t.Fatalf("Expected ErrBackendUnavailable once open, got %v", err)
+	}
+
+	// After the cooldown a single probe is allowed.
+	now = now.Add(time.Minute)
+	probe, err := b.allow()
+	if err != nil || !probe {
+		t.Fatalf("allow() probe = %v, %v", probe, err)
+	}
+	if _, err := b.allow(); !errors.Is(err, ErrBackendUnavailable) {
+		t.Fatalf("Expected a second concurrent probe to be rejected, got %v", err)
+	}
+
+	// A conversion started before the breaker opened does not end the probe.
+	b.record(ctx, false, failure, 3, time.Minute)
+	if !b.probing {
+		t.Fatal("Expected the probe to still be running")
+	}
+
+	// A failed probe reopens the breaker immediately.
+	b.record(ctx, true, failure, 3, time.Minute)
+	if _, err := b.allow(); !errors.Is(err, ErrBackendUnavailable) {
+		t.Fatalf("Expected breaker to reopen after failed probe, got %v", err)
+	}
+
+	// A successful probe closes it.
+	now = now.Add(time.Minute)
+	if probe, err = b.allow(); err != nil {
+		t.Fatalf("allow() probe = %v", err)
+	}
+	b.record(ctx, probe, nil, 3, time.Minute)
+	if _, err := b.allow(); err != nil || b.failures != 0 {
+		t.Errorf("Expected closed breaker after success, got %v with %d failures", err, b.failures)
+	}
+}
+
+func TestBackendFailure(t *testing.T) {
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		err  error
+		want bool
+	}{
+		{name: "launch failure", ctx: context.Background(), err: fmt.Errorf("x: %w", &backendError{fmt.Errorf("exec: not found")}), want: true},
+		{name: "lost connection", ctx: context.Background(), err: &backendError{chromedp.ErrChannelClosed}, want: true},
+		{name: "navigation failure", ctx: context.Background(), err: fmt.Errorf("navigation to https://bad.example failed: net::ERR_NAME_NOT_RESOLVED"), want: false},
+		{name: "invalid page range", ctx: context.Background(), err: fmt.Errorf("x: %w", ErrInvalidPageRange), want: false},
+		{name: "invalid paper size", ctx: context.Background(), err: ErrInvalidPaperSize, want: false},
+		{name: "script exception", ctx: context.Background(), err: fmt.Errorf("exception \"SyntaxError: '##' is not a valid selector\""), want: false},
+		{name: "canceled context", ctx: canceled, err: context.Canceled, want: false},
+		{name: "overflowing content", ctx: context.Background(), err: fmt.Errorf("x: %w", ErrContentOverflow), want: false},
+		{name: "content too large", ctx: context.Background(), err: ErrContentTooLarge, want: false},
+		{name: "failed assertion", ctx: context.Background(), err: fmt.Errorf("x: %w", ErrAssertionFailed), want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := backendFailure(tt.ctx, tt.err); got != tt.want {
+				t.Errorf("backendFailure() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTransportFailure(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "channel closed", err: fmt.Errorf("x: %w", chromedp.ErrChannelClosed), want: true},
+		{name: "connection reset", err: &net.OpError{Op: "read", Err: errors.New("connection reset by peer")}, want: true},
+		{name: "unexpected eof", err: io.ErrUnexpectedEOF, want: true},
+		{name: "protocol error", err: errors.New("Printing failed (-32000)")},
+		{name: "document error", err: ErrContentOverflow},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := transportFailure(tt.err); got != tt.want {
+				t.Errorf("transportFailure() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
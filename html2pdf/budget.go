@@ -0,0 +1,71 @@
+package html2pdf
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/chromedp/cdproto/performance"
+	"github.com/chromedp/chromedp"
+)
+
+// cpuBudgetInterval is how often the CPU watchdog samples page metrics.
+const cpuBudgetInterval = 100 * time.Millisecond
+
+// startCPUWatchdog enables the Performance domain and starts a goroutine that
+// calls exceeded once the page has spent more than budget running scripts.
+// The watchdog stops when stop is closed or the context is done.
+func startCPUWatchdog(budget time.Duration, stop <-chan struct{}, exceeded func()) chromedp.ActionFunc {
+	return func(ctx context.Context) error {
+		if err := performance.Enable().WithTimeDomain(performance.EnableTimeDomainThreadTicks).Do(ctx); err != nil {
+			if err := performance.Enable().Do(ctx); err != nil {
+				return err
+			}
+		}
+		go watchCPUBudget(ctx, budget, stop, exceeded)
+		return nil
+	}
+}
+
+// watchCPUBudget polls the ScriptDuration metric until the budget is used up.
+// A renderer that is too busy to answer within the remaining budget is
+// treated as running a hot loop.
+func watchCPUBudget(ctx context.Context, budget time.Duration, stop <-chan struct{}, exceeded func()) {
+	ticker := time.NewTicker(cpuBudgetInterval)
+	defer ticker.Stop()
+
+	var used time.Duration
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+
+		callCtx, cancel := context.WithTimeout(ctx, budget-used)
+		metrics, err := performance.GetMetrics().Do(callCtx)
+		cancel()
+		select {
+		case <-stop:
+			return
+		default:
+		}
+		if err != nil {
+			if errors.Is(err, context.DeadlineExceeded) && ctx.Err() == nil {
+				exceeded()
+			}
+			return
+		}
+		for _, m := range metrics {
+			if m.Name == "ScriptDuration" {
+				used = time.Duration(m.Value * float64(time.Second))
+			}
+		}
+		if used >= budget {
+			exceeded()
+			return
+		}
+	}
+}
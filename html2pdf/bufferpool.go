@@ -0,0 +1,105 @@
+package html2pdf
+
+import (
+	"sync"
+
+	"github.com/patipolchat/html2pdf/html2pdf/internal/pdf"
+)
+
+// maxPooledBuffer is the capacity above which buffers are left to the
+// garbage collector instead of being pooled, so one huge document does not
+// pin its memory for good.
+const maxPooledBuffer = 256 << 20
+
+// pdfBuffers holds PDF buffers of earlier conversions for reuse.
+var pdfBuffers sync.Pool
+
+// WithBufferPool reuses the memory of PDF buffers across conversions instead
+// of allocating it anew, to ease garbage collection in services rendering
+// many large documents. Chrome then hands over the PDF as a stream, see
+// WithStreamTransfer, which is read into a pooled buffer, and post-processing
+// steps write into pooled buffers and recycle the ones they replace. PDFs
+// passed in by the caller, e.g. with WithPrependPDF, are never recycled.
+// Memory of the final PDF is reused once the caller hands it back with
+// Result.Release; ConvertHtmlToPdfWriter does so after writing.
+func WithBufferPool(enabled bool) Option {
+	return func(o *options) {
+		o.bufferPool = enabled
+	}
+}
+
+// getPDFBuffer returns an empty buffer from the pool, or nil when it is empty.
+func getPDFBuffer() []byte {
+	if b, ok := pdfBuffers.Get().(*[]byte); ok {
+		return (*b)[:0]
+	}
+	return nil
+}
+
+// putPDFBuffer adds the memory of b to the pool. b must not be used afterwards.
+func putPDFBuffer(b []byte) {
+	if cap(b) == 0 || cap(b) > maxPooledBuffer {
+		return
+	}
+	b = b[:0]
+	pdfBuffers.Put(&b)
+}
+
+// pdfBuffer returns the buffer to print into: a pooled one with
+// WithBufferPool, otherwise nil.
+func (o *options) pdfBuffer() []byte {
+	if !o.bufferPool {
+		return nil
+	}
+	return getPDFBuffer()
+}
+
+// own records buf as the buffer the conversion printed or edited into, whose
+// memory it may return to the pool once buf is replaced.
+func (o *options) own(buf []byte) {
+	o.ownedPDF = nil
+	if cap(buf) > 0 {
+		o.ownedPDF = &buf[:1][0]
+	}
+}
+
+// owns reports whether buf is the buffer recorded by own. Buffers built
+// otherwise, e.g. by merging or rasterizing, or passed in by the caller, are
+// never returned to the pool.
+func (o *options) owns(buf []byte) bool {
+	return o.ownedPDF != nil && cap(buf) > 0 && &buf[:1][0] == o.ownedPDF
+}
+
+// editPDF applies fn to buf like the editPDF function. With WithBufferPool,
+// the result is written into a pooled buffer, and buf is returned to the pool
+// if the conversion owns it.
+func (o *options) editPDF(buf []byte, fn func(*pdf.Document) error) ([]byte, error) {
+	if !o.bufferPool {
+		return editPDF(buf, fn)
+	}
+	doc, err := pdf.Parse(buf)
+	if err != nil {
+		return nil, err
+	}
+	if err := fn(doc); err != nil {
+		return nil, err
+	}
+	out := doc.AppendBytes(getPDFBuffer())
+	if o.owns(buf) {
+		putPDFBuffer(buf)
+	}
+	o.own(out)
+	return out, nil
+}
+
+// Release hands the memory of PDF back for reuse by later conversions made
+// with WithBufferPool and clears PDF. Call it once the document has been
+// written out; slices of PDF must not be used afterwards. It does nothing for
+// results converted without WithBufferPool or shared by WithDeduplication.
+func (r *Result) Release() {
+	if r.pooled {
+		putPDFBuffer(r.PDF)
+	}
+	r.PDF = nil
+	r.pooled = false
+}
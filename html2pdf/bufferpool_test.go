@@ -0,0 +1,83 @@
+package html2pdf
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestEditPDFWithBufferPool(t *testing.T) {
+	buf := testPDF(t, 3)
+	want, err := newOptions(nil).editPDF(append([]byte(nil), buf...), trimBlankPages)
+	if err != nil {
+		t.Fatalf("editPDF() error = %v", err)
+	}
+
+	pooled := newOptions([]Option{WithBufferPool(true)})
+	for i := 0; i < 3; i++ {
+		got, err := pooled.editPDF(append([]byte(nil), buf...), trimBlankPages)
+		if err != nil {
+			t.Fatalf("editPDF() error = %v", err)
+		}
+		if !bytes.Equal(got, want) {
+			t.Errorf("Run %d: expected the same document with the buffer pool", i+1)
+		}
+		putPDFBuffer(got)
+	}
+}
+
+func TestEditPDFOwnership(t *testing.T) {
+	opts := newOptions([]Option{WithBufferPool(true)})
+	caller := testPDF(t, 2)
+	if opts.owns(caller) {
+		t.Fatal("Expected a caller's buffer not to be owned")
+	}
+	out, err := opts.editPDF(caller, trimBlankPages)
+	if err != nil {
+		t.Fatalf("editPDF() error = %v", err)
+	}
+	if b := getPDFBuffer(); cap(b) > 0 && &b[:1][0] == &caller[0] {
+		t.Error("Expected the caller's buffer not to be returned to the pool")
+	}
+	if !opts.owns(out) {
+		t.Error("Expected the edited buffer to be owned")
+	}
+	if opts.owns(out[1:]) || opts.owns(nil) {
+		t.Error("Expected only the edited buffer to be owned")
+	}
+}
+
+func TestBufferPoolOptions(t *testing.T) {
+	if newOptions(nil).pdfBuffer() != nil {
+		t.Error("Expected no buffer without WithBufferPool")
+	}
+	if mode := newOptions([]Option{WithBufferPool(true)}).printToPDFParams().TransferMode; mode == "" {
+		t.Error("Expected WithBufferPool to use stream transfer")
+	}
+}
+
+func TestResultRelease(t *testing.T) {
+	tests := []struct {
+		name   string
+		pooled bool
+	}{
+		{name: "pooled", pooled: true},
+		{name: "not pooled"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := newResult(testPDF(t, 1))
+			res.pooled = tt.pooled
+			res.Release()
+			if res.PDF != nil {
+				t.Error("Expected PDF to be cleared")
+			}
+			res.Release()
+		})
+	}
+
+	// Buffers beyond the limit are not pooled.
+	putPDFBuffer(make([]byte, 0, maxPooledBuffer+1))
+	if b := getPDFBuffer(); cap(b) > maxPooledBuffer {
+		t.Errorf("Expected oversized buffers to be dropped, got capacity %d", cap(b))
+	}
+}
@@ -0,0 +1,12 @@
+package html2pdf
+
+// WithCanvasScale renders the page at a device pixel ratio of factor, so
+// scripts that size <canvas> backing stores by window.devicePixelRatio, such
+// as Chart.js, draw at a resolution that stays sharp in print. Canvas is
+// printed as a bitmap; 2 or 3 suits A4 print density. A factor of 1 or less
+// keeps Chrome's default.
+func WithCanvasScale(factor float64) Option {
+	return func(o *options) {
+		o.canvasScale = factor
+	}
+}
@@ -0,0 +1,51 @@
+package html2pdf
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/chromedp/chromedp"
+)
+
+func TestWithCanvasScale(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []Option
+		want float64
+	}{
+		{name: "default", want: 0},
+		{name: "1x", opts: []Option{WithCanvasScale(1)}, want: 0},
+		{name: "3x", opts: []Option{WithCanvasScale(3)}, want: 3},
+		{name: "highest wins", opts: []Option{WithCanvasScale(1.5), WithImageResolutionPolicy(ImageResolutionPrint)}, want: 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := newOptions(tt.opts).deviceScaleFactor(); got != tt.want {
+				t.Errorf("Expected device scale factor %g, got %g", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestConvertHtmlToPdfWithCanvasScale(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	// The canvas is sized like Chart.js does for high-DPI displays.
+	html := `<html><body><canvas id="c" style="width:100px;height:50px"></canvas><script>
+	const c = document.getElementById("c");
+	c.width = 100 * window.devicePixelRatio;
+	c.height = 50 * window.devicePixelRatio;
+	</script></body></html>`
+	var width int
+	_, err := ConvertHtmlToPdf(ctx, html,
+		WithCanvasScale(3),
+		WithPostRenderActions(chromedp.Evaluate(`document.getElementById("c").width`, &width)))
+	if err != nil {
+		t.Fatalf("ConvertHtmlToPdf() error = %v", err)
+	}
+	if width != 300 {
+		t.Errorf("Expected a 300px canvas backing store, got %d", width)
+	}
+}
@@ -0,0 +1,208 @@
+package html2pdf
+
+import (
+	"fmt"
+	"html/template"
+	"math"
+	"reflect"
+	"strings"
+)
+
+// Chart geometry in SVG user units. Charts scale to the width of their
+// container, keeping this aspect ratio.
+const (
+	chartWidth   = 480
+	chartHeight  = 240
+	chartPadding = 30
+	chartFont    = 11
+)
+
+// chartPalette colors chart series and pie slices in order.
+var chartPalette = []string{"#4e79a7", "#f28e2b", "#e15759", "#76b7b2", "#59a14f", "#edc948", "#b07aa1", "#ff9da7", "#9c755f", "#bab0ac"}
+
+// chartPoint is one labeled value of a chart.
+type chartPoint struct {
+	label string
+	value float64
+}
+
+// chartFuncs returns the chart template functions.
+func chartFuncs() map[string]interface{} {
+	return map[string]interface{}{
+		"barChart":  chartFunc(barChartSVG),
+		"lineChart": chartFunc(lineChartSVG),
+		"pieChart":  chartFunc(pieChartSVG),
+	}
+}
+
+// chartFunc adapts a chart renderer to a template function taking the items
+// and the names of their label and value fields.
+func chartFunc(render func([]chartPoint) (string, error)) func(items interface{}, labelField, valueField string) (template.HTML, error) {
+	return func(items interface{}, labelField, valueField string) (template.HTML, error) {
+		points, err := chartPoints(items, labelField, valueField)
+		if err != nil {
+			return "", err
+		}
+		svg, err := render(points)
+		if err != nil {
+			return "", err
+		}
+		return template.HTML(svg), nil
+	}
+}
+
+// chartPoints reads the label and value fields of each item of a slice.
+func chartPoints(items interface{}, labelField, valueField string) ([]chartPoint, error) {
+	rv := reflect.ValueOf(items)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return nil, fmt.Errorf("cannot chart %T", items)
+	}
+	points := make([]chartPoint, rv.Len())
+	for i := range points {
+		label, err := fieldValue(rv.Index(i), labelField)
+		if err != nil {
+			return nil, fmt.Errorf("item %d: %w", i+1, err)
+		}
+		value, err := fieldValue(rv.Index(i), valueField)
+		if err != nil {
+			return nil, fmt.Errorf("item %d: %w", i+1, err)
+		}
+		d, err := toDecimal(value.Interface())
+		if err != nil {
+			return nil, fmt.Errorf("item %d: %w", i+1, err)
+		}
+		points[i].label = fmt.Sprint(label.Interface())
+		points[i].value, _ = d.rat.Float64()
+	}
+	return points, nil
+}
+
+// chartCoord formats a coordinate with at most two decimals.
+func chartCoord(v float64) string {
+	return formatNumber(math.Round(v*100) / 100)
+}
+
+// startChart begins an SVG document with the chart's text style.
+func startChart(b *strings.Builder) {
+	fmt.Fprintf(b, `<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 %d %d" width="100%%" font-family="sans-serif" font-size="%d">`, chartWidth, chartHeight, chartFont)
+}
+
+// chartText writes a text element.
+func chartText(b *strings.Builder, x, y float64, anchor, text string) {
+	fmt.Fprintf(b, `<text x="%s" y="%s" text-anchor="%s">%s</text>`, chartCoord(x), chartCoord(y), anchor, template.HTMLEscapeString(text))
+}
+
+// valueRange returns the range the value axis covers, always including zero.
+func valueRange(points []chartPoint) (lo, hi float64) {
+	for _, p := range points {
+		lo = math.Min(lo, p.value)
+		hi = math.Max(hi, p.value)
+	}
+	if lo == hi {
+		hi = lo + 1
+	}
+	return lo, hi
+}
+
+// plotArea draws the value axis of a bar or line chart and returns a
+// function mapping values to y coordinates.
+func plotArea(b *strings.Builder, points []chartPoint) func(float64) float64 {
+	lo, hi := valueRange(points)
+	top, bottom := float64(chartPadding)/2, float64(chartHeight-chartPadding)
+	y := func(v float64) float64 {
+		return bottom - (v-lo)/(hi-lo)*(bottom-top)
+	}
+	left := float64(chartPadding)
+	fmt.Fprintf(b, `<line x1="%s" y1="%s" x2="%s" y2="%s" stroke="#999"/>`, chartCoord(left), chartCoord(y(0)), chartCoord(chartWidth), chartCoord(y(0)))
+	chartText(b, left-4, y(hi)+4, "end", formatNumber(hi))
+	if lo < 0 {
+		chartText(b, left-4, y(lo)+4, "end", formatNumber(lo))
+	}
+	chartText(b, left-4, y(0)+4, "end", "0")
+	return y
+}
+
+// barChartSVG draws one bar per point.
+func barChartSVG(points []chartPoint) (string, error) {
+	var b strings.Builder
+	startChart(&b)
+	y := plotArea(&b, points)
+	if len(points) > 0 {
+		slot := float64(chartWidth-chartPadding) / float64(len(points))
+		for i, p := range points {
+			x := float64(chartPadding) + float64(i)*slot
+			y1, y2 := math.Min(y(0), y(p.value)), math.Max(y(0), y(p.value))
+			fmt.Fprintf(&b, `<rect x="%s" y="%s" width="%s" height="%s" fill="%s"/>`,
+				chartCoord(x+slot*0.1), chartCoord(y1), chartCoord(slot*0.8), chartCoord(y2-y1), chartPalette[0])
+			chartText(&b, x+slot/2, chartHeight-chartPadding/2+4, "middle", p.label)
+		}
+	}
+	b.WriteString("</svg>")
+	return b.String(), nil
+}
+
+// lineChartSVG draws a line through the points, left to right.
+func lineChartSVG(points []chartPoint) (string, error) {
+	var b strings.Builder
+	startChart(&b)
+	y := plotArea(&b, points)
+	if len(points) > 0 {
+		slot := float64(chartWidth-chartPadding) / float64(len(points))
+		xs := make([]float64, len(points))
+		coords := make([]string, len(points))
+		for i, p := range points {
+			xs[i] = float64(chartPadding) + (float64(i)+0.5)*slot
+			coords[i] = chartCoord(xs[i]) + "," + chartCoord(y(p.value))
+			chartText(&b, xs[i], chartHeight-chartPadding/2+4, "middle", p.label)
+		}
+		fmt.Fprintf(&b, `<polyline points="%s" fill="none" stroke="%s" stroke-width="2"/>`, strings.Join(coords, " "), chartPalette[0])
+		for i, p := range points {
+			fmt.Fprintf(&b, `<circle cx="%s" cy="%s" r="3" fill="%s"/>`, chartCoord(xs[i]), chartCoord(y(p.value)), chartPalette[0])
+		}
+	}
+	b.WriteString("</svg>")
+	return b.String(), nil
+}
+
+// pieChartSVG draws one slice per point, clockwise from the top, with a
+// legend on the right.
+func pieChartSVG(points []chartPoint) (string, error) {
+	var total float64
+	for _, p := range points {
+		if p.value < 0 {
+			return "", fmt.Errorf("pie chart value %s for %q is negative", formatNumber(p.value), p.label)
+		}
+		total += p.value
+	}
+
+	var b strings.Builder
+	startChart(&b)
+	r := float64(chartHeight)/2 - chartPadding/2
+	cx, cy := float64(chartHeight)/2, float64(chartHeight)/2
+	angle := -math.Pi / 2
+	for i, p := range points {
+		color := chartPalette[i%len(chartPalette)]
+		switch {
+		case total == 0 || p.value == 0:
+		case p.value == total:
+			fmt.Fprintf(&b, `<circle cx="%s" cy="%s" r="%s" fill="%s"/>`, chartCoord(cx), chartCoord(cy), chartCoord(r), color)
+		default:
+			sweep := p.value / total * 2 * math.Pi
+			large := 0
+			if sweep > math.Pi {
+				large = 1
+			}
+			fmt.Fprintf(&b, `<path d="M%s %s L%s %s A%s %s 0 %d 1 %s %s Z" fill="%s"/>`,
+				chartCoord(cx), chartCoord(cy),
+				chartCoord(cx+r*math.Cos(angle)), chartCoord(cy+r*math.Sin(angle)),
+				chartCoord(r), chartCoord(r), large,
+				chartCoord(cx+r*math.Cos(angle+sweep)), chartCoord(cy+r*math.Sin(angle+sweep)), color)
+			angle += sweep
+		}
+		ly := float64(chartPadding + i*(chartFont+6))
+		fmt.Fprintf(&b, `<rect x="%d" y="%s" width="%d" height="%d" fill="%s"/>`, chartHeight+chartPadding, chartCoord(ly-chartFont+1), chartFont, chartFont, color)
+		chartText(&b, float64(chartHeight+chartPadding+chartFont+6), ly, "start", p.label)
+	}
+	b.WriteString("</svg>")
+	return b.String(), nil
+}
@@ -0,0 +1,97 @@
+package html2pdf
+
+import (
+	"encoding/xml"
+	"html/template"
+	"strings"
+	"testing"
+)
+
+func TestChartFuncs(t *testing.T) {
+	type sale struct {
+		Month string
+		Total float64
+	}
+	data := map[string]interface{}{
+		"Sales":  []sale{{"Jan", 120}, {"Feb", 80.5}, {"Mar", -20}},
+		"Shares": []map[string]interface{}{{"Name": "A & B", "Pct": "75"}, {"Name": "C", "Pct": 25}},
+		"Whole":  []map[string]interface{}{{"Name": "All", "Pct": 1}},
+	}
+
+	tests := []struct {
+		name     string
+		tmpl     string
+		contains []string
+		count    map[string]int
+		wantErr  string
+	}{
+		{
+			name:     "bar",
+			tmpl:     `{{barChart .Sales "Month" "Total"}}`,
+			contains: []string{`viewBox="0 0 480 240"`, ">Jan<", ">Mar<", ">120<", ">-20<"},
+			count:    map[string]int{"<rect ": 3},
+		},
+		{
+			name:     "line",
+			tmpl:     `{{lineChart .Sales "Month" "Total"}}`,
+			contains: []string{"<polyline ", ">Feb<"},
+			count:    map[string]int{"<circle ": 3},
+		},
+		{
+			name:     "pie",
+			tmpl:     `{{pieChart .Shares "Name" "Pct"}}`,
+			contains: []string{">A &amp; B<", " 0 1 1 ", " 0 0 1 "},
+			count:    map[string]int{"<path ": 2},
+		},
+		{
+			name:  "single slice",
+			tmpl:  `{{pieChart .Whole "Name" "Pct"}}`,
+			count: map[string]int{"<circle ": 1, "<path ": 0},
+		},
+		{name: "negative slice", tmpl: `{{pieChart .Sales "Month" "Total"}}`, wantErr: `value -20 for "Mar" is negative`},
+		{name: "missing field", tmpl: `{{barChart .Sales "Month" "Amount"}}`, wantErr: `item 1: no field "Amount"`},
+		{name: "not a list", tmpl: `{{barChart "text" "Name" "Pct"}}`, wantErr: "cannot chart"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpl := template.Must(template.New("chart").Funcs(TemplateFuncs()).Parse(tt.tmpl))
+			var out strings.Builder
+			err := tmpl.Execute(&out, data)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("Expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+			svg := out.String()
+			if err := xml.Unmarshal([]byte(svg), new(struct{})); err != nil {
+				t.Errorf("Expected well-formed SVG, got %v: %s", err, svg)
+			}
+			for _, s := range tt.contains {
+				if !strings.Contains(svg, s) {
+					t.Errorf("Expected %q in %s", s, svg)
+				}
+			}
+			for s, n := range tt.count {
+				if got := strings.Count(svg, s); got != n {
+					t.Errorf("Expected %d of %q, got %d", n, s, got)
+				}
+			}
+		})
+	}
+}
+
+func TestChartsAreDeterministic(t *testing.T) {
+	points := []chartPoint{{"a", 1}, {"b", 2}, {"c", 3}}
+	for _, render := range []func([]chartPoint) (string, error){barChartSVG, lineChartSVG, pieChartSVG} {
+		first, _ := render(points)
+		second, _ := render(points)
+		if first != second {
+			t.Errorf("Expected identical output, got %s and %s", first, second)
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package html2pdf
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/patipolchat/html2pdf/html2pdf/internal/pdf"
+)
+
+// Classification is the level printed by WithClassification.
+type Classification string
+
+// Standard classification levels. Other values are printed as given.
+const (
+	ClassificationPublic       Classification = "PUBLIC"
+	ClassificationInternal     Classification = "INTERNAL"
+	ClassificationConfidential Classification = "CONFIDENTIAL"
+)
+
+// BannerPosition selects where classification banners are printed.
+type BannerPosition int
+
+const (
+	// BannerTop prints the banner in the top margin.
+	BannerTop BannerPosition = 1 << iota
+	// BannerBottom prints the banner in the bottom margin.
+	BannerBottom
+	// BannerTopAndBottom prints the banner in both margins.
+	BannerTopAndBottom = BannerTop | BannerBottom
+)
+
+const (
+	// classificationFontName is the resource name of the banner font.
+	classificationFontName = "Html2pdfClassification"
+	// classificationFontSize is the banner font size in points.
+	classificationFontSize = 10
+	// classificationInset is the distance from the page edge to the banner
+	// baseline in points.
+	classificationInset = 18
+)
+
+// classificationColors are the RGB fill colors of the standard levels.
+var classificationColors = map[Classification]string{
+	ClassificationPublic:       "0 0.5 0",
+	ClassificationInternal:     "0 0.3 0.7",
+	ClassificationConfidential: "0.8 0 0",
+}
+
+// helveticaBoldWidths are the glyph widths of Helvetica-Bold in 1/1000 em
+// for the characters used in classification levels.
+var helveticaBoldWidths = map[rune]int{
+	' ': 278, '-': 333, '/': 278,
+	'A': 722, 'B': 722, 'C': 722, 'D': 722, 'E': 667, 'F': 611, 'G': 778,
+	'H': 722, 'I': 278, 'J': 556, 'K': 722, 'L': 611, 'M': 833, 'N': 722,
+	'O': 778, 'P': 667, 'Q': 778, 'R': 722, 'S': 667, 'T': 611, 'U': 722,
+	'V': 667, 'W': 944, 'X': 667, 'Y': 667, 'Z': 611,
+}
+
+// WithClassification prints level centered in the top and/or bottom margin
+// of every page, in bold and colored by level for the standard levels. Leave
+// enough margin (see WithMargins) for the banner not to overlap content. An
+// empty level disables the banners.
+func WithClassification(level Classification, position BannerPosition) Option {
+	return func(o *options) {
+		o.classification = level
+		o.classificationPosition = position
+	}
+}
+
+// textWidth returns the width of s in Helvetica-Bold at size points.
+// Characters without a known width count as an average capital.
+func textWidth(s string, size float64) float64 {
+	total := 0
+	for _, r := range s {
+		w, ok := helveticaBoldWidths[r]
+		if !ok {
+			w = 722
+		}
+		total += w
+	}
+	return float64(total) * size / 1000
+}
+
+// classificationContent returns the content stream drawing the banners on a
+// page with the given crop box.
+func classificationContent(level Classification, position BannerPosition, box pdf.Box) []byte {
+	color, ok := classificationColors[level]
+	if !ok {
+		color = "0 0 0"
+	}
+	text := pdf.Serialize(pdf.String(latin1(string(level))))
+	x := box.LLX + (box.Width()-textWidth(string(level), classificationFontSize))/2
+
+	var buf bytes.Buffer
+	draw := func(y float64) {
+		fmt.Fprintf(&buf, "BT %s rg /%s %d Tf %s %s Td %s Tj ET\n",
+			color, classificationFontName, classificationFontSize,
+			formatNumber(round2(x)), formatNumber(round2(y)), text)
+	}
+	buf.WriteString("q\n")
+	if position&BannerTop != 0 {
+		draw(box.URY - classificationInset)
+	}
+	if position&BannerBottom != 0 {
+		draw(box.LLY + classificationInset - classificationFontSize*0.7)
+	}
+	buf.WriteString("Q\n")
+	return buf.Bytes()
+}
+
+// addClassification stamps the classification banners on every page of doc.
+func addClassification(level Classification, position BannerPosition) func(*pdf.Document) error {
+	return func(doc *pdf.Document) error {
+		pages, err := doc.Pages()
+		if err != nil {
+			return err
+		}
+		font := doc.Add(pdf.Dict{
+			"Type":     pdf.Name("Font"),
+			"Subtype":  pdf.Name("Type1"),
+			"BaseFont": pdf.Name("Helvetica-Bold"),
+			"Encoding": pdf.Name("WinAnsiEncoding"),
+		})
+		for _, p := range pages {
+			doc.AddPageResource(p, "Font", classificationFontName, font)
+			doc.AppendPageContent(p, classificationContent(level, position, doc.PageBox(p, "CropBox")))
+		}
+		return nil
+	}
+}
@@ -0,0 +1,96 @@
+package html2pdf
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/patipolchat/html2pdf/html2pdf/internal/pdf"
+)
+
+func TestTextWidth(t *testing.T) {
+	// "PUBLIC" in Helvetica-Bold: 667+722+722+611+278+722 = 3722 units.
+	if got := textWidth("PUBLIC", 10); got != 37.22 {
+		t.Errorf("textWidth() = %v, want 37.22", got)
+	}
+}
+
+func TestClassificationContent(t *testing.T) {
+	box := pdf.Box{URX: 612, URY: 792}
+	tests := []struct {
+		name     string
+		level    Classification
+		position BannerPosition
+		want     []string
+		wantNot  []string
+	}{
+		{
+			name:     "top only",
+			level:    ClassificationConfidential,
+			position: BannerTop,
+			want:     []string{"0.8 0 0 rg", "(CONFIDENTIAL) Tj", " 774 Td"},
+			wantNot:  []string{" 11 Td"},
+		},
+		{
+			name:     "bottom only",
+			level:    ClassificationPublic,
+			position: BannerBottom,
+			want:     []string{"0 0.5 0 rg", "287.39 11 Td"},
+			wantNot:  []string{" 774 Td"},
+		},
+		{
+			name:     "custom level in both margins",
+			level:    "SECRET",
+			position: BannerTopAndBottom,
+			want:     []string{"0 0 0 rg", "(SECRET) Tj", " 774 Td", " 11 Td"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := string(classificationContent(tt.level, tt.position, box))
+			for _, w := range tt.want {
+				if !strings.Contains(got, w) {
+					t.Errorf("Expected content to contain %q, got %q", w, got)
+				}
+			}
+			for _, w := range tt.wantNot {
+				if strings.Contains(got, w) {
+					t.Errorf("Expected content not to contain %q, got %q", w, got)
+				}
+			}
+		})
+	}
+}
+
+func TestWithClassification(t *testing.T) {
+	out, err := postProcess(testPDF(t, 2), newOptions([]Option{WithClassification(ClassificationInternal, BannerTopAndBottom)}))
+	if err != nil {
+		t.Fatalf("postProcess() error = %v", err)
+	}
+	doc, err := pdf.Parse(out)
+	if err != nil {
+		t.Fatalf("Failed to parse output: %v", err)
+	}
+	pages, _ := doc.Pages()
+	if len(pages) != 2 {
+		t.Fatalf("Expected 2 pages, got %d", len(pages))
+	}
+	for i, p := range pages {
+		content, err := doc.PageContent(p)
+		if err != nil {
+			t.Fatalf("Page %d: failed to read content: %v", i+1, err)
+		}
+		if strings.Count(string(content), "(INTERNAL) Tj") != 2 {
+			t.Errorf("Page %d: expected two banners, got %q", i+1, content)
+		}
+		resources, _ := doc.PageAttr(p, "Resources").(pdf.Dict)
+		fonts, _ := doc.Resolve(resources["Font"]).(pdf.Dict)
+		font, _ := doc.Resolve(fonts[classificationFontName]).(pdf.Dict)
+		if font["BaseFont"] != pdf.Name("Helvetica-Bold") {
+			t.Errorf("Page %d: expected Helvetica-Bold banner font, got %v", i+1, font)
+		}
+	}
+
+	if newOptions([]Option{WithClassification(ClassificationPublic, BannerTop)}).fingerprint() == getDefaultOptions().fingerprint() {
+		t.Error("Expected the classification to change the options fingerprint")
+	}
+}
@@ -0,0 +1,65 @@
+package html2pdf
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/chromedp/cdproto/runtime"
+	"github.com/chromedp/chromedp"
+)
+
+// customElementsTimeout bounds how long printing waits for custom elements
+// on the page to be defined.
+var customElementsTimeout = 5 * time.Second
+
+// customElementsScript attaches declarative shadow roots the parser left as
+// templates, e.g. in content set with document.write, then waits for the
+// custom elements on the page to be defined and upgraded. It resolves to the
+// names of elements still undefined after the timeout.
+const customElementsScript = `(async timeoutMs => {
+	const attach = root => {
+		for (const template of root.querySelectorAll("template[shadowrootmode]")) {
+			const host = template.parentNode;
+			if (host instanceof Element && !host.shadowRoot) {
+				const shadow = host.attachShadow({mode: template.getAttribute("shadowrootmode")});
+				shadow.appendChild(template.content);
+				attach(shadow);
+			}
+			template.remove();
+		}
+	};
+	attach(document);
+	const undefinedNames = () => [...new Set(Array.from(document.querySelectorAll(":not(:defined)"), el => el.localName))];
+	const names = undefinedNames();
+	if (names.length === 0) {
+		return [];
+	}
+	await Promise.race([
+		Promise.all(names.map(name => customElements.whenDefined(name))),
+		new Promise(resolve => setTimeout(resolve, timeoutMs)),
+	]);
+	// Components commonly render in a microtask or task after upgrading.
+	await new Promise(resolve => setTimeout(resolve));
+	return undefinedNames();
+})(%d)`
+
+// waitForCustomElements runs customElementsScript and reports elements that
+// were never defined to logger; they are printed as they are.
+func waitForCustomElements(timeout time.Duration, logger func(string, ...interface{})) chromedp.ActionFunc {
+	return func(ctx context.Context) error {
+		var pending []string
+		script := fmt.Sprintf(customElementsScript, timeout.Milliseconds())
+		err := chromedp.Evaluate(script, &pending, func(p *runtime.EvaluateParams) *runtime.EvaluateParams {
+			return p.WithAwaitPromise(true)
+		}).Do(ctx)
+		if err != nil {
+			return err
+		}
+		if len(pending) > 0 {
+			logger("printing with undefined custom elements after %v: %s", timeout, strings.Join(pending, ", "))
+		}
+		return nil
+	}
+}
@@ -0,0 +1,71 @@
+package html2pdf
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/chromedp/chromedp"
+)
+
+func TestConvertHtmlToPdfWaitsForCustomElements(t *testing.T) {
+	defer func(d time.Duration) { customElementsTimeout = d }(customElementsTimeout)
+	customElementsTimeout = time.Second
+
+	tests := []struct {
+		name    string
+		html    string
+		script  string
+		want    string
+		wantLog string
+	}{
+		{
+			name: "late definition",
+			html: `<x-badge></x-badge><script>
+			setTimeout(() => customElements.define("x-badge", class extends HTMLElement {
+				connectedCallback() { this.textContent = "Ready"; }
+			}), 200);
+			</script>`,
+			script: `document.querySelector("x-badge").textContent`,
+			want:   "Ready",
+		},
+		{
+			name:   "declarative shadow DOM",
+			html:   `<div id="host"><template shadowrootmode="open"><p>Shadow</p></template></div>`,
+			script: `document.getElementById("host").shadowRoot.textContent`,
+			want:   "Shadow",
+		},
+		{
+			name:    "never defined",
+			html:    `<x-missing>Fallback</x-missing>`,
+			script:  `document.querySelector("x-missing").textContent`,
+			want:    "Fallback",
+			wantLog: "x-missing",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			defer cancel()
+
+			var got, logged string
+			_, err := ConvertHtmlToPdf(ctx, "<html><body>"+tt.html+"</body></html>",
+				WithLogger(func(format string, args ...interface{}) {
+					logged += fmt.Sprintf(format, args...) + "\n"
+				}),
+				WithPostRenderActions(chromedp.Evaluate(tt.script, &got)))
+			if err != nil {
+				t.Fatalf("ConvertHtmlToPdf() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Expected %q, got %q", tt.want, got)
+			}
+			if tt.wantLog != "" && !strings.Contains(logged, tt.wantLog) {
+				t.Errorf("Expected %q to be logged, got %q", tt.wantLog, logged)
+			}
+		})
+	}
+}
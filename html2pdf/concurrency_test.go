@@ -0,0 +1,187 @@
+package html2pdf
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"html/template"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// The tests in this file run conversions concurrently and are meant to be
+// run with the race detector, go test -race.
+
+func TestWithLoggerConcurrent(t *testing.T) {
+	// A bytes.Buffer is not safe for concurrent use; WithLogger serializes it.
+	var out bytes.Buffer
+	opts := []Option{WithLogger(func(format string, args ...interface{}) {
+		fmt.Fprintf(&out, format+"\n", args...)
+	})}
+
+	const conversions, lines = 20, 50
+	var wg sync.WaitGroup
+	for i := 0; i < conversions; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			logger := conversionLogger(newOptions(opts).logger)
+			for j := 0; j < lines; j++ {
+				logger("line %d", j)
+			}
+		}()
+	}
+	wg.Wait()
+
+	got := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(got) != conversions*lines {
+		t.Fatalf("Expected %d lines, got %d", conversions*lines, len(got))
+	}
+	line := regexp.MustCompile(`^html2pdf #(\d+): line \d+$`)
+	ids := map[string]int{}
+	for _, l := range got {
+		m := line.FindStringSubmatch(l)
+		if m == nil {
+			t.Fatalf("Unexpected log line %q", l)
+		}
+		ids[m[1]]++
+	}
+	if len(ids) != conversions {
+		t.Errorf("Expected %d conversion numbers, got %d", conversions, len(ids))
+	}
+}
+
+func TestWithLoggerNil(t *testing.T) {
+	conversionLogger(newOptions([]Option{WithLogger(nil)}).logger)("discarded %d", 1)
+}
+
+func TestFixturesConcurrent(t *testing.T) {
+	dir := t.TempDir()
+	doc := document{html: "<h1>Concurrent</h1>"}
+	opts := newOptions([]Option{WithFixtures(dir, FixtureRecord)})
+	res := newResult(bytes.Repeat([]byte("%PDF-1.4 "), 10000))
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 40)
+	for i := 0; i < 20; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			errs <- recordFixture(doc, opts, res)
+		}()
+		go func() {
+			defer wg.Done()
+			got, err := replayFixture(doc, opts)
+			if err == nil && got.SHA256 != res.SHA256 {
+				err = fmt.Errorf("replayed checksum %s, want %s", got.SHA256, res.SHA256)
+			}
+			if errors.Is(err, ErrFixtureNotFound) {
+				err = nil
+			}
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Error(err)
+		}
+	}
+}
+
+func TestConversionsConcurrent(t *testing.T) {
+	dir := t.TempDir()
+	shared := []Option{WithResultHMAC([]byte("key")), WithDeduplication(true), WithBufferPool(true)}
+	const docs = 5
+	for i := 0; i < docs; i++ {
+		opts := newOptions(append(shared, WithFixtures(dir, FixtureRecord)))
+		html := fmt.Sprintf("<p>Dear %d</p>", i)
+		if err := recordFixture(document{html: html}, opts, newResult(testPDF(t, 1))); err != nil {
+			t.Fatalf("recordFixture() error = %v", err)
+		}
+	}
+
+	// Every call shares the options.
+	opts := append(shared, WithFixtures(dir, FixtureReplay))
+	tmpl := template.Must(template.New("letter").Parse(`<p>Dear {{.}}</p>`))
+	ctx := context.Background()
+	cache := NewMemoryPackCache()
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 200)
+	for i := 0; i < 50; i++ {
+		wg.Add(4)
+		go func() {
+			defer wg.Done()
+			res, err := ConvertHtmlToResult(ctx, fmt.Sprintf("<p>Dear %d</p>", i%docs), opts...)
+			if err == nil {
+				err = VerifyResult(res.PDF, res.Signature, []byte("key"))
+			}
+			errs <- err
+		}()
+		go func() {
+			defer wg.Done()
+			results, err := BatchRender(ctx, tmpl, []interface{}{0, 1, 2}, opts...)
+			for _, r := range results {
+				err = errors.Join(err, r.Err)
+			}
+			errs <- err
+		}()
+		go func() {
+			defer wg.Done()
+			_, err := RenderPack(ctx, []PackSection{{Name: "a", HTML: "<p>Dear 3</p>"}, {Name: "b", HTML: "<p>Dear 4</p>"}}, cache, opts...)
+			errs <- err
+		}()
+		go func() {
+			defer wg.Done()
+			var w bytes.Buffer
+			errs <- ConvertHtmlToPdfWriter(ctx, "<p>Dear 0</p>", &w, opts...)
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Error(err)
+		}
+	}
+}
+
+func TestConvertHtmlToPdfConcurrent(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 120*time.Second)
+	defer cancel()
+
+	var mu sync.Mutex
+	var lines []string
+	opts := []Option{WithLogger(func(format string, args ...interface{}) {
+		mu.Lock()
+		defer mu.Unlock()
+		lines = append(lines, fmt.Sprintf(format, args...))
+	})}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 8)
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			buf, err := ConvertHtmlToPdf(ctx, fmt.Sprintf("<h1>Document %d</h1>", i), opts...)
+			if err == nil && !bytes.HasPrefix(buf, []byte("%PDF")) {
+				err = fmt.Errorf("document %d is not a PDF", i)
+			}
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Error(err)
+		}
+	}
+}
@@ -0,0 +1,42 @@
+package html2pdf
+
+import (
+	"context"
+	"fmt"
+)
+
+// WithCoverHTML renders html as a separate cover page and prepends it to the
+// document. The cover is printed full-bleed, without margins, header and
+// footer templates or section overrides, in the paper size and orientation
+// of the document. Its content is styled on its own, so it can use a
+// different layout than the body.
+func WithCoverHTML(html string) Option {
+	return func(o *options) {
+		o.coverHTML = html
+	}
+}
+
+// coverOptions returns the options the cover is rendered with: those of the
+// document without the ones that lay out its body.
+func (o *options) coverOptions() *options {
+	cover := *o
+	cover.margins = Margins{}
+	cover.headerTemplate, cover.footerTemplate = "", ""
+	cover.sections = nil
+	cover.pageRanges = ""
+	cover.failOnOverflow = false
+	cover.preRender, cover.postRender = nil, nil
+	cover.coverHTML = ""
+	return &cover
+}
+
+// prependCover renders the cover and places its pages before those of buf.
+// Warnings of the cover are added to those of the document.
+func prependCover(ctx context.Context, cover document, buf []byte, options *options, env *Environment, warnings *warningCollector) ([]byte, error) {
+	var timings Timings
+	coverPDF, err := render(ctx, cover, options.coverOptions(), &timings, nil, env, warnings, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render cover: %w", err)
+	}
+	return MergePdf([][]byte{coverPDF, buf})
+}
@@ -0,0 +1,57 @@
+package html2pdf
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestCoverOptions(t *testing.T) {
+	options := newOptions([]Option{
+		WithCoverHTML("<h1>Annual report</h1>"),
+		WithMargins(1, 1, 0.5, 0.5),
+		WithFooterTemplate(`<span class="pageNumber"></span>`),
+		WithPaperSize(A4),
+		WithLandscape(true),
+		WithPageRanges("2-3"),
+	})
+	cover := options.coverOptions()
+	params := cover.printToPDFParams()
+	if params.MarginTop != 0 || params.MarginLeft != 0 || params.DisplayHeaderFooter || params.PageRanges != "" {
+		t.Errorf("Expected a full-bleed cover without footer or page ranges, got %+v", params)
+	}
+	if params.PaperWidth != A4.Width || !params.Landscape {
+		t.Errorf("Expected the document's paper, got %+v", params)
+	}
+	if cover.coverHTML != "" {
+		t.Error("Expected the cover not to have a cover")
+	}
+	if options.margins.Top != 1 || options.footerTemplate == "" {
+		t.Error("Expected the document options to be unchanged")
+	}
+	if newOptions(nil).fingerprint() == options.fingerprint() {
+		t.Error("Expected the cover to be part of the fingerprint")
+	}
+	if options.streamable() {
+		t.Error("Expected a document with a cover not to be streamable")
+	}
+}
+
+func TestConvertHtmlToResultWithCover(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	res, err := ConvertHtmlToResult(ctx, "<h1>Body</h1>",
+		WithCoverHTML(`<h1 style="color: navy">Annual report</h1>`),
+		WithMargins(1, 1, 1, 1))
+	if err != nil {
+		t.Fatalf("ConvertHtmlToResult() error = %v", err)
+	}
+	pages, err := res.PageInfos()
+	if err != nil {
+		t.Fatalf("PageInfos() error = %v", err)
+	}
+	if len(pages) != 2 {
+		t.Errorf("Expected a cover and a body page, got %d pages", len(pages))
+	}
+}
@@ -0,0 +1,140 @@
+package html2pdf
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/chromedp/chromedp"
+)
+
+// DefaultPageBreakSelector matches the elements turned into page breaks by default.
+const DefaultPageBreakSelector = "[data-html2pdf-break]"
+
+// WithPageBreakSelector sets the CSS selector of the marker elements that are
+// turned into forced page breaks. An empty selector disables the feature.
+func WithPageBreakSelector(selector string) Option {
+	return func(o *options) {
+		o.pageBreakSelector = selector
+	}
+}
+
+// typographyCSS keeps headings with the text that follows them and avoids
+// single lines of a paragraph at the top or bottom of a page. :where() gives
+// the rules no specificity, so any rule in the page overrides them.
+const typographyCSS = `:where(p, li, blockquote, dd, figcaption) {
+	orphans: 3;
+	widows: 3;
+}
+:where(h1, h2, h3, h4, h5, h6) {
+	break-after: avoid;
+	page-break-after: avoid;
+	break-inside: avoid;
+	page-break-inside: avoid;
+}`
+
+// WithTypographyFixes adds print typography rules: headings are kept with
+// the content after them, and paragraphs leave at least three lines at the
+// bottom and top of a page. Templates can still override the rules.
+func WithTypographyFixes(enabled bool) Option {
+	return func(o *options) {
+		o.typographyFixes = enabled
+	}
+}
+
+// WithBackgroundGraphics prints background images. Combined with
+// WithBackgroundColors(false), background colors are removed.
+func WithBackgroundGraphics(enabled bool) Option {
+	return func(o *options) {
+		o.backgroundGraphics = enabled
+	}
+}
+
+// WithBackgroundColors prints background colors, e.g. for colored table
+// headers. Combined with WithBackgroundGraphics(false), background images are removed.
+func WithBackgroundColors(enabled bool) Option {
+	return func(o *options) {
+		o.backgroundColors = enabled
+	}
+}
+
+// WithPrintBackground prints both background colors and background images,
+// like the "Background graphics" checkbox in Chrome's print dialog. It is
+// shorthand for WithBackgroundColors and WithBackgroundGraphics. Backgrounds
+// are not printed by default.
+func WithPrintBackground(enabled bool) Option {
+	return func(o *options) {
+		o.backgroundColors = enabled
+		o.backgroundGraphics = enabled
+	}
+}
+
+// printBackground reports whether Chrome has to print backgrounds at all.
+func (o *options) printBackground() bool {
+	return o.backgroundGraphics || o.backgroundColors
+}
+
+// injectedCSS returns the style sheet the options add to the page before printing.
+func (o *options) injectedCSS() string {
+	var rules []string
+	if o.pageBreakSelector != "" {
+		rules = append(rules, fmt.Sprintf(`%s {
+	display: block !important;
+	clear: both !important;
+	height: 0 !important;
+	margin: 0 !important;
+	padding: 0 !important;
+	border: 0 !important;
+	break-after: page !important;
+	page-break-after: always !important;
+}`, o.pageBreakSelector))
+	}
+	// Chrome prints background colors and images together, so the one that
+	// was not asked for is suppressed with CSS.
+	switch {
+	case o.backgroundColors && !o.backgroundGraphics:
+		rules = append(rules, `*, *::before, *::after {
+	background-image: none !important;
+	-webkit-print-color-adjust: exact !important;
+	print-color-adjust: exact !important;
+}`)
+	case o.backgroundGraphics && !o.backgroundColors:
+		rules = append(rules, `*, *::before, *::after {
+	background-color: transparent !important;
+}`)
+	case o.backgroundGraphics && o.backgroundColors:
+		rules = append(rules, `*, *::before, *::after {
+	-webkit-print-color-adjust: exact !important;
+	print-color-adjust: exact !important;
+}`)
+	}
+	if o.typographyFixes {
+		rules = append(rules, typographyCSS)
+	}
+	if o.iframePolicy == IframeBlock {
+		rules = append(rules, `iframe {
+	display: none !important;
+}`)
+	}
+	if o.svgVectors {
+		rules = append(rules, svgVectorCSS)
+	}
+	return strings.Join(rules, "\n")
+}
+
+// injectCSS appends css to the document in a new style element.
+func injectCSS(css string) chromedp.Action {
+	return chromedp.ActionFunc(func(ctx context.Context) error {
+		quoted, err := json.Marshal(css)
+		if err != nil {
+			return err
+		}
+		script := fmt.Sprintf(`(() => {
+	const style = document.createElement("style");
+	style.textContent = %s;
+	(document.head || document.documentElement).appendChild(style);
+})()`, quoted)
+		return chromedp.Evaluate(script, nil).Do(ctx)
+	})
+}
@@ -0,0 +1,123 @@
+package html2pdf
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestWithPageBreakSelector(t *testing.T) {
+	tests := []struct {
+		name     string
+		opts     []Option
+		contains string
+		empty    bool
+	}{
+		{
+			name:     "default marker",
+			contains: DefaultPageBreakSelector + " {",
+		},
+		{
+			name:     "custom selector",
+			opts:     []Option{WithPageBreakSelector(".chapter-end")},
+			contains: ".chapter-end {",
+		},
+		{
+			name:  "disabled",
+			opts:  []Option{WithPageBreakSelector("")},
+			empty: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			css := newOptions(tt.opts).injectedCSS()
+			if tt.empty {
+				if css != "" {
+					t.Errorf("Expected no injected CSS, got %q", css)
+				}
+				return
+			}
+			if !strings.Contains(css, tt.contains) || !strings.Contains(css, "break-after: page") {
+				t.Errorf("Expected page break rule for %q, got %q", tt.contains, css)
+			}
+		})
+	}
+}
+
+func TestBackgroundOptions(t *testing.T) {
+	tests := []struct {
+		name            string
+		opts            []Option
+		printBackground bool
+		contains        string
+		excludes        []string
+	}{
+		{
+			name:     "default",
+			excludes: []string{"background-image", "background-color", "print-color-adjust"},
+		},
+		{
+			name:            "colors only",
+			opts:            []Option{WithBackgroundColors(true)},
+			printBackground: true,
+			contains:        "background-image: none",
+			excludes:        []string{"background-color"},
+		},
+		{
+			name:            "graphics only",
+			opts:            []Option{WithBackgroundGraphics(true)},
+			printBackground: true,
+			contains:        "background-color: transparent",
+			excludes:        []string{"background-image"},
+		},
+		{
+			name:            "both",
+			opts:            []Option{WithBackgroundGraphics(true), WithBackgroundColors(true)},
+			printBackground: true,
+			contains:        "print-color-adjust: exact",
+			excludes:        []string{"background-image", "background-color"},
+		},
+		{
+			name:            "print background",
+			opts:            []Option{WithPrintBackground(true)},
+			printBackground: true,
+			contains:        "print-color-adjust: exact",
+			excludes:        []string{"background-image", "background-color"},
+		},
+		{
+			name:     "print background disabled",
+			opts:     []Option{WithBackgroundColors(true), WithPrintBackground(false)},
+			excludes: []string{"background-image", "background-color", "print-color-adjust"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o := newOptions(tt.opts)
+			if got := o.printToPDFParams().PrintBackground; got != tt.printBackground {
+				t.Errorf("Expected PrintBackground %v, got %v", tt.printBackground, got)
+			}
+			css := o.injectedCSS()
+			if !strings.Contains(css, tt.contains) {
+				t.Errorf("Expected CSS to contain %q, got %q", tt.contains, css)
+			}
+			for _, s := range tt.excludes {
+				if strings.Contains(css, s) {
+					t.Errorf("Expected CSS not to contain %q, got %q", s, css)
+				}
+			}
+		})
+	}
+}
+
+func TestWithTypographyFixes(t *testing.T) {
+	if strings.Contains(getDefaultOptions().injectedCSS(), "orphans") {
+		t.Error("Expected no typography rules by default")
+	}
+	css := newOptions([]Option{WithTypographyFixes(true)}).injectedCSS()
+	for _, want := range []string{"orphans: 3", "widows: 3", ":where(h1, h2, h3, h4, h5, h6)", "break-after: avoid"} {
+		if !strings.Contains(css, want) {
+			t.Errorf("Expected %q in %q", want, css)
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package html2pdf
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"html/template"
+	"io"
+	"strings"
+)
+
+// WithCSVRowMapper sets how MailMergeCSV turns a CSV row, keyed by the
+// header, into template data, e.g. to parse amounts or dates. By default
+// every column is passed on as a string.
+func WithCSVRowMapper(fn func(row map[string]string) (map[string]interface{}, error)) Option {
+	return func(o *options) {
+		o.csvRowMapper = fn
+	}
+}
+
+// MailMergeCSV reads a CSV whose first line names the columns, such as a
+// spreadsheet export, and passes one row per record to MailMerge, e.g. to
+// generate certificates or payslips. Outputs are named with
+// WithMailMergeName, e.g. "payslip-{{.Row.employee_id}}.pdf".
+func MailMergeCSV(ctx context.Context, tmpl *template.Template, r io.Reader, opts ...Option) ([]MailMergeDocument, error) {
+	options := newOptions(opts)
+	rows, err := readCSVRows(r, options.csvRowMapper)
+	if err != nil {
+		return nil, err
+	}
+	return MailMerge(ctx, tmpl, rows, opts...)
+}
+
+// readCSVRows reads the records of r keyed by its header and maps them with
+// mapper, if set.
+func readCSVRows(r io.Reader, mapper func(map[string]string) (map[string]interface{}, error)) ([]map[string]interface{}, error) {
+	cr := csv.NewReader(r)
+	header, err := cr.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("failed to read CSV: no header")
+		}
+		return nil, fmt.Errorf("failed to read CSV: %w", err)
+	}
+	seen := make(map[string]bool, len(header))
+	for i, name := range header {
+		if i == 0 {
+			// Spreadsheet applications often start UTF-8 exports with a BOM.
+			name = strings.TrimPrefix(name, "\ufeff")
+		}
+		name = strings.TrimSpace(name)
+		if name == "" {
+			return nil, fmt.Errorf("failed to read CSV: column %d has no name", i+1)
+		}
+		if seen[name] {
+			return nil, fmt.Errorf("failed to read CSV: duplicate column %q", name)
+		}
+		seen[name] = true
+		header[i] = name
+	}
+
+	var rows []map[string]interface{}
+	for {
+		record, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			return rows, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CSV: %w", err)
+		}
+		fields := make(map[string]string, len(header))
+		for i, name := range header {
+			fields[name] = record[i]
+		}
+		if mapper != nil {
+			row, err := mapper(fields)
+			if err != nil {
+				return nil, fmt.Errorf("row %d: %w", len(rows)+1, err)
+			}
+			rows = append(rows, row)
+			continue
+		}
+		row := make(map[string]interface{}, len(fields))
+		for name, v := range fields {
+			row[name] = v
+		}
+		rows = append(rows, row)
+	}
+}
@@ -0,0 +1,74 @@
+package html2pdf
+
+import (
+	"context"
+	"fmt"
+	"html/template"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestReadCSVRows(t *testing.T) {
+	hours := func(row map[string]string) (map[string]interface{}, error) {
+		h, err := strconv.Atoi(row["hours"])
+		if err != nil {
+			return nil, fmt.Errorf("invalid hours %q", row["hours"])
+		}
+		return map[string]interface{}{"name": row["name"], "hours": h}, nil
+	}
+
+	tests := []struct {
+		name    string
+		csv     string
+		mapper  func(map[string]string) (map[string]interface{}, error)
+		want    []map[string]interface{}
+		wantErr bool
+	}{
+		{
+			name: "strings",
+			csv:  "name,hours\nAda,40\nGrace,32\n",
+			want: []map[string]interface{}{{"name": "Ada", "hours": "40"}, {"name": "Grace", "hours": "32"}},
+		},
+		{
+			name: "spreadsheet export",
+			csv:  "\ufeffname , hours\r\n\"Lovelace, Ada\",40\r\n",
+			want: []map[string]interface{}{{"name": "Lovelace, Ada", "hours": "40"}},
+		},
+		{name: "mapped", csv: "name,hours\nAda,40\n", mapper: hours, want: []map[string]interface{}{{"name": "Ada", "hours": 40}}},
+		{name: "header only", csv: "name,hours\n"},
+		{name: "mapper error", csv: "name,hours\nAda,forty\n", mapper: hours, wantErr: true},
+		{name: "empty", csv: "", wantErr: true},
+		{name: "unnamed column", csv: "name,,hours\n", wantErr: true},
+		{name: "duplicate column", csv: "name,name\n", wantErr: true},
+		{name: "short row", csv: "name,hours\nAda\n", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := readCSVRows(strings.NewReader(tt.csv), tt.mapper)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("Expected an error, got rows %v", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("readCSVRows() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestMailMergeCSVNames(t *testing.T) {
+	tmpl := template.Must(template.New("certificate").Parse(`<h1>{{.name}}</h1>`))
+	csv := "id,name\n1,Ada\n1,Grace\n"
+	_, err := MailMergeCSV(context.Background(), tmpl, strings.NewReader(csv), WithMailMergeName("certificate-{{.Row.id}}.pdf"))
+	if err == nil || !strings.Contains(err.Error(), "certificate-1.pdf") {
+		t.Errorf("Expected an error for duplicate names, got %v", err)
+	}
+}
@@ -0,0 +1,78 @@
+package html2pdf
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/chromedp/cdproto/emulation"
+	"github.com/chromedp/cdproto/page"
+	"github.com/chromedp/cdproto/runtime"
+	"github.com/chromedp/chromedp"
+)
+
+// deadlineGlobal is the window property holding the page deadline.
+const deadlineGlobal = "__html2pdfDeadlineMs"
+
+// WithDeadlinePropagation exposes the context deadline, minus reserve, to
+// page scripts as window.__html2pdfDeadlineMs in Unix milliseconds, so
+// templates can compare it with Date.now() and skip heavy work. When the page
+// deadline passes, running scripts are interrupted and further scripts are
+// disabled, and the page is printed as it is. The reserve leaves time for
+// printing before the context expires. Without a context deadline the option
+// has no effect.
+func WithDeadlinePropagation(reserve time.Duration) Option {
+	return func(o *options) {
+		o.propagateDeadline = true
+		o.deadlineReserve = reserve
+	}
+}
+
+// pageDeadline returns the deadline page scripts should finish by.
+func pageDeadline(ctx context.Context, reserve time.Duration) (time.Time, bool) {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return time.Time{}, false
+	}
+	return deadline.Add(-reserve), true
+}
+
+// deadlineScript sets the deadline global for page scripts.
+func deadlineScript(deadline time.Time) string {
+	return fmt.Sprintf("window.%s = %d;", deadlineGlobal, deadline.UnixMilli())
+}
+
+// propagateDeadline publishes the page deadline to the current and future
+// documents and interrupts page scripts once it passes, unless stop is
+// closed first.
+func propagateDeadline(reserve time.Duration, stop <-chan struct{}) chromedp.ActionFunc {
+	return func(ctx context.Context) error {
+		deadline, ok := pageDeadline(ctx, reserve)
+		if !ok {
+			return nil
+		}
+		script := deadlineScript(deadline)
+		if _, err := page.AddScriptToEvaluateOnNewDocument(script).Do(ctx); err != nil {
+			return err
+		}
+		if _, _, err := runtime.Evaluate(script).Do(ctx); err != nil {
+			return err
+		}
+
+		go func() {
+			timer := time.NewTimer(time.Until(deadline))
+			defer timer.Stop()
+			select {
+			case <-stop:
+				return
+			case <-ctx.Done():
+				return
+			case <-timer.C:
+			}
+			// Disable scripts first so timers cannot restart the work.
+			emulation.SetScriptExecutionDisabled(true).Do(ctx)
+			runtime.TerminateExecution().Do(ctx)
+		}()
+		return nil
+	}
+}
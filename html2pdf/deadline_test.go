@@ -0,0 +1,37 @@
+package html2pdf
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestPageDeadline(t *testing.T) {
+	if _, ok := pageDeadline(context.Background(), time.Second); ok {
+		t.Error("Expected no page deadline without a context deadline")
+	}
+
+	deadline := time.Unix(1700000000, 0)
+	ctx, cancel := context.WithDeadline(context.Background(), deadline)
+	defer cancel()
+	got, ok := pageDeadline(ctx, 2*time.Second)
+	if !ok || !got.Equal(deadline.Add(-2*time.Second)) {
+		t.Errorf("pageDeadline() = %v, %v; want %v", got, ok, deadline.Add(-2*time.Second))
+	}
+}
+
+func TestDeadlineScript(t *testing.T) {
+	got := deadlineScript(time.UnixMilli(1700000000123))
+	want := "window.__html2pdfDeadlineMs = 1700000000123;"
+	if got != want {
+		t.Errorf("deadlineScript() = %q, want %q", got, want)
+	}
+}
+
+func TestWithDeadlinePropagation(t *testing.T) {
+	opts := getDefaultOptions()
+	WithDeadlinePropagation(500 * time.Millisecond)(opts)
+	if !opts.propagateDeadline || opts.deadlineReserve != 500*time.Millisecond {
+		t.Errorf("Expected deadline propagation with 500ms reserve, got %v/%v", opts.propagateDeadline, opts.deadlineReserve)
+	}
+}
@@ -0,0 +1,224 @@
+package html2pdf
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// maxDecimalPlaces limits the digits printed for a Decimal that has no exact
+// decimal form, such as one third.
+const maxDecimalPlaces = 16
+
+// Decimal is an exact decimal number for money and tax calculations in
+// templates, backed by big.Rat. It prints in plain decimal notation; results
+// of the round template function keep their trailing zeros, e.g. "1234.50".
+type Decimal struct {
+	rat big.Rat
+	// places is the number of fraction digits to print, or -1 for as
+	// many as the value needs.
+	places int
+}
+
+// ParseDecimal parses a decimal number such as "19.99" or "-0.07".
+func ParseDecimal(s string) (Decimal, error) {
+	var d Decimal
+	if _, ok := d.rat.SetString(strings.TrimSpace(s)); !ok || strings.ContainsAny(s, "/") {
+		return Decimal{}, fmt.Errorf("invalid decimal %q", s)
+	}
+	d.places = -1
+	return d, nil
+}
+
+// String formats d in decimal notation without an exponent.
+func (d Decimal) String() string {
+	places := d.places
+	if places < 0 {
+		places = exactPlaces(&d.rat)
+	}
+	return d.rat.FloatString(places)
+}
+
+// MarshalJSON encodes d as a JSON number, so schemas see it as one.
+func (d Decimal) MarshalJSON() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// UnmarshalJSON decodes a JSON number or numeric string.
+func (d *Decimal) UnmarshalJSON(b []byte) error {
+	s := string(b)
+	if unquoted, err := strconv.Unquote(s); err == nil {
+		s = unquoted
+	}
+	parsed, err := ParseDecimal(s)
+	if err != nil {
+		return err
+	}
+	*d = parsed
+	return nil
+}
+
+// exactPlaces returns the fraction digits needed to print r exactly, capped
+// at maxDecimalPlaces.
+func exactPlaces(r *big.Rat) int {
+	pow := big.NewInt(1)
+	ten := big.NewInt(10)
+	for places := 0; places < maxDecimalPlaces; places++ {
+		if new(big.Int).Mod(pow, r.Denom()).Sign() == 0 {
+			return places
+		}
+		pow.Mul(pow, ten)
+	}
+	return maxDecimalPlaces
+}
+
+// toDecimal converts a template value to a Decimal. Floats are converted from
+// their shortest decimal form, so 0.1 is exactly one tenth.
+func toDecimal(v interface{}) (Decimal, error) {
+	switch v := v.(type) {
+	case Decimal:
+		return v, nil
+	case *big.Rat:
+		var d Decimal
+		d.rat.Set(v)
+		d.places = -1
+		return d, nil
+	case string:
+		return ParseDecimal(v)
+	case json.Number:
+		return ParseDecimal(v.String())
+	}
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return ParseDecimal(strconv.FormatInt(rv.Int(), 10))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return ParseDecimal(strconv.FormatUint(rv.Uint(), 10))
+	case reflect.Float32:
+		return ParseDecimal(strconv.FormatFloat(rv.Float(), 'f', -1, 32))
+	case reflect.Float64:
+		return ParseDecimal(strconv.FormatFloat(rv.Float(), 'f', -1, 64))
+	}
+	return Decimal{}, fmt.Errorf("cannot use %T as a decimal", v)
+}
+
+// decimalFuncs returns the decimal template functions.
+func decimalFuncs() map[string]interface{} {
+	return map[string]interface{}{
+		"decimal": toDecimal,
+		"add": func(a interface{}, more ...interface{}) (Decimal, error) {
+			return foldDecimals(a, more, (*big.Rat).Add)
+		},
+		"sub": func(a, b interface{}) (Decimal, error) {
+			return foldDecimals(a, []interface{}{b}, (*big.Rat).Sub)
+		},
+		"mul": func(a interface{}, more ...interface{}) (Decimal, error) {
+			return foldDecimals(a, more, (*big.Rat).Mul)
+		},
+		"div":   divDecimal,
+		"round": roundDecimal,
+		"sum":   sumDecimals,
+	}
+}
+
+// foldDecimals combines a with each of more using op.
+func foldDecimals(a interface{}, more []interface{}, op func(z, x, y *big.Rat) *big.Rat) (Decimal, error) {
+	acc, err := toDecimal(a)
+	if err != nil {
+		return Decimal{}, err
+	}
+	result := Decimal{places: -1}
+	result.rat.Set(&acc.rat)
+	for _, v := range more {
+		d, err := toDecimal(v)
+		if err != nil {
+			return Decimal{}, err
+		}
+		op(&result.rat, &result.rat, &d.rat)
+	}
+	return result, nil
+}
+
+// divDecimal divides a by b.
+func divDecimal(a, b interface{}) (Decimal, error) {
+	d, err := toDecimal(b)
+	if err != nil {
+		return Decimal{}, err
+	}
+	if d.rat.Sign() == 0 {
+		return Decimal{}, fmt.Errorf("division by zero")
+	}
+	return foldDecimals(a, []interface{}{d}, (*big.Rat).Quo)
+}
+
+// roundDecimal rounds v to places fraction digits, rounding halves away
+// from zero, and prints the result with exactly that many digits. v comes
+// last so results can be piped in: {{mul .Subtotal "0.07" | round 2}}.
+func roundDecimal(places int, v interface{}) (Decimal, error) {
+	if places < 0 {
+		return Decimal{}, fmt.Errorf("negative decimal places %d", places)
+	}
+	d, err := toDecimal(v)
+	if err != nil {
+		return Decimal{}, err
+	}
+	// FloatString rounds halves away from zero.
+	rounded, err := ParseDecimal(d.rat.FloatString(places))
+	if err != nil {
+		return Decimal{}, err
+	}
+	rounded.places = places
+	return rounded, nil
+}
+
+// sumDecimals adds up the elements of a slice or array. With a field, it
+// adds up that struct field or map key of each element instead, e.g.
+// {{sum .Lines "Amount"}}.
+func sumDecimals(items interface{}, field ...string) (Decimal, error) {
+	total := Decimal{places: -1}
+	rv := reflect.ValueOf(items)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return Decimal{}, fmt.Errorf("cannot sum %T", items)
+	}
+	for i := 0; i < rv.Len(); i++ {
+		elem := rv.Index(i)
+		if len(field) > 0 {
+			var err error
+			if elem, err = fieldValue(elem, field[0]); err != nil {
+				return Decimal{}, fmt.Errorf("item %d: %w", i+1, err)
+			}
+		}
+		d, err := toDecimal(elem.Interface())
+		if err != nil {
+			return Decimal{}, fmt.Errorf("item %d: %w", i+1, err)
+		}
+		total.rat.Add(&total.rat, &d.rat)
+	}
+	return total, nil
+}
+
+// fieldValue returns the named struct field or map entry of v.
+func fieldValue(v reflect.Value, name string) (reflect.Value, error) {
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return reflect.Value{}, fmt.Errorf("nil value has no field %q", name)
+		}
+		v = v.Elem()
+	}
+	switch v.Kind() {
+	case reflect.Struct:
+		if f := v.FieldByName(name); f.IsValid() && f.CanInterface() {
+			return f, nil
+		}
+	case reflect.Map:
+		if v.Type().Key().Kind() == reflect.String {
+			if f := v.MapIndex(reflect.ValueOf(name).Convert(v.Type().Key())); f.IsValid() {
+				return f, nil
+			}
+		}
+	}
+	return reflect.Value{}, fmt.Errorf("no field %q in %s", name, v.Type())
+}
@@ -0,0 +1,95 @@
+package html2pdf
+
+import (
+	"encoding/json"
+	"html/template"
+	"strings"
+	"testing"
+)
+
+func TestDecimalFuncs(t *testing.T) {
+	type line struct {
+		Description string
+		Amount      float64
+	}
+	data := map[string]interface{}{
+		"Lines":    []line{{"A", 0.1}, {"B", 0.1}, {"C", 0.1}},
+		"Rows":     []map[string]interface{}{{"Amount": "19.99"}, {"Amount": 5}},
+		"Values":   []int{1, 2, 3},
+		"Subtotal": 199.9,
+	}
+
+	tests := []struct {
+		name    string
+		tmpl    string
+		want    string
+		wantErr string
+	}{
+		{name: "add", tmpl: `{{add 0.1 0.2}}`, want: "0.3"},
+		{name: "add many", tmpl: `{{add "1.10" 2 "-0.1"}}`, want: "3"},
+		{name: "sub", tmpl: `{{sub "100" "0.01"}}`, want: "99.99"},
+		{name: "mul", tmpl: `{{mul 19.99 3}}`, want: "59.97"},
+		{name: "div", tmpl: `{{div 10 4}}`, want: "2.5"},
+		{name: "repeating division", tmpl: `{{div 10 3}}`, want: "3.3333333333333333"},
+		{name: "round half up", tmpl: `{{round 2 "10.005"}}`, want: "10.01"},
+		{name: "round negative half", tmpl: `{{round 0 "-2.5"}}`, want: "-3"},
+		{name: "round pads", tmpl: `{{round 2 3}}`, want: "3.00"},
+		{name: "tax", tmpl: `{{mul .Subtotal "0.07" | round 2}}`, want: "13.99"},
+		{name: "sum field", tmpl: `{{sum .Lines "Amount"}}`, want: "0.3"},
+		{name: "sum map key", tmpl: `{{sum .Rows "Amount"}}`, want: "24.99"},
+		{name: "sum values", tmpl: `{{sum .Values}}`, want: "6"},
+		{name: "decimal", tmpl: `{{decimal "12.50"}}`, want: "12.5"},
+		{name: "division by zero", tmpl: `{{div 1 0}}`, wantErr: "division by zero"},
+		{name: "not a number", tmpl: `{{add "abc" 1}}`, wantErr: `invalid decimal "abc"`},
+		{name: "fraction", tmpl: `{{decimal "1/3"}}`, wantErr: `invalid decimal "1/3"`},
+		{name: "unsupported type", tmpl: `{{add true 1}}`, wantErr: "cannot use bool as a decimal"},
+		{name: "missing field", tmpl: `{{sum .Lines "Price"}}`, wantErr: `item 1: no field "Price"`},
+		{name: "sum non-list", tmpl: `{{sum .Subtotal}}`, wantErr: "cannot sum float64"},
+		{name: "negative places", tmpl: `{{round -1 1}}`, wantErr: "negative decimal places"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpl := template.Must(template.New("t").Funcs(TemplateFuncs()).Parse(tt.tmpl))
+			var out strings.Builder
+			err := tmpl.Execute(&out, data)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("Expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+			if out.String() != tt.want {
+				t.Errorf("Expected %q, got %q", tt.want, out.String())
+			}
+		})
+	}
+}
+
+func TestDecimalJSON(t *testing.T) {
+	var invoice struct {
+		Total Decimal `json:"total"`
+		Tax   Decimal `json:"tax"`
+	}
+	if err := json.Unmarshal([]byte(`{"total": 10.10, "tax": "0.71"}`), &invoice); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if invoice.Total.String() != "10.1" || invoice.Tax.String() != "0.71" {
+		t.Errorf("Expected 10.1 and 0.71, got %s and %s", invoice.Total, invoice.Tax)
+	}
+
+	b, err := json.Marshal(invoice)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	if want := `{"total":10.1,"tax":0.71}`; string(b) != want {
+		t.Errorf("Expected %s, got %s", want, b)
+	}
+
+	if err := json.Unmarshal([]byte(`{"total": "ten"}`), &invoice); err == nil {
+		t.Error("Expected an error for a non-numeric string")
+	}
+}
@@ -0,0 +1,141 @@
+package html2pdf
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// flightCall is a conversion in progress whose outcome is shared by every
+// caller that requested the same document.
+type flightCall struct {
+	done    chan struct{}
+	ctx     context.Context
+	cancel  context.CancelFunc
+	waiters int
+	res     *Result
+	err     error
+}
+
+// flightGroup deduplicates concurrent conversions by key.
+type flightGroup struct {
+	mu    sync.Mutex
+	calls map[string]*flightCall
+}
+
+// conversions is the group shared by all calls with WithDeduplication enabled.
+var conversions = &flightGroup{}
+
+// do runs fn once for all concurrent callers using the same key. fn gets a
+// context that keeps the values and the deadline of the first caller's but
+// is canceled only once every caller has stopped waiting, so one caller
+// giving up does not fail the others. Each caller stops waiting when its own
+// context is done, and a caller left with time when the first caller's
+// deadline cuts the call short starts it again.
+func (g *flightGroup) do(ctx context.Context, key string, fn func(ctx context.Context) (*Result, error)) (*Result, error) {
+	for {
+		c := g.join(ctx, key, fn)
+		select {
+		case <-c.done:
+			if c.err != nil && errors.Is(c.ctx.Err(), context.DeadlineExceeded) && !expired(ctx) {
+				continue
+			}
+			return c.shared()
+		case <-ctx.Done():
+			g.mu.Lock()
+			c.waiters--
+			abandoned := c.waiters == 0
+			g.mu.Unlock()
+			if abandoned {
+				// Later callers start a new conversion instead of joining the
+				// canceled one.
+				g.forget(key, c)
+				c.cancel()
+			}
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// join adds the caller to the call for key, starting fn if there is none.
+func (g *flightGroup) join(ctx context.Context, key string, fn func(ctx context.Context) (*Result, error)) *flightCall {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.calls == nil {
+		g.calls = make(map[string]*flightCall)
+	}
+	c, ok := g.calls[key]
+	if !ok {
+		runCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+		if deadline, ok := ctx.Deadline(); ok {
+			runCtx, cancel = context.WithDeadline(context.WithoutCancel(ctx), deadline)
+		}
+		c = &flightCall{done: make(chan struct{}), ctx: runCtx, cancel: cancel}
+		g.calls[key] = c
+		go func() {
+			c.res, c.err = fn(runCtx)
+			cancel()
+			g.forget(key, c)
+			close(c.done)
+		}()
+	}
+	c.waiters++
+	return c
+}
+
+// expired reports whether the deadline of ctx has passed.
+func expired(ctx context.Context) bool {
+	deadline, ok := ctx.Deadline()
+	return ok && !time.Now().Before(deadline)
+}
+
+// forget removes c from the group unless a newer call replaced it.
+func (g *flightGroup) forget(key string, c *flightCall) {
+	g.mu.Lock()
+	if g.calls[key] == c {
+		delete(g.calls, key)
+	}
+	g.mu.Unlock()
+}
+
+// shared returns a copy of the call's Result so callers do not share the
+// struct. The PDF bytes themselves are shared and must not be modified.
+func (c *flightCall) shared() (*Result, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+	res := *c.res
+	return &res, nil
+}
+
+// WithDeduplication makes concurrent conversions of identical content with
+// identical options share a single render. The render is canceled only when
+// every caller's context is done, and is bounded by the deadline of the
+// caller that started it. Conversions with render actions always render on
+// their own, since the actions cannot be compared. The PDF bytes of a shared
+// result are not copied, so callers must not modify them.
+func WithDeduplication(enabled bool) Option {
+	return func(o *options) {
+		o.deduplicate = enabled
+	}
+}
+
+// shares reports whether the conversion shares its render with identical
+// concurrent ones.
+func (o *options) shares() bool {
+	return o.deduplicate && len(o.preRender) == 0 && len(o.postRender) == 0
+}
+
+// dedupKey identifies a conversion by the SHA-256 of its content, the
+// options that affect the output and those that change how the render runs,
+// such as a screencast, which callers expect to get for their own request.
+func dedupKey(htmlContent string, o *options) string {
+	h := sha256.New()
+	h.Write([]byte(htmlContent))
+	fmt.Fprintf(h, "\x00%s\x00deadline=%v/%s screencast=%q", o.fingerprint(), o.propagateDeadline, o.deadlineReserve, o.screencastDir)
+	return hex.EncodeToString(h.Sum(nil))
+}
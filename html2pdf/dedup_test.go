@@ -0,0 +1,286 @@
+package html2pdf
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/chromedp/chromedp"
+)
+
+func TestFlightGroupSharesResult(t *testing.T) {
+	g := &flightGroup{}
+	var calls int32
+	release := make(chan struct{})
+
+	fn := func(context.Context) (*Result, error) {
+		atomic.AddInt32(&calls, 1)
+		<-release
+		return newResult([]byte("%PDF-shared")), nil
+	}
+
+	const callers = 50
+	var wg sync.WaitGroup
+	results := make([]*Result, callers)
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			res, err := g.do(context.Background(), "key", fn)
+			if err != nil {
+				t.Errorf("do() error = %v", err)
+				return
+			}
+			results[i] = res
+		}(i)
+	}
+
+	// Give every caller time to join the in-flight call.
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if got := atomic.LoadInt32(&calls); got != 1 {
+		t.Errorf("Expected 1 render, got %d", got)
+	}
+	for i, res := range results {
+		if res == nil || string(res.PDF) != "%PDF-shared" {
+			t.Errorf("Caller %d got unexpected result %+v", i, res)
+		}
+	}
+	if results[0] == results[1] {
+		t.Error("Callers should receive distinct Result values")
+	}
+}
+
+func TestFlightGroupWaiterContext(t *testing.T) {
+	g := &flightGroup{}
+	release := make(chan struct{})
+	defer close(release)
+
+	go g.do(context.Background(), "key", func(context.Context) (*Result, error) {
+		<-release
+		return newResult(nil), nil
+	})
+	time.Sleep(10 * time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := g.do(ctx, "key", nil); err != context.DeadlineExceeded {
+		t.Errorf("Expected context.DeadlineExceeded, got %v", err)
+	}
+}
+
+func TestFlightGroupLeaderCanceled(t *testing.T) {
+	g := &flightGroup{}
+	release := make(chan struct{})
+
+	leaderCtx, cancelLeader := context.WithCancel(context.Background())
+	leaderDone := make(chan error)
+	go func() {
+		_, err := g.do(leaderCtx, "key", func(ctx context.Context) (*Result, error) {
+			select {
+			case <-release:
+				return newResult([]byte("%PDF-shared")), nil
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+		})
+		leaderDone <- err
+	}()
+	time.Sleep(10 * time.Millisecond)
+
+	followerDone := make(chan *Result)
+	go func() {
+		res, err := g.do(context.Background(), "key", nil)
+		if err != nil {
+			t.Errorf("Follower got error %v", err)
+		}
+		followerDone <- res
+	}()
+	time.Sleep(10 * time.Millisecond)
+
+	cancelLeader()
+	if err := <-leaderDone; err != context.Canceled {
+		t.Errorf("Expected the leader to get context.Canceled, got %v", err)
+	}
+	close(release)
+	if res := <-followerDone; res == nil || string(res.PDF) != "%PDF-shared" {
+		t.Errorf("Expected the follower to get the shared result, got %+v", res)
+	}
+}
+
+func TestFlightGroupAbandoned(t *testing.T) {
+	g := &flightGroup{}
+	canceled := make(chan struct{})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		cancel()
+	}()
+	_, err := g.do(ctx, "key", func(ctx context.Context) (*Result, error) {
+		<-ctx.Done()
+		close(canceled)
+		return nil, ctx.Err()
+	})
+	if err != context.Canceled {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+	select {
+	case <-canceled:
+	case <-time.After(time.Second):
+		t.Fatal("Expected the conversion to be canceled once every caller left")
+	}
+
+	// A new caller starts a fresh conversion.
+	res, err := g.do(context.Background(), "key", func(context.Context) (*Result, error) {
+		return newResult([]byte("%PDF-fresh")), nil
+	})
+	if err != nil || string(res.PDF) != "%PDF-fresh" {
+		t.Errorf("Expected a fresh conversion, got %+v, %v", res, err)
+	}
+}
+
+func TestDedupKey(t *testing.T) {
+	base := getDefaultOptions()
+	other := getDefaultOptions()
+	WithChecksumMetadata(true)(other)
+
+	if dedupKey("<p>a</p>", base) != dedupKey("<p>a</p>", getDefaultOptions()) {
+		t.Error("Expected identical content and options to share a key")
+	}
+	if dedupKey("<p>a</p>", base) == dedupKey("<p>b</p>", base) {
+		t.Error("Expected different content to use different keys")
+	}
+	if dedupKey("<p>a</p>", base) == dedupKey("<p>a</p>", other) {
+		t.Error("Expected different options to use different keys")
+	}
+}
+
+func TestFlightGroupDeadline(t *testing.T) {
+	g := &flightGroup{}
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	want, _ := ctx.Deadline()
+
+	leaderDone := make(chan error)
+	go func() {
+		_, err := g.do(ctx, "key", func(ctx context.Context) (*Result, error) {
+			if got, ok := ctx.Deadline(); !ok || !got.Equal(want) {
+				t.Errorf("Expected the leader's deadline %v, got %v", want, got)
+			}
+			<-ctx.Done()
+			return nil, ctx.Err()
+		})
+		leaderDone <- err
+	}()
+	time.Sleep(5 * time.Millisecond)
+
+	// A follower without a deadline is not failed by the leader's.
+	res, err := g.do(context.Background(), "key", func(context.Context) (*Result, error) {
+		return newResult([]byte("%PDF-again")), nil
+	})
+	if err != nil || string(res.PDF) != "%PDF-again" {
+		t.Errorf("Expected the follower to convert again, got %+v, %v", res, err)
+	}
+	if err := <-leaderDone; err != context.DeadlineExceeded {
+		t.Errorf("Expected the leader to get context.DeadlineExceeded, got %v", err)
+	}
+}
+
+func TestDeduplicatedAssertions(t *testing.T) {
+	// Nothing is recorded, so only a caller that joins the held call gets a
+	// result.
+	dir := t.TempDir()
+	html := "<p>Total: $100.00</p>"
+	key := dedupKey(html, newOptions([]Option{WithFixtures(dir, FixtureReplay), WithDeduplication(true)}))
+	release := make(chan struct{})
+	go conversions.do(context.Background(), key, func(context.Context) (*Result, error) {
+		<-release
+		return newResult(testPDFWithContents(t, "BT /F1 12 Tf 72 720 Td (Total: $100.00) Tj ET")), nil
+	})
+	time.Sleep(20 * time.Millisecond)
+
+	require := func(s string) Option {
+		return WithPostRenderAssertion(func(doc TextIndex) error {
+			if !doc.Contains(s) {
+				return fmt.Errorf("%q not found", s)
+			}
+			return nil
+		})
+	}
+	run := func(want string) <-chan error {
+		done := make(chan error, 1)
+		go func() {
+			_, err := ConvertHtmlToResult(context.Background(), html, WithFixtures(dir, FixtureReplay), WithDeduplication(true), require(want))
+			done <- err
+		}()
+		return done
+	}
+	holds, fails := run("$100.00"), run("$999.00")
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+
+	if err := <-holds; err != nil {
+		t.Errorf("Expected the caller whose assertion holds to succeed, got %v", err)
+	}
+	if err := <-fails; !errors.Is(err, ErrAssertionFailed) {
+		t.Errorf("Expected ErrAssertionFailed for the other caller, got %v", err)
+	}
+}
+
+func TestDedupKeyRenderOptions(t *testing.T) {
+	html := "<p>a</p>"
+	base := dedupKey(html, getDefaultOptions())
+	tests := []struct {
+		name  string
+		opts  []Option
+		share bool
+	}{
+		{name: "assertion", opts: []Option{WithPostRenderAssertion(func(TextIndex) error { return nil })}, share: true},
+		{name: "signing key", opts: []Option{WithResultHMAC([]byte("key"))}, share: true},
+		{name: "deadline propagation", opts: []Option{WithDeadlinePropagation(time.Second)}},
+		{name: "screencast", opts: []Option{WithScreencast(t.TempDir())}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := dedupKey(html, newOptions(tt.opts)) == base; got != tt.share {
+				t.Errorf("Expected sharing the key to be %v, got %v", tt.share, got)
+			}
+		})
+	}
+}
+
+func TestSharesRender(t *testing.T) {
+	noop := chromedp.ActionFunc(func(context.Context) error { return nil })
+	tests := []struct {
+		name string
+		opts []Option
+		want bool
+	}{
+		{name: "disabled"},
+		{name: "enabled", opts: []Option{WithDeduplication(true)}, want: true},
+		{name: "pre-render actions", opts: []Option{WithDeduplication(true), WithPreRenderActions(noop)}},
+		{name: "post-render actions", opts: []Option{WithDeduplication(true), WithPostRenderActions(noop)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := newOptions(tt.opts).shares(); got != tt.want {
+				t.Errorf("shares() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDedupKeyBaseURL(t *testing.T) {
+	a := newOptions([]Option{WithBaseURL("https://a.example.com/")})
+	b := newOptions([]Option{WithBaseURL("https://b.example.com/")})
+	if dedupKey("<img src=\"logo.png\">", a) == dedupKey("<img src=\"logo.png\">", b) {
+		t.Error("Expected different base URLs to use different keys")
+	}
+}
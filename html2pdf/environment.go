@@ -0,0 +1,162 @@
+package html2pdf
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime/debug"
+	"sort"
+	"sync"
+
+	"github.com/chromedp/cdproto/browser"
+	"github.com/chromedp/cdproto/cdp"
+	"github.com/chromedp/chromedp"
+	"github.com/patipolchat/html2pdf/html2pdf/internal/pdf"
+)
+
+// modulePath is the module this package belongs to.
+const modulePath = "github.com/patipolchat/html2pdf"
+
+// environmentMetadataKeys are the document information entries written by
+// WithEnvironmentMetadata.
+const (
+	chromeVersionKey  = "RenderChromeVersion"
+	packageVersionKey = "RenderPackageVersion"
+	fontsHashKey      = "RenderFontsHash"
+	optionsHashKey    = "RenderOptionsHash"
+)
+
+// Environment describes what a document was rendered with, to explain
+// differences between documents generated months apart.
+type Environment struct {
+	// ChromeVersion is the browser product, e.g. "HeadlessChrome/126.0.6478.126".
+	ChromeVersion string
+	// PackageVersion is the version of this module, "(devel)" when built
+	// from a source checkout.
+	PackageVersion string
+	// FontsHash is the hex SHA-256 of the installed font files' names,
+	// sizes and modification times.
+	FontsHash string
+	// OptionsHash is the hex SHA-256 of the options that affect the output.
+	OptionsHash string
+}
+
+// WithEnvironmentMetadata writes Result.Environment into the document
+// information dictionary of the PDF.
+func WithEnvironmentMetadata(enabled bool) Option {
+	return func(o *options) {
+		o.environmentMetadata = enabled
+	}
+}
+
+// newEnvironment returns the environment for a conversion with options; the
+// Chrome version is filled in while rendering.
+func newEnvironment(options *options) *Environment {
+	sum := sha256.Sum256([]byte(options.fingerprint()))
+	return &Environment{
+		PackageVersion: packageVersion(),
+		FontsHash:      installedFontsHash(),
+		OptionsHash:    hex.EncodeToString(sum[:]),
+	}
+}
+
+// addEnvironmentMetadata records env in the document information dictionary.
+func addEnvironmentMetadata(env *Environment) func(*pdf.Document) error {
+	return func(doc *pdf.Document) error {
+		doc.SetInfo(chromeVersionKey, env.ChromeVersion)
+		doc.SetInfo(packageVersionKey, env.PackageVersion)
+		doc.SetInfo(fontsHashKey, env.FontsHash)
+		doc.SetInfo(optionsHashKey, env.OptionsHash)
+		return nil
+	}
+}
+
+// readChromeVersion stores the browser product in env.
+func readChromeVersion(env *Environment) chromedp.ActionFunc {
+	return func(ctx context.Context) error {
+		c := chromedp.FromContext(ctx)
+		if c == nil || c.Browser == nil {
+			return fmt.Errorf("no browser in context")
+		}
+		_, product, _, _, _, err := browser.GetVersion().Do(cdp.WithExecutor(ctx, c.Browser))
+		if err != nil {
+			return err
+		}
+		env.ChromeVersion = product
+		return nil
+	}
+}
+
+// packageVersion returns the version of this module from the build info.
+func packageVersion() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return "unknown"
+	}
+	if info.Main.Path == modulePath {
+		return info.Main.Version
+	}
+	for _, dep := range info.Deps {
+		if dep.Path == modulePath {
+			if dep.Replace != nil {
+				return dep.Replace.Version
+			}
+			return dep.Version
+		}
+	}
+	return "unknown"
+}
+
+// fontDirs are the directories searched for installed fonts.
+var fontDirs = func() []string {
+	dirs := []string{"/usr/share/fonts", "/usr/local/share/fonts", "/Library/Fonts", "/System/Library/Fonts"}
+	if home, err := os.UserHomeDir(); err == nil {
+		dirs = append(dirs, filepath.Join(home, ".fonts"), filepath.Join(home, ".local/share/fonts"), filepath.Join(home, "Library/Fonts"))
+	}
+	if windir := os.Getenv("WINDIR"); windir != "" {
+		dirs = append(dirs, filepath.Join(windir, "Fonts"))
+	}
+	return dirs
+}()
+
+var (
+	fontsHash     string
+	fontsHashOnce sync.Once
+)
+
+// installedFontsHash returns the hash of the installed fonts, computed once.
+func installedFontsHash() string {
+	fontsHashOnce.Do(func() {
+		fontsHash = hashFontDirs(fontDirs)
+	})
+	return fontsHash
+}
+
+// hashFontDirs hashes the path, size and modification time of every file
+// below dirs. Missing directories are skipped.
+func hashFontDirs(dirs []string) string {
+	var entries []string
+	for _, dir := range dirs {
+		filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+			if err != nil || d.IsDir() {
+				return nil
+			}
+			info, err := d.Info()
+			if err != nil {
+				return nil
+			}
+			entries = append(entries, fmt.Sprintf("%s\x00%d\x00%d", path, info.Size(), info.ModTime().Unix()))
+			return nil
+		})
+	}
+	sort.Strings(entries)
+	h := sha256.New()
+	for _, e := range entries {
+		fmt.Fprintln(h, e)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,65 @@
+package html2pdf
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/patipolchat/html2pdf/html2pdf/internal/pdf"
+)
+
+func TestHashFontDirs(t *testing.T) {
+	dir := t.TempDir()
+	font := filepath.Join(dir, "truetype", "DejaVuSans.ttf")
+	os.MkdirAll(filepath.Dir(font), 0o755)
+	os.WriteFile(font, []byte("font"), 0o644)
+
+	missing := filepath.Join(dir, "missing")
+	first := hashFontDirs([]string{dir, missing})
+	if len(first) != 64 {
+		t.Fatalf("Expected a hex SHA-256, got %q", first)
+	}
+	if again := hashFontDirs([]string{missing, dir}); again != first {
+		t.Error("Expected the hash to be stable")
+	}
+
+	os.WriteFile(font, []byte("updated font"), 0o644)
+	os.Chtimes(font, time.Now(), time.Now().Add(time.Hour))
+	if changed := hashFontDirs([]string{dir}); changed == first {
+		t.Error("Expected the hash to change when a font changes")
+	}
+}
+
+func TestNewEnvironment(t *testing.T) {
+	env := newEnvironment(getDefaultOptions())
+	if env.PackageVersion == "" || len(env.FontsHash) != 64 || len(env.OptionsHash) != 64 {
+		t.Errorf("Incomplete environment: %+v", env)
+	}
+	if other := newEnvironment(newOptions([]Option{WithPaperSize(A4)})); other.OptionsHash == env.OptionsHash {
+		t.Error("Expected options that change the output to change the options hash")
+	}
+}
+
+func TestAddEnvironmentMetadata(t *testing.T) {
+	env := &Environment{ChromeVersion: "HeadlessChrome/126.0", PackageVersion: "v1.2.3", FontsHash: "ab", OptionsHash: "cd"}
+	buf, err := editPDF(testPDF(t, 1), addEnvironmentMetadata(env))
+	if err != nil {
+		t.Fatalf("editPDF() error = %v", err)
+	}
+	doc, err := pdf.Parse(buf)
+	if err != nil {
+		t.Fatalf("pdf.Parse() error = %v", err)
+	}
+	info := doc.Info()
+	for key, want := range map[string]string{
+		chromeVersionKey:  "HeadlessChrome/126.0",
+		packageVersionKey: "v1.2.3",
+		fontsHashKey:      "ab",
+		optionsHashKey:    "cd",
+	} {
+		if got, _ := info[pdf.Name(key)].(pdf.String); string(got) != want {
+			t.Errorf("Expected %s = %q, got %q", key, want, got)
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package html2pdf
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/chromedp/cdproto/page"
+	"github.com/chromedp/chromedp"
+)
+
+// layoutScript returns the vertical positions, in CSS pixels from the top of
+// the document, of forced page breaks before or after elements and of the
+// bottom of the lowest element.
+const layoutScript = `(() => {
+	const forced = (v) => ["page", "always", "left", "right", "recto", "verso"].includes(v);
+	const breaks = [];
+	let end = 0;
+	for (const el of document.body ? document.body.querySelectorAll("*") : []) {
+		const style = getComputedStyle(el);
+		if (style.display === "none") {
+			continue;
+		}
+		const rect = el.getBoundingClientRect();
+		end = Math.max(end, rect.bottom + window.scrollY);
+		if (forced(style.breakBefore) || forced(style.pageBreakBefore)) {
+			breaks.push(rect.top + window.scrollY);
+		}
+		if (forced(style.breakAfter) || forced(style.pageBreakAfter)) {
+			breaks.push(rect.bottom + window.scrollY);
+		}
+	}
+	return {breaks, end};
+})()`
+
+// pageLayout is the result of layoutScript.
+type pageLayout struct {
+	Breaks []float64 `json:"breaks"`
+	End    float64   `json:"end"`
+}
+
+// EstimatePageCount lays out the HTML content as ConvertHtmlToPdf would and
+// estimates the number of pages from the content height and forced page
+// breaks, without printing. It is cheaper than converting, e.g. for "about
+// 42 pages" hints, but may differ from the printed document by a page or
+// so where content is kept together across page boundaries.
+func EstimatePageCount(ctx context.Context, htmlContent string, opts ...Option) (int, error) {
+	options := newOptions(opts)
+	htmlContent, err := applyStarterTemplate(htmlContent, options)
+	if err != nil {
+		return 0, err
+	}
+	var pages int
+	options.pageEstimate = &pages
+	res, err := convert(ctx, document{html: htmlContent}, options)
+	if err != nil {
+		return 0, err
+	}
+	if pages == 0 {
+		// Replayed from a fixture, which holds the printed document.
+		infos, err := res.PageInfos()
+		if err != nil {
+			return 0, fmt.Errorf("failed to count pages: %w", err)
+		}
+		return len(infos), nil
+	}
+	return pages, nil
+}
+
+// estimatePages measures the laid-out page at the printable width of params
+// and estimates how many pages it prints on.
+func estimatePages(ctx context.Context, params *page.PrintToPDFParams) (int, error) {
+	paperW, paperH := paperDimensions(params)
+	scale := printScale(params)
+	width := (paperW - params.MarginLeft - params.MarginRight) * cssPixelsPerInch / scale
+	height := (paperH - params.MarginTop - params.MarginBottom) * cssPixelsPerInch / scale
+	if _, _, err := measureContent(ctx, width); err != nil {
+		return 0, err
+	}
+	var layout pageLayout
+	if err := chromedp.Evaluate(layoutScript, &layout).Do(ctx); err != nil {
+		return 0, err
+	}
+	return pageCount(layout.End, height, layout.Breaks), nil
+}
+
+// pageCount splits content ending at contentH at the forced breaks and
+// counts the pages each part fills. Every part takes at least one page;
+// breaks at the very start or end of the content add none.
+func pageCount(contentH, pageH float64, breaks []float64) int {
+	sort.Float64s(breaks)
+	pages, prev := 0, 0.0
+	segment := func(end float64) {
+		// A pixel of slack absorbs rounding at exact page multiples.
+		pages += int(math.Max(1, math.Ceil((end-prev-1)/pageH)))
+		prev = end
+	}
+	for _, b := range breaks {
+		if b > prev && b < contentH {
+			segment(b)
+		}
+	}
+	segment(contentH)
+	return pages
+}
@@ -0,0 +1,66 @@
+package html2pdf
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestPageCount(t *testing.T) {
+	const pageH = 1000
+
+	tests := []struct {
+		name     string
+		contentH float64
+		breaks   []float64
+		want     int
+	}{
+		{name: "empty", contentH: 0, want: 1},
+		{name: "short", contentH: 300, want: 1},
+		{name: "exactly one page", contentH: 1000.4, want: 1},
+		{name: "just over", contentH: 1002, want: 2},
+		{name: "several pages", contentH: 4200, want: 5},
+		{name: "forced breaks", contentH: 900, breaks: []float64{300, 600}, want: 3},
+		{name: "unsorted breaks", contentH: 2500, breaks: []float64{1800, 200}, want: 4},
+		{name: "breaks at the edges", contentH: 500, breaks: []float64{0, 500}, want: 1},
+		{name: "duplicate breaks", contentH: 500, breaks: []float64{250, 250}, want: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := pageCount(tt.contentH, pageH, tt.breaks); got != tt.want {
+				t.Errorf("Expected %d pages, got %d", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestEstimatePageCount(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	tall := `<div style="height: 2500px">Tall</div>`
+	tests := []struct {
+		name string
+		html string
+		opts []Option
+		want int
+	}{
+		{name: "single page", html: "<p>Hello</p>", want: 1},
+		{name: "tall content", html: tall, want: 3},
+		{name: "page break markers", html: "<p>One</p><div data-html2pdf-break></div><p>Two</p>", want: 2},
+		{name: "fit to single page", html: tall, opts: []Option{WithFitToSinglePage(true)}, want: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := EstimatePageCount(ctx, tt.html, tt.opts...)
+			if err != nil {
+				t.Fatalf("EstimatePageCount() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Expected %d pages, got %d", tt.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,73 @@
+package html2pdf
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"github.com/chromedp/cdproto/page"
+)
+
+// ErrContentTooLarge is returned by WithFitToSinglePage when the content does
+// not fit on one page even at the minimum scale.
+var ErrContentTooLarge = fmt.Errorf("content too large to fit on a single page")
+
+// WithFitToSinglePage scales the content down so the document fits on a
+// single page, e.g. for certificates and labels. Content is never scaled up.
+// It has no effect together with WithRollPaper.
+func WithFitToSinglePage(enabled bool) Option {
+	return func(o *options) {
+		o.fitToSinglePage = enabled
+	}
+}
+
+// fitSinglePage measures the page and lowers params.Scale until the content
+// fits the printable area.
+func fitSinglePage(ctx context.Context, params *page.PrintToPDFParams) error {
+	paperW, paperH := paperDimensions(params)
+	printableW := (paperW - params.MarginLeft - params.MarginRight) * cssPixelsPerInch
+	printableH := (paperH - params.MarginTop - params.MarginBottom) * cssPixelsPerInch
+	contentW, contentH, err := measureContent(ctx, printableW)
+	if err != nil {
+		return err
+	}
+	scale, err := fitScale(contentW, contentH, printableW, printableH)
+	if err != nil {
+		return err
+	}
+	params.Scale = math.Min(printScale(params), scale)
+	return nil
+}
+
+// paperDimensions returns the paper width and height of params in inches,
+// applying Chrome's Letter default and the orientation.
+func paperDimensions(params *page.PrintToPDFParams) (width, height float64) {
+	width, height = Letter.Width, Letter.Height
+	if params.PaperWidth > 0 {
+		width = params.PaperWidth
+	}
+	if params.PaperHeight > 0 {
+		height = params.PaperHeight
+	}
+	if params.Landscape {
+		width, height = height, width
+	}
+	return width, height
+}
+
+// fitScale returns the largest scale, at most 1, at which content of the
+// given size fits the printable area.
+func fitScale(contentW, contentH, printableW, printableH float64) (float64, error) {
+	scale := 1.0
+	// One extra pixel keeps rounding from spilling onto a second page.
+	if contentW > 0 {
+		scale = math.Min(scale, printableW/(math.Ceil(contentW)+1))
+	}
+	if contentH > 0 {
+		scale = math.Min(scale, printableH/(math.Ceil(contentH)+1))
+	}
+	if scale < minScale {
+		return 0, fmt.Errorf("%w: needs scale %.3f, minimum is %g", ErrContentTooLarge, scale, minScale)
+	}
+	return scale, nil
+}
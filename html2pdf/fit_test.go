@@ -0,0 +1,51 @@
+package html2pdf
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/chromedp/cdproto/page"
+)
+
+func TestFitScale(t *testing.T) {
+	tests := []struct {
+		name                   string
+		contentW, contentH     float64
+		printableW, printableH float64
+		want                   float64
+		wantErr                error
+	}{
+		{name: "already fits", contentW: 500, contentH: 700, printableW: 720, printableH: 960, want: 1},
+		{name: "too tall", contentW: 500, contentH: 1919, printableW: 720, printableH: 960, want: 0.5},
+		{name: "too wide", contentW: 1439, contentH: 100, printableW: 720, printableH: 960, want: 0.5},
+		{name: "below minimum", contentW: 500, contentH: 20000, printableW: 720, printableH: 960, wantErr: ErrContentTooLarge},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := fitScale(tt.contentW, tt.contentH, tt.printableW, tt.printableH)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Expected error %v, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("fitScale() error = %v", err)
+			}
+			if math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("Expected scale %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestPaperDimensions(t *testing.T) {
+	if w, h := paperDimensions(page.PrintToPDF()); w != 8.5 || h != 11 {
+		t.Errorf("Expected Letter default, got %vx%v", w, h)
+	}
+	if w, h := paperDimensions(page.PrintToPDF().WithPaperWidth(4).WithPaperHeight(6).WithLandscape(true)); w != 6 || h != 4 {
+		t.Errorf("Expected landscape 6x4, got %vx%v", w, h)
+	}
+}
@@ -0,0 +1,92 @@
+package html2pdf
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// ErrFixtureNotFound is returned in FixtureReplay mode when no fixture was
+// recorded for a conversion.
+var ErrFixtureNotFound = fmt.Errorf("fixture not found")
+
+// FixtureMode selects whether WithFixtures records or replays conversions.
+type FixtureMode int
+
+const (
+	// FixtureRecord converts with Chrome and saves each result as a fixture.
+	FixtureRecord FixtureMode = iota + 1
+	// FixtureReplay returns saved fixtures without starting Chrome.
+	FixtureReplay
+)
+
+// WithFixtures records conversion results to dir, or replays them from dir,
+// so test suites can run where Chrome cannot be installed. Fixtures are keyed
+// by the document content and the options that affect the output; record
+// them once with FixtureRecord and commit the directory.
+func WithFixtures(dir string, mode FixtureMode) Option {
+	return func(o *options) {
+		o.fixtureDir = dir
+		o.fixtureMode = mode
+	}
+}
+
+// fixturePath returns the fixture file for doc converted with o.
+func fixturePath(doc document, o *options) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\x00%s\x00%s", doc.url, doc.html, o.fingerprint())
+	return filepath.Join(o.fixtureDir, hex.EncodeToString(h.Sum(nil))+".json")
+}
+
+// replayFixture loads the recorded result for doc.
+func replayFixture(doc document, o *options) (*Result, error) {
+	path := fixturePath(doc, o)
+	b, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("%w: %s", ErrFixtureNotFound, path)
+		}
+		return nil, fmt.Errorf("failed to read fixture: %w", err)
+	}
+	var res Result
+	if err := json.Unmarshal(b, &res); err != nil {
+		return nil, fmt.Errorf("failed to decode fixture %s: %w", path, err)
+	}
+	return &res, nil
+}
+
+// recordFixture saves res as the fixture for doc.
+func recordFixture(doc document, o *options, res *Result) error {
+	b, err := json.MarshalIndent(res, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode fixture: %w", err)
+	}
+	if err := os.MkdirAll(o.fixtureDir, 0o755); err != nil {
+		return fmt.Errorf("failed to create fixture directory: %w", err)
+	}
+	// Concurrent recordings of the same conversion each write a complete
+	// file and rename it into place, so replays never see a partial one.
+	path := fixturePath(doc, o)
+	tmp, err := os.CreateTemp(o.fixtureDir, filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to write fixture: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(b); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write fixture: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write fixture: %w", err)
+	}
+	if err := os.Chmod(tmp.Name(), 0o644); err != nil {
+		return fmt.Errorf("failed to write fixture: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to write fixture: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+package html2pdf
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestFixtureReplay(t *testing.T) {
+	dir := t.TempDir()
+	html := "<html><body><h1>Fixture</h1></body></html>"
+
+	opts := newOptions([]Option{WithFixtures(dir, FixtureRecord), WithTrimBlankPages(true)})
+	recorded := newResult([]byte("%PDF-1.4 recorded"))
+	if err := recordFixture(document{html: html}, opts, recorded); err != nil {
+		t.Fatalf("recordFixture() error = %v", err)
+	}
+
+	ctx := context.Background()
+	res, err := ConvertHtmlToResult(ctx, html, WithFixtures(dir, FixtureReplay), WithTrimBlankPages(true))
+	if err != nil {
+		t.Fatalf("ConvertHtmlToResult() error = %v", err)
+	}
+	if string(res.PDF) != string(recorded.PDF) || res.SHA256 != recorded.SHA256 {
+		t.Errorf("Expected the recorded result, got %+v", res)
+	}
+
+	tests := []struct {
+		name string
+		html string
+		opts []Option
+	}{
+		{name: "different content", html: "<p>other</p>"},
+		{name: "different options", html: html},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ConvertHtmlToPdf(ctx, tt.html, append(tt.opts, WithFixtures(dir, FixtureReplay))...)
+			if !errors.Is(err, ErrFixtureNotFound) {
+				t.Errorf("Expected ErrFixtureNotFound, got %v", err)
+			}
+		})
+	}
+}
+
+func TestFixturePathIgnoresFixtureOptions(t *testing.T) {
+	doc := document{html: "<p>x</p>"}
+	record := newOptions([]Option{WithFixtures("dir", FixtureRecord)})
+	replay := newOptions([]Option{WithFixtures("dir", FixtureReplay)})
+	if fixturePath(doc, record) != fixturePath(doc, replay) {
+		t.Error("Expected recording and replaying to use the same fixture path")
+	}
+}
+
+func TestFixtureReplayAssertions(t *testing.T) {
+	dir := t.TempDir()
+	html := "<p>Total: $100.00</p>"
+	recorded := newResult(testPDFWithContents(t, "BT /F1 12 Tf 72 720 Td (Total: $100.00) Tj ET"))
+	if err := recordFixture(document{html: html}, newOptions([]Option{WithFixtures(dir, FixtureRecord)}), recorded); err != nil {
+		t.Fatalf("recordFixture() error = %v", err)
+	}
+	require := func(s string) Option {
+		return WithPostRenderAssertion(func(doc TextIndex) error {
+			if !doc.Contains(s) {
+				return errors.New("missing " + s)
+			}
+			return nil
+		})
+	}
+
+	ctx := context.Background()
+	if _, err := ConvertHtmlToPdf(ctx, html, WithFixtures(dir, FixtureReplay), require("Total: $100.00")); err != nil {
+		t.Errorf("ConvertHtmlToPdf() error = %v", err)
+	}
+	if _, err := ConvertHtmlToPdf(ctx, html, WithFixtures(dir, FixtureReplay), require("$999.00")); !errors.Is(err, ErrAssertionFailed) {
+		t.Errorf("Expected ErrAssertionFailed, got %v", err)
+	}
+}
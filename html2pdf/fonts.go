@@ -0,0 +1,217 @@
+package html2pdf
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/chromedp/cdproto/css"
+	"github.com/chromedp/cdproto/dom"
+	"github.com/chromedp/chromedp"
+)
+
+// maxFontReportNodes limits how many elements are inspected for a font report.
+const maxFontReportNodes = 5000
+
+// genericFamilies are the CSS generic font families, which are resolved to a
+// platform font and never appear in the used fonts by name.
+var genericFamilies = map[string]bool{
+	"serif": true, "sans-serif": true, "monospace": true, "cursive": true,
+	"fantasy": true, "system-ui": true, "emoji": true, "math": true, "fangsong": true,
+	"ui-serif": true, "ui-sans-serif": true, "ui-monospace": true, "ui-rounded": true,
+}
+
+// FontReport compares the fonts a document asks for with the fonts Chrome
+// actually rendered it with.
+type FontReport struct {
+	// Requested lists the font families named in font-family declarations
+	// of elements with text, sorted.
+	Requested []string
+	// Used lists the platform fonts that rendered glyphs, most glyphs first.
+	Used []FontUsage
+	// Unused lists requested families, other than generic ones, that no
+	// glyph was rendered with, e.g. web fonts that failed to load.
+	Unused []string
+	// Fallbacks lists text that was rendered with a font outside its
+	// font-family list because the requested fonts lack the glyphs.
+	Fallbacks []FontFallback
+}
+
+// FontUsage is a platform font and how many glyphs it rendered.
+type FontUsage struct {
+	Family         string
+	PostScriptName string
+	// Custom reports whether the font was downloaded as a web font.
+	Custom bool
+	Glyphs int
+}
+
+// FontFallback records characters rendered with a substitute font.
+type FontFallback struct {
+	// Requested is the font-family value of the text.
+	Requested string
+	// Used is the family of the substitute font.
+	Used string
+	// Characters holds the distinct non-ASCII characters of the affected text.
+	Characters string
+}
+
+// WithFontReport fills Result.FontReport with the fonts requested and used
+// while rendering, so missing fonts and glyph substitution show up in logs
+// rather than in delivered documents.
+func WithFontReport(enabled bool) Option {
+	return func(o *options) {
+		o.fontReport = enabled
+	}
+}
+
+// fontNode is an element with direct text and the fonts that rendered it.
+type fontNode struct {
+	Family string `json:"family"`
+	Text   string `json:"text"`
+	Fonts  []*css.PlatformFontUsage
+}
+
+// fontNodesScript returns the font-family and direct text of the elements
+// matched by "body, body *", in document order.
+const fontNodesScript = `Array.from(document.querySelectorAll("body, body *")).slice(0, %d).map((el) => ({
+	family: getComputedStyle(el).fontFamily,
+	text: Array.from(el.childNodes).filter((n) => n.nodeType === Node.TEXT_NODE).map((n) => n.data).join("").trim(),
+}))`
+
+// collectFontReport inspects the rendered fonts of every element with text.
+func collectFontReport(ctx context.Context, report *FontReport) error {
+	if err := dom.Enable().Do(ctx); err != nil {
+		return err
+	}
+	if err := css.Enable().Do(ctx); err != nil {
+		return err
+	}
+	root, err := dom.GetDocument().Do(ctx)
+	if err != nil {
+		return err
+	}
+	ids, err := dom.QuerySelectorAll(root.NodeID, "body, body *").Do(ctx)
+	if err != nil {
+		return err
+	}
+	var nodes []fontNode
+	if err := chromedp.Evaluate(fmt.Sprintf(fontNodesScript, maxFontReportNodes), &nodes).Do(ctx); err != nil {
+		return err
+	}
+	if len(ids) > len(nodes) {
+		ids = ids[:len(nodes)]
+	}
+	for i, id := range ids {
+		if nodes[i].Text == "" {
+			continue
+		}
+		if nodes[i].Fonts, err = css.GetPlatformFontsForNode(id).Do(ctx); err != nil {
+			return err
+		}
+	}
+	*report = buildFontReport(nodes)
+	return nil
+}
+
+// buildFontReport aggregates the fonts of nodes.
+func buildFontReport(nodes []fontNode) FontReport {
+	requested := map[string]bool{}
+	used := map[string]*FontUsage{}
+	usedFamilies := map[string]bool{}
+	fallbacks := map[[2]string]map[rune]bool{}
+
+	for _, n := range nodes {
+		if n.Text == "" {
+			continue
+		}
+		families, generic := parseFontFamily(n.Family)
+		inStack := map[string]bool{}
+		for _, f := range families {
+			requested[f] = true
+			inStack[strings.ToLower(f)] = true
+		}
+		matched := false
+		for _, f := range n.Fonts {
+			if inStack[strings.ToLower(f.FamilyName)] {
+				matched = true
+			}
+		}
+		for _, f := range n.Fonts {
+			key := f.FamilyName + "\x00" + f.PostScriptName
+			if used[key] == nil {
+				used[key] = &FontUsage{Family: f.FamilyName, PostScriptName: f.PostScriptName, Custom: f.IsCustomFont}
+			}
+			used[key].Glyphs += int(f.GlyphCount)
+			usedFamilies[strings.ToLower(f.FamilyName)] = true
+
+			// A font outside the list substitutes for missing glyphs when
+			// another font of the list rendered part of the text, or when
+			// the list has no generic family that could resolve to it.
+			if !inStack[strings.ToLower(f.FamilyName)] && (matched || !generic) {
+				fb := [2]string{n.Family, f.FamilyName}
+				if fallbacks[fb] == nil {
+					fallbacks[fb] = map[rune]bool{}
+				}
+				for _, r := range n.Text {
+					if r > 0x7e {
+						fallbacks[fb][r] = true
+					}
+				}
+			}
+		}
+	}
+
+	var report FontReport
+	for f := range requested {
+		report.Requested = append(report.Requested, f)
+		if !usedFamilies[strings.ToLower(f)] {
+			report.Unused = append(report.Unused, f)
+		}
+	}
+	sort.Strings(report.Requested)
+	sort.Strings(report.Unused)
+	for _, u := range used {
+		report.Used = append(report.Used, *u)
+	}
+	sort.Slice(report.Used, func(i, j int) bool {
+		if report.Used[i].Glyphs != report.Used[j].Glyphs {
+			return report.Used[i].Glyphs > report.Used[j].Glyphs
+		}
+		return report.Used[i].PostScriptName < report.Used[j].PostScriptName
+	})
+	for fb, chars := range fallbacks {
+		runes := make([]rune, 0, len(chars))
+		for r := range chars {
+			runes = append(runes, r)
+		}
+		sort.Slice(runes, func(i, j int) bool { return runes[i] < runes[j] })
+		report.Fallbacks = append(report.Fallbacks, FontFallback{Requested: fb[0], Used: fb[1], Characters: string(runes)})
+	}
+	sort.Slice(report.Fallbacks, func(i, j int) bool {
+		a, b := report.Fallbacks[i], report.Fallbacks[j]
+		if a.Requested != b.Requested {
+			return a.Requested < b.Requested
+		}
+		return a.Used < b.Used
+	})
+	return report
+}
+
+// parseFontFamily splits a computed font-family value into its named
+// families and reports whether it includes a generic family.
+func parseFontFamily(value string) (families []string, generic bool) {
+	for _, f := range strings.Split(value, ",") {
+		f = strings.Trim(strings.TrimSpace(f), `"'`)
+		if f == "" {
+			continue
+		}
+		if genericFamilies[strings.ToLower(f)] {
+			generic = true
+			continue
+		}
+		families = append(families, f)
+	}
+	return families, generic
+}
@@ -0,0 +1,63 @@
+package html2pdf
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/chromedp/cdproto/css"
+)
+
+func TestParseFontFamily(t *testing.T) {
+	families, generic := parseFontFamily(`"Open Sans", Arial, 'Noto Sans', sans-serif`)
+	if want := []string{"Open Sans", "Arial", "Noto Sans"}; !reflect.DeepEqual(families, want) || !generic {
+		t.Errorf("Expected %v with generic, got %v, %v", want, families, generic)
+	}
+	if _, generic := parseFontFamily("Inter"); generic {
+		t.Error("Expected no generic family")
+	}
+}
+
+func TestBuildFontReport(t *testing.T) {
+	nodes := []fontNode{
+		{
+			Family: `Inter, sans-serif`,
+			Text:   "Total: 12 €",
+			Fonts: []*css.PlatformFontUsage{
+				{FamilyName: "Inter", PostScriptName: "Inter-Regular", IsCustomFont: true, GlyphCount: 10},
+				{FamilyName: "DejaVu Sans", PostScriptName: "DejaVuSans", GlyphCount: 1},
+			},
+		},
+		{
+			Family: `"Brand Font", serif`,
+			Text:   "Heading",
+			Fonts:  []*css.PlatformFontUsage{{FamilyName: "DejaVu Serif", PostScriptName: "DejaVuSerif", GlyphCount: 7}},
+		},
+		{
+			Family: `Inter`,
+			Text:   "日本",
+			Fonts: []*css.PlatformFontUsage{
+				{FamilyName: "Noto Sans CJK JP", PostScriptName: "NotoSansCJKjp-Regular", GlyphCount: 2},
+			},
+		},
+		{Family: `Unused`, Text: ""},
+	}
+
+	got := buildFontReport(nodes)
+	want := FontReport{
+		Requested: []string{"Brand Font", "Inter"},
+		Used: []FontUsage{
+			{Family: "Inter", PostScriptName: "Inter-Regular", Custom: true, Glyphs: 10},
+			{Family: "DejaVu Serif", PostScriptName: "DejaVuSerif", Glyphs: 7},
+			{Family: "Noto Sans CJK JP", PostScriptName: "NotoSansCJKjp-Regular", Glyphs: 2},
+			{Family: "DejaVu Sans", PostScriptName: "DejaVuSans", Glyphs: 1},
+		},
+		Unused: []string{"Brand Font"},
+		Fallbacks: []FontFallback{
+			{Requested: "Inter", Used: "Noto Sans CJK JP", Characters: "日本"},
+			{Requested: "Inter, sans-serif", Used: "DejaVu Sans", Characters: "€"},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected report\n%+v\ngot\n%+v", want, got)
+	}
+}
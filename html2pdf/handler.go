@@ -0,0 +1,45 @@
+package html2pdf
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// ConvertHandlerToPdf serves h on a loopback listener and converts the page
+// it returns for req, so a route renders with its own CSS and scripts without
+// being exposed publicly. The path and query of req select the page, and its
+// headers (e.g. cookies or authorization) are added to every request the page
+// makes to h. The page is always fetched with GET.
+func ConvertHandlerToPdf(ctx context.Context, h http.Handler, req *http.Request, opts ...Option) ([]byte, error) {
+	srv, err := newAssetServer(withHeaders(h, req.Header))
+	if err != nil {
+		return nil, fmt.Errorf("failed to start asset server: %w", err)
+	}
+	defer srv.Close()
+
+	u := *req.URL
+	u.Scheme = "http"
+	u.Host = srv.listener.Addr().String()
+	res, err := convert(ctx, document{url: u.String()}, newOptions(opts))
+	if err != nil {
+		return nil, err
+	}
+	return res.PDF, nil
+}
+
+// withHeaders wraps h so that each request carries header, except for keys
+// Chrome already set.
+func withHeaders(h http.Handler, header http.Header) http.Handler {
+	if len(header) == 0 {
+		return h
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		for k, v := range header {
+			if _, ok := r.Header[k]; !ok {
+				r.Header[k] = v
+			}
+		}
+		h.ServeHTTP(w, r)
+	})
+}
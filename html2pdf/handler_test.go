@@ -0,0 +1,59 @@
+package html2pdf
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestWithHeaders(t *testing.T) {
+	var got http.Header
+	h := withHeaders(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header
+	}), http.Header{
+		"Authorization": {"Bearer token"},
+		"Accept":        {"text/html"},
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/report", nil)
+	req.Header.Set("Accept", "text/css")
+	h.ServeHTTP(httptest.NewRecorder(), req)
+
+	if v := got.Get("Authorization"); v != "Bearer token" {
+		t.Errorf("Expected forwarded Authorization header, got %q", v)
+	}
+	if v := got.Get("Accept"); v != "text/css" {
+		t.Errorf("Expected request's own Accept header to win, got %q", v)
+	}
+}
+
+func TestConvertHandlerToPdf(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/style.css", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/css")
+		w.Write([]byte("h1 { color: #2c3e50; }"))
+	})
+	mux.HandleFunc("/report", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") == "" {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(`<html><head><link rel="stylesheet" href="/style.css"></head><body><h1>` + r.URL.Query().Get("title") + `</h1></body></html>`))
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	req := httptest.NewRequest(http.MethodGet, "/report?title=Monthly", nil)
+	req.Header.Set("Authorization", "Bearer token")
+	got, err := ConvertHandlerToPdf(ctx, mux, req)
+	if err != nil {
+		t.Fatalf("ConvertHandlerToPdf() error = %v", err)
+	}
+	if len(got) < 4 || string(got[:4]) != "%PDF" {
+		t.Error("Expected output to be a PDF")
+	}
+}
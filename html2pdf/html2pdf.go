@@ -2,10 +2,16 @@ package html2pdf
 
 import (
 	"context"
+	"crypto/sha256"
+	"errors"
 	"fmt"
+	"html/template"
+	"io"
 	"log"
 	"os"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/chromedp/cdproto/page"
 	"github.com/chromedp/chromedp"
@@ -14,27 +20,246 @@ import (
 var (
 	// ErrHTMLFileNotFound is returned when the specified file does not exist.
 	ErrHTMLFileNotFound = fmt.Errorf("html file not found")
+	// ErrCPUBudgetExceeded is returned when page scripts run longer than the budget set with WithCPUBudget.
+	ErrCPUBudgetExceeded = fmt.Errorf("cpu budget exceeded")
 )
 
 // Option represents a configuration option for the PDF conversion functions.
 type Option func(*options)
 
 type options struct {
-	logger func(string, ...interface{})
+	logger                 func(string, ...interface{})
+	cpuBudget              time.Duration
+	checksumMetadata       bool
+	deduplicate            bool
+	printParams            []func(*page.PrintToPDFParams)
+	preRender              []chromedp.Action
+	postRender             []chromedp.Action
+	pageBreakSelector      string
+	sections               map[PageRange]HeaderFooter
+	trimBlankPages         bool
+	pageCallback           func(docIndex, pageInDoc, absolutePage int)
+	screencastDir          string
+	backgroundColors       bool
+	backgroundGraphics     bool
+	rollPaperWidth         float64
+	fitToSinglePage        bool
+	mailMergeName          string
+	mailMergeCombined      bool
+	starterTemplate        string
+	pageBackground         []byte
+	pageBackgroundFit      FitMode
+	redactSelectors        []string
+	rasterDPI              int
+	rasterText             bool
+	failOnOverflow         bool
+	fontReport             bool
+	qualityReport          bool
+	fixtureDir             string
+	fixtureMode            FixtureMode
+	breakerThreshold       int
+	breakerCooldown        time.Duration
+	propagateDeadline      bool
+	deadlineReserve        time.Duration
+	dataSchema             []byte
+	assertions             []func(TextIndex) error
+	paperSize              *PaperSize
+	environmentMetadata    bool
+	margins                Margins
+	landscape              bool
+	classification         Classification
+	classificationPosition BannerPosition
+	scale                  float64
+	retentionExpiry        time.Time
+	retentionPolicyID      string
+	pageRanges             string
+	hardenedSandbox        bool
+	userNamespace          bool
+	imageResolution        ImageResolutionPolicy
+	svgDPI                 int
+	svgVectors             bool
+	canvasScale            float64
+	iframePolicy           IframePolicy
+	viewportUnitFix        bool
+	streamTransfer         bool
+	typographyFixes        bool
+	baseURL                string
+	assetDir               string
+	translations           Translations
+	language               string
+	templateGlob           string
+	customFuncs            template.FuncMap
+	markdownTheme          *string
+	hmacKey                []byte
+	batchConcurrency       int
+	csvRowMapper           func(map[string]string) (map[string]interface{}, error)
+	coverHTML              string
+	bufferPool             bool
+	prependPDFs            []stitchedPDF
+	appendPDFs             []stitchedPDF
+	headerTemplate         string
+	footerTemplate         string
+	preferCSSPageSize      bool
+
+	// pageEstimate receives the page count when only the layout is wanted.
+	pageEstimate *int
+	// browser, when set, is a running browser to open the tab in.
+	browser context.Context
+	// pdfStream, when set, receives the PDF as it is read from Chrome.
+	pdfStream *pdfStream
+	// ownedPDF is the first byte of the buffer the conversion may return to
+	// the pool, see owns.
+	ownedPDF *byte
+}
+
+// fingerprint describes the options that affect the generated PDF. Options
+// holding functions or actions, such as the logger, are not part of the fingerprint.
+func (o *options) fingerprint() string {
+	return fmt.Sprintf("cpuBudget=%v checksumMetadata=%v print=%+v css=%q sections=%v trimBlankPages=%v fitToSinglePage=%v background=%x/%d redact=%q rasterDPI=%d rasterText=%v failOnOverflow=%v fontReport=%v qualityReport=%v environmentMetadata=%v classification=%q/%d retention=%s/%q imageResolution=%d svgDPI=%d canvasScale=%g iframePolicy=%d viewportUnitFix=%v baseURL=%q assetDir=%q cover=%x prepend=%v append=%v",
+		o.cpuBudget, o.checksumMetadata, *o.printToPDFParams(), o.injectedCSS(), o.sections, o.trimBlankPages, o.fitToSinglePage,
+		sha256.Sum256(o.pageBackground), o.pageBackgroundFit, o.redactSelectors, o.rasterDPI, o.rasterText, o.failOnOverflow, o.fontReport, o.qualityReport, o.environmentMetadata,
+		o.classification, o.classificationPosition, o.retentionExpiry.UTC().Format(time.RFC3339), o.retentionPolicyID,
+		o.imageResolution, o.svgDPI, o.canvasScale, o.iframePolicy, o.viewportUnitFix, o.baseURL, o.assetDir, sha256.Sum256([]byte(o.coverHTML)), stitchedHashes(o.prependPDFs), stitchedHashes(o.appendPDFs))
+}
+
+// printToPDFParams builds the Page.printToPDF parameters for the options.
+func (o *options) printToPDFParams() *page.PrintToPDFParams {
+	params := page.PrintToPDF().
+		WithPrintBackground(o.printBackground()).
+		WithLandscape(o.landscape).
+		WithScale(o.scale).
+		WithPageRanges(o.pageRanges).
+		WithPreferCSSPageSize(o.preferCSSPageSize)
+	if o.streamTransfer || o.bufferPool {
+		params.TransferMode = page.PrintToPDFTransferModeReturnAsStream
+	}
+	if o.paperSize != nil {
+		params.PaperWidth, params.PaperHeight = o.paperSize.Width, o.paperSize.Height
+	}
+	if o.headerTemplate != "" || o.footerTemplate != "" {
+		params.DisplayHeaderFooter = true
+		params.HeaderTemplate = orEmptyTemplate(o.headerTemplate)
+		params.FooterTemplate = orEmptyTemplate(o.footerTemplate)
+	}
+	params.MarginTop, params.MarginBottom = o.margins.Top, o.margins.Bottom
+	params.MarginLeft, params.MarginRight = o.margins.Left, o.margins.Right
+	if o.rollPaperWidth > 0 {
+		params.PaperWidth = o.rollPaperWidth
+	}
+	for _, fn := range o.printParams {
+		fn(params)
+	}
+	return params
 }
 
-// WithLogger sets a custom logger function for debugging output.
+// WithLogger sets a custom logger function for debugging output. Calls
+// through the returned Option are serialized, so logger need not be safe for
+// concurrent use as long as conversions share the Option. Each line starts
+// with a number identifying its conversion. A nil logger discards output.
 func WithLogger(logger func(string, ...interface{})) Option {
+	var mu sync.Mutex
 	return func(o *options) {
-		o.logger = logger
+		if logger == nil {
+			o.logger = nil
+			return
+		}
+		o.logger = func(format string, args ...interface{}) {
+			mu.Lock()
+			defer mu.Unlock()
+			logger(format, args...)
+		}
+	}
+}
+
+// conversionIDs numbers conversions in log output.
+var conversionIDs atomic.Uint64
+
+// conversionLogger returns logger prefixing each line with a number unique
+// to the conversion, so lines of concurrent conversions can be told apart.
+// A nil logger discards the lines.
+func conversionLogger(logger func(string, ...interface{})) func(string, ...interface{}) {
+	if logger == nil {
+		return func(string, ...interface{}) {}
+	}
+	prefix := fmt.Sprintf("html2pdf #%d: ", conversionIDs.Add(1))
+	return func(format string, args ...interface{}) {
+		logger(prefix+format, args...)
+	}
+}
+
+// WithCPUBudget limits how much CPU time page scripts may use before the
+// conversion is aborted with ErrCPUBudgetExceeded. A zero duration disables the limit.
+func WithCPUBudget(d time.Duration) Option {
+	return func(o *options) {
+		o.cpuBudget = d
+	}
+}
+
+// WithPrintToPDFParams registers a function that can modify the raw
+// Page.printToPDF parameters before printing. It is an escape hatch for
+// settings without a dedicated option and runs after all other options are applied.
+func WithPrintToPDFParams(fn func(*page.PrintToPDFParams)) Option {
+	return func(o *options) {
+		o.printParams = append(o.printParams, fn)
+	}
+}
+
+// WithRawPrintParams replaces the Page.printToPDF parameters with params,
+// discarding those derived from other options such as WithPaperSize or
+// WithMargins. Functions registered with WithPrintToPDFParams still run
+// afterwards, in the order the options were given.
+func WithRawPrintParams(params page.PrintToPDFParams) Option {
+	return WithPrintToPDFParams(func(p *page.PrintToPDFParams) {
+		*p = params
+	})
+}
+
+// WithPreRenderActions runs the given chromedp actions on the blank page
+// before the HTML content is set, e.g. to set up emulation or cookies.
+func WithPreRenderActions(actions ...chromedp.Action) Option {
+	return func(o *options) {
+		o.preRender = append(o.preRender, actions...)
+	}
+}
+
+// WithPostRenderActions runs the given chromedp actions after the page has
+// loaded and before it is printed, e.g. to dispatch input events.
+func WithPostRenderActions(actions ...chromedp.Action) Option {
+	return func(o *options) {
+		o.postRender = append(o.postRender, actions...)
 	}
 }
 
 // getDefaultOptions returns the default options.
 func getDefaultOptions() *options {
 	return &options{
-		logger: log.Printf,
+		logger:            log.Printf,
+		pageBreakSelector: DefaultPageBreakSelector,
+	}
+}
+
+// allocatorOptions returns the Chrome launch options, sized to the container
+// limits when present and hardened or namespaced when requested in o.
+func allocatorOptions(o *options) []chromedp.ExecAllocatorOption {
+	opts := make([]chromedp.ExecAllocatorOption, 0, len(chromedp.DefaultExecAllocatorOptions))
+	opts = append(opts, chromedp.DefaultExecAllocatorOptions[:]...)
+	opts = append(opts, DetectResourceLimits().allocatorOptions()...)
+	if o.hardenedSandbox {
+		opts = append(opts, sandboxAllocatorOptions()...)
+	}
+	if o.userNamespace {
+		opts = append(opts, userNamespaceAllocatorOptions()...)
+	}
+	return opts
+}
+
+// newOptions applies opts on top of the default options.
+func newOptions(opts []Option) *options {
+	options := getDefaultOptions()
+	for _, opt := range opts {
+		opt(options)
 	}
+	return options
 }
 
 // ConvertHtmlFileToPdf reads an HTML file and converts its content to PDF.
@@ -51,42 +276,430 @@ func ConvertHtmlFileToPdf(ctx context.Context, fileName string, opts ...Option)
 
 // ConvertHtmlToPdf converts HTML content to PDF using chromedp.
 func ConvertHtmlToPdf(ctx context.Context, htmlContent string, opts ...Option) ([]byte, error) {
-	options := getDefaultOptions()
-	for _, opt := range opts {
-		opt(options)
+	res, err := ConvertHtmlToResult(ctx, htmlContent, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return res.PDF, nil
+}
+
+// ConvertHtmlReaderToPdf reads HTML from r, e.g. an HTTP request body or a
+// template engine's output pipe, and converts it to PDF. Chrome needs the
+// whole document before printing, so r is read to the end first.
+func ConvertHtmlReaderToPdf(ctx context.Context, r io.Reader, opts ...Option) ([]byte, error) {
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read HTML: %w", err)
+	}
+	return ConvertHtmlToPdf(ctx, string(b), opts...)
+}
+
+// ConvertHtmlToPdfWriter converts HTML content to PDF and writes it to w,
+// e.g. an HTTP response or a file, instead of returning it. With
+// WithStreamTransfer, the PDF is written as Chrome hands it over unless an
+// option needs the finished document; a conversion failing midway may then
+// leave part of the PDF in w.
+func ConvertHtmlToPdfWriter(ctx context.Context, htmlContent string, w io.Writer, opts ...Option) error {
+	if options := newOptions(opts); options.streamTransfer && options.streamable() && !options.shares() {
+		r, err := ConvertHtmlToPdfStream(ctx, htmlContent, opts...)
+		if err != nil {
+			return err
+		}
+		defer r.Close()
+		if _, err := io.Copy(w, r); err != nil {
+			return fmt.Errorf("failed to write PDF: %w", err)
+		}
+		return nil
+	}
+
+	res, err := ConvertHtmlToResult(ctx, htmlContent, opts...)
+	if err != nil {
+		return err
+	}
+	defer res.Release()
+	if _, err := res.WriteTo(w); err != nil {
+		return fmt.Errorf("failed to write PDF: %w", err)
+	}
+	return nil
+}
+
+// ConvertHtmlToResult converts HTML content to PDF and returns the document
+// together with its checksum and size.
+func ConvertHtmlToResult(ctx context.Context, htmlContent string, opts ...Option) (*Result, error) {
+	options := newOptions(opts)
+	htmlContent, err := applyStarterTemplate(htmlContent, options)
+	if err != nil {
+		return nil, err
+	}
+
+	doc := document{html: htmlContent}
+	if options.shares() {
+		res, err := conversions.do(ctx, dedupKey(htmlContent, options), func(ctx context.Context) (*Result, error) {
+			return convert(ctx, doc, options)
+		})
+		if err != nil {
+			return nil, err
+		}
+		// The result is shared with callers whose keys may differ, so each
+		// checks its own assertions and signs its own copy.
+		if err := checkAssertions(res.PDF, options.assertions); err != nil {
+			return nil, err
+		}
+		res.Signature = options.signature(res.PDF)
+		return res, nil
 	}
+	return convert(ctx, doc, options)
+}
 
-	ctx, cancel := chromedp.NewContext(ctx, chromedp.WithDebugf(options.logger))
+// document describes where the content to convert comes from.
+type document struct {
+	// html is written into a blank page when url is empty.
+	html string
+	// url is navigated to directly when set.
+	url string
+}
+
+// convert renders doc and applies post-processing.
+func convert(ctx context.Context, doc document, options *options) (*Result, error) {
+	if options.paperSize != nil {
+		if err := options.paperSize.validate(); err != nil {
+			return nil, err
+		}
+	}
+	if err := validateScale(options.scale); err != nil {
+		return nil, err
+	}
+	if options.pageRanges != "" {
+		if _, err := parsePageRanges(options.pageRanges); err != nil {
+			return nil, err
+		}
+	}
+	if len(options.sections) > 0 {
+		if _, err := sortedSections(options.sections); err != nil {
+			return nil, err
+		}
+		if options.pageRanges != "" {
+			return nil, fmt.Errorf("%w: section headers cannot be combined with page ranges", ErrInvalidPageRange)
+		}
+	}
+	if options.rasterDPI > 0 {
+		if err := options.validateRaster(); err != nil {
+			return nil, err
+		}
+	}
+	if options.baseURL != "" {
+		if err := validateBaseURL(options.baseURL); err != nil {
+			return nil, err
+		}
+		if doc.url == "" {
+			doc.html = withBaseURL(doc.html, options.baseURL)
+		}
+	}
+	cover := document{html: options.coverHTML}
+	if options.baseURL != "" {
+		cover.html = withBaseURL(cover.html, options.baseURL)
+	}
+	if options.fixtureDir != "" && options.fixtureMode == FixtureReplay {
+		res, err := replayFixture(doc, options)
+		if err != nil {
+			return nil, err
+		}
+		if !options.shares() {
+			if err := checkAssertions(res.PDF, options.assertions); err != nil {
+				return nil, err
+			}
+			res.Signature = options.signature(res.PDF)
+		}
+		return res, nil
+	}
+	// The asset server listens on a new port for every conversion, so its
+	// base is added to a copy of doc that fixtures do not see.
+	rendered := doc
+	if options.assetDir != "" && options.baseURL == "" && doc.url == "" {
+		srv, err := serveAssetDir(options.assetDir)
+		if err != nil {
+			return nil, err
+		}
+		defer srv.Close()
+		rendered.html = withBaseURL(doc.html, srv.URL(""))
+		cover.html = withBaseURL(cover.html, srv.URL(""))
+	}
+
+	var timings Timings
+	var fonts *FontReport
+	var quality *QualityReport
+	if options.fontReport || options.qualityReport {
+		fonts = &FontReport{}
+	}
+	if options.qualityReport {
+		quality = &QualityReport{}
+	}
+	var probe bool
+	if options.breakerThreshold > 0 {
+		var err error
+		if probe, err = backend.allow(); err != nil {
+			return nil, err
+		}
+	}
+	env := newEnvironment(options)
+	warnings := &warningCollector{}
+	buf, err := render(ctx, rendered, options, &timings, fonts, env, warnings, quality)
+	if options.breakerThreshold > 0 {
+		backend.record(ctx, probe, err, options.breakerThreshold, options.breakerCooldown)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if options.pageEstimate != nil || options.pdfStream != nil {
+		return &Result{}, nil
+	}
+	if options.coverHTML != "" {
+		if buf, err = prependCover(ctx, cover, buf, options, env, warnings); err != nil {
+			return nil, err
+		}
+	}
+	if len(options.prependPDFs) > 0 || len(options.appendPDFs) > 0 {
+		if buf, err = stitchPDFs(buf, options); err != nil {
+			return nil, err
+		}
+	}
+	if options.environmentMetadata {
+		if buf, err = options.editPDF(buf, addEnvironmentMetadata(env)); err != nil {
+			return nil, fmt.Errorf("failed to post-process PDF: %w", err)
+		}
+	}
+
+	start := time.Now()
+	buf, err = postProcess(buf, options)
+	if err != nil {
+		return nil, fmt.Errorf("failed to post-process PDF: %w", err)
+	}
+	timings.PostProcess = time.Since(start)
+	if !options.shares() {
+		if err := checkAssertions(buf, options.assertions); err != nil {
+			return nil, err
+		}
+	}
+
+	res := newResult(buf)
+	res.pooled = options.bufferPool && !options.shares() && options.owns(buf)
+	res.Timings = timings
+	if options.fontReport {
+		res.FontReport = fonts
+	}
+	res.Environment = *env
+	res.Warnings = warnings.list()
+	if quality != nil {
+		quality.addFonts(fonts)
+		if err := quality.addBlankPages(buf); err != nil {
+			return nil, fmt.Errorf("failed to check for blank pages: %w", err)
+		}
+		quality.score()
+		res.Quality = quality
+	}
+	if options.fixtureDir != "" && options.fixtureMode == FixtureRecord {
+		if err := recordFixture(doc, options, res); err != nil {
+			return nil, err
+		}
+	}
+	if !options.shares() {
+		res.Signature = options.signature(res.PDF)
+	}
+	return res, nil
+}
+
+// render loads doc into a new browser tab, in the browser of withBrowser if
+// set, and prints it to PDF, recording the duration of each phase in
+// timings, the browser version in env and non-fatal problems in warnings.
+// When fonts is not nil, it is filled with the fonts used by the page; when
+// quality is not nil, it receives the overflowing elements and missing
+// images.
+func render(ctx context.Context, doc document, options *options, timings *Timings, fonts *FontReport, env *Environment, warnings *warningCollector, quality *QualityReport) ([]byte, error) {
+	callerCtx := ctx
+	ctx, cancelBudget := context.WithCancelCause(ctx)
+	defer cancelBudget(nil)
+
+	if options.browser != nil {
+		ctx = sharedBrowser{Context: ctx, browser: options.browser}
+	} else {
+		var cancelAlloc context.CancelFunc
+		ctx, cancelAlloc = chromedp.NewExecAllocator(ctx, allocatorOptions(options)...)
+		defer cancelAlloc()
+	}
+
+	logger := conversionLogger(options.logger)
+	ctx, cancel := chromedp.NewContext(ctx, chromedp.WithDebugf(logger))
 	defer cancel()
 
-	var buf []byte
-	err := chromedp.Run(ctx,
-		chromedp.Navigate("about:blank"),
-		chromedp.ActionFunc(func(ctx context.Context) error {
-			var wg sync.WaitGroup
-			wg.Add(1)
-			chromedp.ListenTarget(ctx, func(ev interface{}) {
-				if _, ok := ev.(*page.EventLoadEventFired); ok {
-					wg.Done()
-				}
-			})
-			frameTree, err := page.GetFrameTree().Do(ctx)
+	// Running no actions starts the browser and opens the tab.
+	start := time.Now()
+	if err := chromedp.Run(ctx); err != nil {
+		return nil, fmt.Errorf("failed to convert HTML to PDF: %w", &backendError{err})
+	}
+	timings.Allocate = time.Since(start)
+
+	actions := []chromedp.Action{readChromeVersion(env), timed(&timings.Navigate, chromedp.Navigate("about:blank"))}
+	stopWatchdog := make(chan struct{})
+	if options.cpuBudget > 0 {
+		actions = append(actions, startCPUWatchdog(options.cpuBudget, stopWatchdog, func() {
+			cancelBudget(ErrCPUBudgetExceeded)
+		}))
+	}
+
+	if options.propagateDeadline {
+		actions = append(actions, propagateDeadline(options.deadlineReserve, stopWatchdog))
+	}
+	if options.screencastDir != "" {
+		actions = append(actions, startScreencast(options.screencastDir, logger))
+	}
+	if factor := options.deviceScaleFactor(); factor > 0 {
+		actions = append(actions, emulateDeviceScale(factor))
+	}
+	frames := &frameTracker{}
+	actions = append(actions, frames.listen(), warnings.listen())
+	if options.iframePolicy != IframeAllow {
+		actions = append(actions, blockIframes(options.iframePolicy, doc.url))
+	}
+	actions = append(actions, options.preRender...)
+
+	loaded := make(chan struct{})
+	load := chromedp.ActionFunc(func(ctx context.Context) error {
+		var once sync.Once
+		chromedp.ListenTarget(ctx, func(ev interface{}) {
+			if _, ok := ev.(*page.EventLoadEventFired); ok {
+				once.Do(func() { close(loaded) })
+			}
+		})
+		if doc.url != "" {
+			_, _, errorText, _, err := page.Navigate(doc.url).Do(ctx)
 			if err != nil {
 				return err
 			}
-			if err := page.SetDocumentContent(frameTree.Frame.ID, htmlContent).Do(ctx); err != nil {
-				return err
+			if errorText != "" {
+				return fmt.Errorf("navigation to %s failed: %s", doc.url, errorText)
 			}
-			wg.Wait()
 			return nil
-		}),
-		chromedp.ActionFunc(func(ctx context.Context) error {
+		}
+		frameTree, err := page.GetFrameTree().Do(ctx)
+		if err != nil {
+			return err
+		}
+		return page.SetDocumentContent(frameTree.Frame.ID, doc.html).Do(ctx)
+	})
+	if doc.url != "" {
+		actions = append(actions, timed(&timings.Navigate, load))
+	} else {
+		actions = append(actions, timed(&timings.SetContent, load))
+	}
+
+	var buf []byte
+	actions = append(actions,
+		timed(&timings.WaitReady, chromedp.ActionFunc(func(ctx context.Context) error {
+			select {
+			case <-loaded:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		})),
+		timed(&timings.WaitReady, frames.wait(iframeLoadTimeout, logger)),
+		timed(&timings.WaitReady, waitForCustomElements(customElementsTimeout, logger)),
+		warnings.checkFonts(),
+	)
+	if options.iframePolicy == IframeSameOrigin {
+		actions = append(actions, chromedp.Evaluate(hideBlockedIframesScript, nil))
+	}
+	if options.imageResolution == ImageResolutionLargest {
+		actions = append(actions, useLargestImages())
+	}
+	if css := options.injectedCSS(); css != "" {
+		actions = append(actions, injectCSS(css))
+	}
+	if options.viewportUnitFix {
+		actions = append(actions, fixViewportUnits(options.printToPDFParams()))
+	}
+	actions = append(actions, options.postRender...)
+	if len(options.redactSelectors) > 0 {
+		actions = append(actions, redact(options.redactSelectors))
+	}
+	if fonts != nil {
+		actions = append(actions, chromedp.ActionFunc(func(ctx context.Context) error {
+			return collectFontReport(ctx, fonts)
+		}))
+	}
+	if quality != nil {
+		actions = append(actions, chromedp.Evaluate(missingImagesScript, &quality.MissingImages))
+	}
+	if options.screencastDir != "" {
+		actions = append(actions, page.StopScreencast())
+	}
+	actions = append(actions,
+		timed(&timings.Print, chromedp.ActionFunc(func(ctx context.Context) error {
+			close(stopWatchdog)
+			params := options.printToPDFParams()
+			if options.rollPaperWidth > 0 {
+				if err := fitRollPaper(ctx, params); err != nil {
+					return err
+				}
+			} else if options.fitToSinglePage {
+				if err := fitSinglePage(ctx, params); err != nil {
+					return err
+				}
+			}
+			if options.pageEstimate != nil {
+				var err error
+				*options.pageEstimate, err = estimatePages(ctx, params)
+				return err
+			}
+			if options.failOnOverflow || quality != nil {
+				selectors, err := overflowSelectors(ctx, params)
+				if err != nil {
+					return err
+				}
+				if options.failOnOverflow {
+					if err := overflowError(selectors); err != nil {
+						return err
+					}
+				}
+				if quality != nil {
+					quality.Overflow = selectors
+				}
+			}
+			if options.pdfStream != nil {
+				params.TransferMode = page.PrintToPDFTransferModeReturnAsStream
+				_, handle, err := params.Do(ctx)
+				if err != nil {
+					return err
+				}
+				options.pdfStream.start(nil)
+				return readStream(ctx, handle, options.pdfStream.w)
+			}
 			var err error
-			buf, _, err = page.PrintToPDF().WithPrintBackground(false).Do(ctx)
+			if options.rasterDPI > 0 {
+				buf, err = printRaster(ctx, params, options.rasterDPI, options.rasterText)
+				return err
+			}
+			if buf, err = printToPDF(ctx, params, options.pdfBuffer()); err != nil {
+				return err
+			}
+			if options.bufferPool {
+				options.own(buf)
+			}
+			if len(options.sections) > 0 {
+				buf, err = printSections(ctx, buf, params, options.sections)
+			}
 			return err
-		}),
+		})),
 	)
-	if err != nil {
+
+	if err := chromedp.Run(ctx, actions...); err != nil {
+		if cause := context.Cause(ctx); errors.Is(cause, ErrCPUBudgetExceeded) {
+			return nil, cause
+		}
+		// The tab ends on its own when the browser crashes or exits.
+		if transportFailure(err) || (ctx.Err() != nil && callerCtx.Err() == nil) {
+			err = &backendError{err}
+		}
 		return nil, fmt.Errorf("failed to convert HTML to PDF: %w", err)
 	}
 	return buf, nil
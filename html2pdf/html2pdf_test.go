@@ -1,12 +1,20 @@
 package html2pdf
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"strings"
 	"testing"
+	"testing/iotest"
 	"time"
+
+	"github.com/chromedp/cdproto/page"
+	"github.com/chromedp/chromedp"
+	"github.com/chromedp/chromedp/device"
 )
 
 func TestConvertHtmlToPdf(t *testing.T) {
@@ -31,6 +39,35 @@ func TestConvertHtmlToPdf(t *testing.T) {
 			htmlContent: "",
 			wantErr:     false,
 		},
+		{
+			name:        "with page break markers",
+			htmlContent: "<html><body><p>Page one</p><div data-html2pdf-break></div><p>Page two</p></body></html>",
+			wantErr:     false,
+		},
+		{
+			name:        "with stream transfer",
+			htmlContent: "<html><body><h1>Streamed</h1></body></html>",
+			wantErr:     false,
+			opts:        []Option{WithStreamTransfer(true)},
+		},
+		{
+			name:        "with redacted elements",
+			htmlContent: `<html><body><p>Name: <span class="pii">Jane Doe</span></p><a class="pii" href="mailto:jane@example.com">jane@example.com</a></body></html>`,
+			wantErr:     false,
+			opts:        []Option{WithRedactSelectors([]string{".pii"})},
+		},
+		{
+			name:        "with invalid redaction selector",
+			htmlContent: "<html><body><p>Text</p></body></html>",
+			wantErr:     true,
+			opts:        []Option{WithRedactSelectors([]string{"[unclosed"})},
+		},
+		{
+			name:        "with deadline propagation interrupting a hot loop",
+			htmlContent: `<html><body><p>Before</p><script>if (window.__html2pdfDeadlineMs) { while (true) {} }</script></body></html>`,
+			wantErr:     false,
+			opts:        []Option{WithDeadlinePropagation(29 * time.Second)},
+		},
 		{
 			name:        "with custom logger",
 			htmlContent: "<html><body><h1>Test with Logger</h1></body></html>",
@@ -125,6 +162,115 @@ func TestConvertHtmlFileToPdf(t *testing.T) {
 	}
 }
 
+func TestConvertHtmlReaderToPdf(t *testing.T) {
+	tests := []struct {
+		name    string
+		r       io.Reader
+		wantErr bool
+	}{
+		{name: "valid HTML", r: strings.NewReader(`<html><body><h1>Streamed</h1></body></html>`)},
+		{name: "read error", r: iotest.ErrReader(errors.New("connection reset")), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			defer cancel()
+
+			got, err := ConvertHtmlReaderToPdf(ctx, tt.r)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ConvertHtmlReaderToPdf() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && !strings.HasPrefix(string(got), "%PDF") {
+				t.Errorf("ConvertHtmlReaderToPdf() returned non-PDF content")
+			}
+		})
+	}
+}
+
+func TestConvertHtmlToPdfWriter(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	var buf bytes.Buffer
+	if err := ConvertHtmlToPdfWriter(ctx, "<html><body><h1>Writer</h1></body></html>", &buf); err != nil {
+		t.Fatalf("ConvertHtmlToPdfWriter() error = %v", err)
+	}
+	if !strings.HasPrefix(buf.String(), "%PDF") {
+		t.Errorf("ConvertHtmlToPdfWriter() wrote non-PDF content")
+	}
+
+	if err := ConvertHtmlToPdfWriter(ctx, "<p>x</p>", failingWriter{}); err == nil {
+		t.Error("Expected the write error to be returned")
+	}
+}
+
+func TestConvertHtmlToPdfWriterStreamTransfer(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	defer func(size int64) { streamChunkSize = size }(streamChunkSize)
+	streamChunkSize = 1 << 10
+
+	var w countingWriter
+	if err := ConvertHtmlToPdfWriter(ctx, "<html><body><h1>Streamed</h1></body></html>", &w, WithStreamTransfer(true)); err != nil {
+		t.Fatalf("ConvertHtmlToPdfWriter() error = %v", err)
+	}
+	if !strings.HasPrefix(w.buf.String(), "%PDF") {
+		t.Errorf("ConvertHtmlToPdfWriter() wrote non-PDF content")
+	}
+	if w.writes < 2 {
+		t.Errorf("Expected the PDF to be written chunk by chunk, got %d writes", w.writes)
+	}
+}
+
+// countingWriter counts the writes made to it.
+type countingWriter struct {
+	buf    bytes.Buffer
+	writes int
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	w.writes++
+	return w.buf.Write(p)
+}
+
+// failingWriter fails every write.
+type failingWriter struct{}
+
+func (failingWriter) Write(p []byte) (int, error) {
+	return 0, errors.New("disk full")
+}
+
+func TestConvertHtmlToResult(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	res, err := ConvertHtmlToResult(ctx, "<html><body><h1>Result</h1></body></html>")
+	if err != nil {
+		t.Fatalf("ConvertHtmlToResult() error = %v", err)
+	}
+	if !strings.HasPrefix(string(res.PDF), "%PDF") {
+		t.Errorf("ConvertHtmlToResult() returned non-PDF content")
+	}
+	if res.Size != len(res.PDF) || len(res.SHA256) != 64 {
+		t.Errorf("ConvertHtmlToResult() returned inconsistent integrity data: size %d, sha256 %q", res.Size, res.SHA256)
+	}
+	if res.Timings.Allocate <= 0 || res.Timings.Print <= 0 {
+		t.Errorf("ConvertHtmlToResult() did not record timings: %+v", res.Timings)
+	}
+	pages, err := res.PageInfos()
+	if err != nil {
+		t.Fatalf("PageInfos() error = %v", err)
+	}
+	if len(pages) != 1 || !pages[0].Matches(Letter) {
+		t.Errorf("ConvertHtmlToResult() returned unexpected pages: %+v", pages)
+	}
+	if res.Environment.ChromeVersion == "" || res.Environment.OptionsHash == "" {
+		t.Errorf("ConvertHtmlToResult() returned incomplete environment: %+v", res.Environment)
+	}
+}
+
 func TestWithLogger(t *testing.T) {
 	var logCalled bool
 	var logMessage string
@@ -198,6 +344,108 @@ func TestConvertHtmlToPdfWithNilLogger(t *testing.T) {
 	}
 }
 
+func TestWithCPUBudget(t *testing.T) {
+	opts := getDefaultOptions()
+	if opts.cpuBudget != 0 {
+		t.Errorf("Expected no default CPU budget, got %v", opts.cpuBudget)
+	}
+
+	WithCPUBudget(2 * time.Second)(opts)
+	if opts.cpuBudget != 2*time.Second {
+		t.Errorf("Expected CPU budget 2s, got %v", opts.cpuBudget)
+	}
+}
+
+func TestConvertHtmlToPdfWithCPUBudget(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	htmlContent := `<html><body><script>while (true) {}</script></body></html>`
+
+	_, err := ConvertHtmlToPdf(ctx, htmlContent, WithCPUBudget(500*time.Millisecond))
+	if !errors.Is(err, ErrCPUBudgetExceeded) {
+		t.Errorf("Expected ErrCPUBudgetExceeded, got %v", err)
+	}
+}
+
+func TestWithPrintToPDFParams(t *testing.T) {
+	opts := getDefaultOptions()
+	if params := opts.printToPDFParams(); params.Landscape || params.PrintBackground {
+		t.Errorf("Unexpected default print params: %+v", params)
+	}
+
+	WithPrintToPDFParams(func(p *page.PrintToPDFParams) {
+		p.Landscape = true
+	})(opts)
+	WithPrintToPDFParams(func(p *page.PrintToPDFParams) {
+		p.GenerateTaggedPDF = true
+	})(opts)
+
+	params := opts.printToPDFParams()
+	if !params.Landscape || !params.GenerateTaggedPDF {
+		t.Errorf("Expected both param functions to apply, got %+v", params)
+	}
+	if opts.fingerprint() == getDefaultOptions().fingerprint() {
+		t.Error("Expected print params to change the options fingerprint")
+	}
+}
+
+func TestWithRawPrintParams(t *testing.T) {
+	raw := page.PrintToPDFParams{PaperWidth: 4, PaperHeight: 6, GenerateDocumentOutline: true}
+	opts := newOptions([]Option{
+		WithLandscape(true),
+		WithMargins(1, 1, 1, 1),
+		WithRawPrintParams(raw),
+		WithPrintToPDFParams(func(p *page.PrintToPDFParams) {
+			p.GenerateTaggedPDF = true
+		}),
+	})
+
+	params := opts.printToPDFParams()
+	if params.Landscape || params.MarginTop != 0 {
+		t.Errorf("Expected raw params to replace derived params, got %+v", params)
+	}
+	if params.PaperWidth != 4 || params.PaperHeight != 6 || !params.GenerateDocumentOutline {
+		t.Errorf("Expected raw params to apply, got %+v", params)
+	}
+	if !params.GenerateTaggedPDF {
+		t.Error("Expected later param functions to apply on top of raw params")
+	}
+}
+
+func TestWithRenderActions(t *testing.T) {
+	opts := getDefaultOptions()
+	noop := chromedp.ActionFunc(func(context.Context) error { return nil })
+
+	WithPreRenderActions(noop, noop)(opts)
+	WithPreRenderActions(noop)(opts)
+	WithPostRenderActions(noop)(opts)
+
+	if len(opts.preRender) != 3 {
+		t.Errorf("Expected 3 pre-render actions, got %d", len(opts.preRender))
+	}
+	if len(opts.postRender) != 1 {
+		t.Errorf("Expected 1 post-render action, got %d", len(opts.postRender))
+	}
+}
+
+func TestConvertHtmlToPdfWithRenderActions(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	var title string
+	_, err := ConvertHtmlToPdf(ctx, "<html><head><title>Actions</title></head><body></body></html>",
+		WithPreRenderActions(chromedp.Emulate(device.IPhone7)),
+		WithPostRenderActions(chromedp.Title(&title)),
+	)
+	if err != nil {
+		t.Fatalf("ConvertHtmlToPdf() error = %v", err)
+	}
+	if title != "Actions" {
+		t.Errorf("Expected post-render action to read title 'Actions', got %q", title)
+	}
+}
+
 func BenchmarkConvertHtmlToPdf(b *testing.B) {
 	htmlContent := `<html><body><h1>Benchmark Test</h1><p>This is a benchmark test.</p></body></html>`
 	ctx := context.Background()
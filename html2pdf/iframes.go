@@ -0,0 +1,160 @@
+package html2pdf
+
+import (
+	"context"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/chromedp/cdproto/cdp"
+	"github.com/chromedp/cdproto/fetch"
+	"github.com/chromedp/cdproto/network"
+	"github.com/chromedp/cdproto/page"
+	"github.com/chromedp/chromedp"
+)
+
+// IframePolicy selects which iframes are loaded and printed.
+type IframePolicy int
+
+const (
+	// IframeAllow loads and prints every iframe, the default.
+	IframeAllow IframePolicy = iota
+	// IframeBlock blocks all iframe documents and hides the iframes.
+	IframeBlock
+	// IframeSameOrigin blocks and hides iframes from other origins than the
+	// page. Pages converted from HTML content have no origin, so only
+	// srcdoc and about:blank iframes are kept.
+	IframeSameOrigin
+)
+
+var (
+	// iframeLoadTimeout bounds how long printing waits for iframes that are
+	// still loading after the page itself has loaded.
+	iframeLoadTimeout = 10 * time.Second
+	// iframePollInterval is how often loading iframes are checked.
+	iframePollInterval = 50 * time.Millisecond
+)
+
+// hideBlockedIframesScript hides iframes whose document the page cannot
+// reach: blocked ones, which would print Chrome's error page, and those from
+// other origins.
+const hideBlockedIframesScript = `(() => {
+	for (const frame of document.querySelectorAll("iframe")) {
+		let doc = null;
+		try {
+			doc = frame.contentDocument;
+		} catch (e) {}
+		if (!doc) {
+			frame.style.setProperty("display", "none", "important");
+		}
+	}
+})()`
+
+// WithIframePolicy controls which iframes, such as embedded maps and
+// widgets, are loaded and printed. Whatever the policy, printing waits for
+// iframes that are still loading after the page has loaded, up to 10 seconds.
+func WithIframePolicy(policy IframePolicy) Option {
+	return func(o *options) {
+		o.iframePolicy = policy
+	}
+}
+
+// blockIframes fails iframe document requests the policy does not allow.
+// pageURL is the URL of the page, or empty for HTML content.
+func blockIframes(policy IframePolicy, pageURL string) chromedp.ActionFunc {
+	return func(ctx context.Context) error {
+		tree, err := page.GetFrameTree().Do(ctx)
+		if err != nil {
+			return err
+		}
+		mainFrame := tree.Frame.ID
+		chromedp.ListenTarget(ctx, func(ev interface{}) {
+			req, ok := ev.(*fetch.EventRequestPaused)
+			if !ok {
+				return
+			}
+			// Commands cannot be run from the listener itself.
+			if req.FrameID == mainFrame || (policy == IframeSameOrigin && sameOrigin(req.Request.URL, pageURL)) {
+				go fetch.ContinueRequest(req.RequestID).Do(ctx)
+				return
+			}
+			go fetch.FailRequest(req.RequestID, network.ErrorReasonBlockedByClient).Do(ctx)
+		})
+		return fetch.Enable().WithPatterns([]*fetch.RequestPattern{{
+			URLPattern:   "*",
+			ResourceType: network.ResourceTypeDocument,
+			RequestStage: fetch.RequestStageRequest,
+		}}).Do(ctx)
+	}
+}
+
+// sameOrigin reports whether a and b have the same scheme, host and port.
+func sameOrigin(a, b string) bool {
+	ua, err := url.Parse(a)
+	if err != nil || ua.Host == "" {
+		return false
+	}
+	ub, err := url.Parse(b)
+	if err != nil {
+		return false
+	}
+	return ua.Scheme == ub.Scheme && ua.Host == ub.Host
+}
+
+// frameTracker records which frames of the page are loading.
+type frameTracker struct {
+	mu      sync.Mutex
+	loading map[cdp.FrameID]bool
+}
+
+// listen starts tracking frame loads.
+func (t *frameTracker) listen() chromedp.ActionFunc {
+	return func(ctx context.Context) error {
+		t.loading = map[cdp.FrameID]bool{}
+		chromedp.ListenTarget(ctx, func(ev interface{}) {
+			t.mu.Lock()
+			defer t.mu.Unlock()
+			switch ev := ev.(type) {
+			case *page.EventFrameStartedLoading:
+				t.loading[ev.FrameID] = true
+			case *page.EventFrameStoppedLoading:
+				delete(t.loading, ev.FrameID)
+			case *page.EventFrameDetached:
+				delete(t.loading, ev.FrameID)
+			}
+		})
+		return nil
+	}
+}
+
+// pending returns the number of frames still loading.
+func (t *frameTracker) pending() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return len(t.loading)
+}
+
+// wait blocks until no frame is loading or timeout passes. Frames still
+// loading after timeout are reported to logger and printed as they are.
+func (t *frameTracker) wait(timeout time.Duration, logger func(string, ...interface{})) chromedp.ActionFunc {
+	return func(ctx context.Context) error {
+		deadline := time.Now().Add(timeout)
+		ticker := time.NewTicker(iframePollInterval)
+		defer ticker.Stop()
+		for {
+			n := t.pending()
+			if n == 0 {
+				return nil
+			}
+			if time.Now().After(deadline) {
+				logger("printing with %d frames still loading after %v", n, timeout)
+				return nil
+			}
+			select {
+			case <-ticker.C:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+	}
+}
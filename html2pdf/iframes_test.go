@@ -0,0 +1,123 @@
+package html2pdf
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/chromedp/cdproto/cdp"
+	"github.com/chromedp/chromedp"
+)
+
+func TestSameOrigin(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want bool
+	}{
+		{a: "https://example.com/map", b: "https://example.com/report", want: true},
+		{a: "https://example.com:8443/map", b: "https://example.com:8443/", want: true},
+		{a: "http://example.com/map", b: "https://example.com/report", want: false},
+		{a: "https://maps.example.com/", b: "https://example.com/", want: false},
+		{a: "https://example.com:8443/", b: "https://example.com/", want: false},
+		{a: "https://example.com/", b: "", want: false},
+		{a: "about:blank", b: "", want: false},
+	}
+	for _, tt := range tests {
+		if got := sameOrigin(tt.a, tt.b); got != tt.want {
+			t.Errorf("sameOrigin(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestFrameTrackerWait(t *testing.T) {
+	defer func(d time.Duration) { iframePollInterval = d }(iframePollInterval)
+	iframePollInterval = time.Millisecond
+
+	tracker := &frameTracker{loading: map[cdp.FrameID]bool{}}
+	if err := tracker.wait(time.Second, t.Logf)(context.Background()); err != nil {
+		t.Errorf("Expected no error without loading frames, got %v", err)
+	}
+
+	tracker.loading["map"] = true
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		tracker.mu.Lock()
+		delete(tracker.loading, "map")
+		tracker.mu.Unlock()
+	}()
+	if err := tracker.wait(time.Second, t.Logf)(context.Background()); err != nil || tracker.pending() != 0 {
+		t.Errorf("Expected wait to return once the frame loaded, got %v", err)
+	}
+
+	tracker.loading["widget"] = true
+	var logged string
+	err := tracker.wait(10*time.Millisecond, func(format string, args ...interface{}) {
+		logged = fmt.Sprintf(format, args...)
+	})(context.Background())
+	if err != nil || !strings.Contains(logged, "1 frames still loading") {
+		t.Errorf("Expected the timeout to be logged, got %v, %q", err, logged)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := tracker.wait(time.Second, t.Logf)(ctx); err != context.Canceled {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+}
+
+func TestWithIframePolicy(t *testing.T) {
+	if strings.Contains(getDefaultOptions().injectedCSS(), "iframe") {
+		t.Error("Expected iframes to be shown by default")
+	}
+	if !strings.Contains(newOptions([]Option{WithIframePolicy(IframeBlock)}).injectedCSS(), "iframe") {
+		t.Error("Expected IframeBlock to hide iframes")
+	}
+}
+
+func TestConvertURLToPdfWithIframePolicy(t *testing.T) {
+	other := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`<p>other origin</p>`))
+	}))
+	defer other.Close()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/frame" {
+			w.Write([]byte(`<p>same origin</p>`))
+			return
+		}
+		fmt.Fprintf(w, `<iframe id="same" src="/frame"></iframe><iframe id="other" src="%s"></iframe>`, other.URL)
+	}))
+	defer srv.Close()
+
+	tests := []struct {
+		name   string
+		policy IframePolicy
+		want   []string
+	}{
+		{name: "allow", policy: IframeAllow, want: []string{"same", "other"}},
+		{name: "same origin", policy: IframeSameOrigin, want: []string{"same"}},
+		{name: "block", policy: IframeBlock, want: []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			defer cancel()
+
+			var visible []string
+			_, err := ConvertURLToPdf(ctx, srv.URL,
+				WithIframePolicy(tt.policy),
+				WithPostRenderActions(chromedp.Evaluate(
+					`Array.from(document.querySelectorAll("iframe")).filter(f => getComputedStyle(f).display !== "none").map(f => f.id)`, &visible)))
+			if err != nil {
+				t.Fatalf("ConvertURLToPdf() error = %v", err)
+			}
+			if !reflect.DeepEqual(visible, tt.want) {
+				t.Errorf("Expected visible iframes %v, got %v", tt.want, visible)
+			}
+		})
+	}
+}
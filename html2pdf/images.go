@@ -0,0 +1,89 @@
+package html2pdf
+
+import (
+	"math"
+
+	"github.com/chromedp/cdproto/emulation"
+	"github.com/chromedp/cdproto/runtime"
+	"github.com/chromedp/chromedp"
+)
+
+// ImageResolutionPolicy selects which srcset candidate responsive images are
+// printed with.
+type ImageResolutionPolicy int
+
+const (
+	// ImageResolutionScreen lets Chrome pick candidates for a 1x screen, its
+	// default. Responsive pages often get their low-resolution image.
+	ImageResolutionScreen ImageResolutionPolicy = iota
+	// ImageResolutionPrint picks candidates for a 2x display, sharp at
+	// typical print densities.
+	ImageResolutionPrint
+	// ImageResolutionLargest picks the largest candidate of every srcset.
+	ImageResolutionLargest
+)
+
+// printDeviceScale is the device pixel ratio emulated for ImageResolutionPrint.
+const printDeviceScale = 2
+
+// largestSrcsetScript narrows every srcset to its largest candidate, keeping
+// the descriptor so the image is laid out at the same size, then waits for
+// the images to load.
+const largestSrcsetScript = `(() => {
+	for (const el of document.querySelectorAll("img[srcset], picture source[srcset]")) {
+		let best, bestValue = -1;
+		for (const candidate of el.getAttribute("srcset").split(/,\s+/)) {
+			const [url, descriptor = "1x"] = candidate.trim().split(/\s+/);
+			const value = parseFloat(descriptor);
+			if (url && value > bestValue) {
+				best = candidate.trim();
+				bestValue = value;
+			}
+		}
+		if (best) {
+			el.setAttribute("srcset", best);
+		}
+	}
+	// Images start loading the new candidate in a microtask.
+	return new Promise(resolve => setTimeout(resolve)).then(() =>
+		Promise.all(Array.from(document.images, img => img.decode().catch(() => {}))));
+})()`
+
+// WithImageResolutionPolicy controls which srcset candidate responsive images
+// are printed with. By default Chrome picks candidates for a 1x screen, so
+// PDFs can embed the low-resolution image meant for small displays.
+func WithImageResolutionPolicy(policy ImageResolutionPolicy) Option {
+	return func(o *options) {
+		o.imageResolution = policy
+	}
+}
+
+// deviceScaleFactor returns the device pixel ratio to emulate while
+// rendering, the highest asked for by the image, SVG and canvas options, or 0
+// to keep Chrome's default.
+func (o *options) deviceScaleFactor() float64 {
+	var factor float64
+	if o.imageResolution == ImageResolutionPrint {
+		factor = printDeviceScale
+	}
+	if svg := float64(o.svgDPI) / cssPixelsPerInch; svg > 1 {
+		factor = math.Max(factor, svg)
+	}
+	if o.canvasScale > 1 {
+		factor = math.Max(factor, o.canvasScale)
+	}
+	return factor
+}
+
+// emulateDeviceScale overrides the device pixel ratio of the page, keeping
+// the default viewport size.
+func emulateDeviceScale(factor float64) chromedp.Action {
+	return emulation.SetDeviceMetricsOverride(0, 0, factor, false)
+}
+
+// useLargestImages applies ImageResolutionLargest to the loaded page.
+func useLargestImages() chromedp.Action {
+	return chromedp.Evaluate(largestSrcsetScript, nil, func(p *runtime.EvaluateParams) *runtime.EvaluateParams {
+		return p.WithAwaitPromise(true)
+	})
+}
@@ -0,0 +1,65 @@
+package html2pdf
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/chromedp/chromedp"
+)
+
+func TestWithImageResolutionPolicy(t *testing.T) {
+	tests := []struct {
+		policy ImageResolutionPolicy
+		want   float64
+	}{
+		{policy: ImageResolutionScreen, want: 0},
+		{policy: ImageResolutionPrint, want: 2},
+		{policy: ImageResolutionLargest, want: 0},
+	}
+	for _, tt := range tests {
+		opts := newOptions([]Option{WithImageResolutionPolicy(tt.policy)})
+		if got := opts.deviceScaleFactor(); got != tt.want {
+			t.Errorf("Policy %d: expected device scale factor %g, got %g", tt.policy, tt.want, got)
+		}
+		if tt.policy != ImageResolutionScreen && opts.fingerprint() == getDefaultOptions().fingerprint() {
+			t.Errorf("Policy %d: expected the options fingerprint to change", tt.policy)
+		}
+	}
+}
+
+func TestConvertHtmlToPdfWithImageResolutionPolicy(t *testing.T) {
+	small := "data:image/png;base64," + base64.StdEncoding.EncodeToString(testPNG(t, 1, 1))
+	large := "data:image/png;base64," + base64.StdEncoding.EncodeToString(testPNG(t, 3, 3))
+	tests := []struct {
+		name   string
+		srcset string
+		policy ImageResolutionPolicy
+		want   string
+	}{
+		{name: "screen", srcset: small + " 1x, " + large + " 3x", policy: ImageResolutionScreen, want: small},
+		{name: "print", srcset: small + " 1x, " + large + " 2x", policy: ImageResolutionPrint, want: large},
+		{name: "largest", srcset: small + " 1x, " + large + " 3x", policy: ImageResolutionLargest, want: large},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			defer cancel()
+
+			var src string
+			html := fmt.Sprintf(`<html><body><img srcset="%s"></body></html>`, tt.srcset)
+			_, err := ConvertHtmlToPdf(ctx, html,
+				WithImageResolutionPolicy(tt.policy),
+				WithPostRenderActions(chromedp.Evaluate(`document.images[0].currentSrc`, &src)))
+			if err != nil {
+				t.Fatalf("ConvertHtmlToPdf() error = %v", err)
+			}
+			if src != tt.want {
+				t.Errorf("Expected image %.40s..., got %.40s...", tt.want, src)
+			}
+		})
+	}
+}
@@ -0,0 +1,82 @@
+package html2pdf
+
+import (
+	"encoding/base64"
+	"errors"
+	"io/fs"
+	"mime"
+	"net/http"
+	"net/url"
+	"path"
+	"regexp"
+	"strings"
+)
+
+var (
+	imgSrcPattern = regexp.MustCompile(`(?i)(<img\b[^>]*?\ssrc\s*=\s*["']?)([^"'\s>]+)`)
+	cssURLPattern = regexp.MustCompile(`(?i)(url\(\s*["']?)([^"')\s]+)`)
+)
+
+// InlineAssets rewrites <img src> and CSS url() references in html that point
+// to files in fsys into data URLs, so the page renders without an asset
+// server. References are resolved relative to the root of fsys. Files larger
+// than maxSize bytes, missing files and absolute URLs are left unchanged; a
+// maxSize of 0 or less inlines files of any size.
+func InlineAssets(html string, fsys fs.FS, maxSize int) (string, error) {
+	var firstErr error
+	inline := func(pattern *regexp.Regexp, s string) string {
+		return pattern.ReplaceAllStringFunc(s, func(match string) string {
+			m := pattern.FindStringSubmatch(match)
+			dataURL, err := assetDataURL(fsys, m[2], maxSize)
+			if err != nil && firstErr == nil {
+				firstErr = err
+			}
+			if dataURL == "" {
+				return match
+			}
+			return m[1] + dataURL
+		})
+	}
+	html = inline(cssURLPattern, inline(imgSrcPattern, html))
+	if firstErr != nil {
+		return "", firstErr
+	}
+	return html, nil
+}
+
+// assetDataURL returns ref as a data URL, or "" if it should not be inlined.
+func assetDataURL(fsys fs.FS, ref string, maxSize int) (string, error) {
+	u, err := url.Parse(ref)
+	if err != nil || u.Scheme != "" || u.Host != "" || u.Path == "" {
+		return "", nil
+	}
+	name := strings.TrimPrefix(path.Clean("/"+u.Path), "/")
+	info, err := fs.Stat(fsys, name)
+	if errors.Is(err, fs.ErrNotExist) || (err == nil && info.IsDir()) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	if maxSize > 0 && info.Size() > int64(maxSize) {
+		return "", nil
+	}
+	data, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return "", err
+	}
+	return "data:" + assetType(name, data) + ";base64," + base64.StdEncoding.EncodeToString(data), nil
+}
+
+// assetType returns the media type of the file name with contents data,
+// without parameters.
+func assetType(name string, data []byte) string {
+	t := mime.TypeByExtension(path.Ext(name))
+	if t == "" {
+		t = http.DetectContentType(data)
+	}
+	if mediaType, _, err := mime.ParseMediaType(t); err == nil {
+		return mediaType
+	}
+	return t
+}
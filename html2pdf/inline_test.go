@@ -0,0 +1,98 @@
+package html2pdf
+
+import (
+	"errors"
+	"io/fs"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestInlineAssets(t *testing.T) {
+	fsys := fstest.MapFS{
+		"logo.png":       {Data: testPNG(t, 2, 2)},
+		"img/icon.svg":   {Data: []byte("<svg/>")},
+		"img/big.jpg":    {Data: make([]byte, 2048)},
+		"fonts/body.bin": {Data: []byte{0, 1, 2}},
+	}
+
+	tests := []struct {
+		name     string
+		html     string
+		contains []string
+		excludes []string
+	}{
+		{
+			name:     "img src",
+			html:     `<img alt="logo" src="logo.png">`,
+			contains: []string{`src="data:image/png;base64,`},
+		},
+		{
+			name:     "unquoted nested path",
+			html:     `<IMG src=./img/icon.svg>`,
+			contains: []string{`src=data:image/svg+xml;base64,PHN2Zy8+`},
+		},
+		{
+			name:     "css url",
+			html:     `<style>body { background: url('/img/icon.svg') }</style>`,
+			contains: []string{`url('data:image/svg+xml;base64,PHN2Zy8+')`},
+		},
+		{
+			name:     "sniffed type",
+			html:     `<div style="background-image: url(fonts/body.bin)"></div>`,
+			contains: []string{`url(data:application/octet-stream;base64,AAEC)`},
+		},
+		{
+			name:     "too large",
+			html:     `<img src="img/big.jpg">`,
+			contains: []string{`src="img/big.jpg"`},
+		},
+		{
+			name:     "left unchanged",
+			html:     `<img src="https://example.com/a.png"><img src="missing.png"><img src="data:image/png;base64,AA==">`,
+			contains: []string{`src="https://example.com/a.png"`, `src="missing.png"`, `src="data:image/png;base64,AA=="`},
+		},
+		{
+			name:     "other tags",
+			html:     `<script src="logo.png"></script>`,
+			excludes: []string{"data:"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := InlineAssets(tt.html, fsys, 1024)
+			if err != nil {
+				t.Fatalf("InlineAssets() error = %v", err)
+			}
+			for _, want := range tt.contains {
+				if !strings.Contains(got, want) {
+					t.Errorf("Expected %q in %q", want, got)
+				}
+			}
+			for _, unwanted := range tt.excludes {
+				if strings.Contains(got, unwanted) {
+					t.Errorf("Expected no %q in %q", unwanted, got)
+				}
+			}
+		})
+	}
+
+	got, err := InlineAssets(`<img src="img/big.jpg">`, fsys, 0)
+	if err != nil || !strings.Contains(got, "data:image/jpeg;base64,") {
+		t.Errorf("Expected no size limit for maxSize 0, got %q, %v", got, err)
+	}
+}
+
+// errFS fails every operation.
+type errFS struct{}
+
+func (errFS) Open(name string) (fs.File, error) {
+	return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrPermission}
+}
+
+func TestInlineAssetsError(t *testing.T) {
+	if _, err := InlineAssets(`<img src="logo.png">`, errFS{}, 0); !errors.Is(err, fs.ErrPermission) {
+		t.Errorf("Expected permission error, got %v", err)
+	}
+}
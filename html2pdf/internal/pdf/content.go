@@ -0,0 +1,141 @@
+package pdf
+
+// paintOperators draw marks on the page.
+var paintOperators = map[keyword]bool{
+	"S": true, "s": true, "f": true, "F": true, "f*": true,
+	"B": true, "B*": true, "b": true, "b*": true,
+	"Tj": true, "TJ": true, "'": true, "\"": true,
+	"Do": true, "sh": true, "BI": true,
+}
+
+// fillOperators paint with the fill color only.
+var fillOperators = map[keyword]bool{"f": true, "F": true, "f*": true}
+
+// PageContent returns the decoded content streams of page, concatenated.
+func (d *Document) PageContent(page Ref) ([]byte, error) {
+	var out []byte
+	var streams []Object
+	switch c := d.PageAttr(page, "Contents").(type) {
+	case *Stream:
+		streams = []Object{c}
+	case Array:
+		streams = c
+	}
+	for _, s := range streams {
+		stream, ok := d.Resolve(s).(*Stream)
+		if !ok {
+			continue
+		}
+		data, err := Decode(stream)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, data...)
+		out = append(out, '\n')
+	}
+	return out, nil
+}
+
+// PageIsBlank reports whether page paints nothing visible. Fills in white
+// are ignored, since Chrome paints the page background that way.
+func (d *Document) PageIsBlank(page Ref) (bool, error) {
+	content, err := d.PageContent(page)
+	if err != nil {
+		return false, err
+	}
+
+	p := &parser{data: content}
+	var operands []Object
+	white := false
+	for {
+		p.skipSpace()
+		if p.pos >= len(p.data) {
+			return true, nil
+		}
+		tok, err := p.object()
+		if err != nil {
+			// Content we cannot parse is assumed to draw something.
+			return false, nil
+		}
+		op, ok := tok.(keyword)
+		if !ok {
+			operands = append(operands, tok)
+			continue
+		}
+		switch op {
+		case "g", "rg", "k", "sc", "scn":
+			white = isWhite(op, operands)
+		}
+		if paintOperators[op] && !(fillOperators[op] && white) {
+			return false, nil
+		}
+		operands = operands[:0]
+	}
+}
+
+// isWhite reports whether a fill color operator sets white.
+func isWhite(op keyword, operands []Object) bool {
+	values := make([]float64, 0, len(operands))
+	for _, o := range operands {
+		v, ok := Number(o)
+		if !ok {
+			return false
+		}
+		values = append(values, v)
+	}
+	switch {
+	case op == "k" && len(values) == 4:
+		return values[0] == 0 && values[1] == 0 && values[2] == 0 && values[3] == 0
+	case len(values) == 1 || len(values) == 3:
+		for _, v := range values {
+			if v != 1 {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// AddPageResource adds obj to the category (e.g. XObject) of page's
+// resources under name. The page gets its own copy of its resources, so
+// pages sharing a resource dictionary are not affected.
+func (d *Document) AddPageResource(page Ref, category, name Name, obj Object) {
+	dict, ok := d.Resolve(page).(Dict)
+	if !ok {
+		return
+	}
+	resources := Dict{}
+	if r, ok := d.PageAttr(page, "Resources").(Dict); ok {
+		for k, v := range r {
+			resources[k] = v
+		}
+	}
+	entries := Dict{}
+	if c, ok := d.Resolve(resources[category]).(Dict); ok {
+		for k, v := range c {
+			entries[k] = v
+		}
+	}
+	entries[name] = obj
+	resources[category] = entries
+	dict["Resources"] = resources
+}
+
+// AppendPageContent draws content on top of page. The existing content is
+// wrapped in q/Q so it cannot leave a changed graphics state behind.
+func (d *Document) AppendPageContent(page Ref, content []byte) {
+	dict, ok := d.Resolve(page).(Dict)
+	if !ok {
+		return
+	}
+	contents := Array{d.Add(Encode(nil, []byte("q\n")))}
+	switch c := d.PageAttr(page, "Contents").(type) {
+	case Array:
+		contents = append(contents, c...)
+	case *Stream:
+		contents = append(contents, dict["Contents"])
+	}
+	contents = append(contents, d.Add(Encode(nil, append([]byte("\nQ\n"), content...))))
+	dict["Contents"] = contents
+}
@@ -0,0 +1,615 @@
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+)
+
+var (
+	// ErrEncrypted is returned when parsing an encrypted document.
+	ErrEncrypted = fmt.Errorf("pdf: encrypted documents are not supported")
+	// ErrMalformed is returned when a document cannot be parsed.
+	ErrMalformed = fmt.Errorf("pdf: malformed document")
+)
+
+// Document is a parsed PDF document held fully in memory.
+type Document struct {
+	// Version is the PDF version from the file header, e.g. "1.4".
+	Version string
+	// Trailer is the document trailer; it holds the Root and Info references.
+	Trailer Dict
+
+	objects map[int]Object
+	next    int
+}
+
+// New returns an empty document with a catalog and an empty page tree.
+func New() *Document {
+	d := &Document{Version: "1.4", objects: map[int]Object{}, next: 1}
+	pages := d.Add(Dict{"Type": Name("Pages"), "Kids": Array{}, "Count": 0})
+	root := d.Add(Dict{"Type": Name("Catalog"), "Pages": pages})
+	d.Trailer = Dict{"Root": root}
+	return d
+}
+
+type xrefEntry struct {
+	free   bool
+	offset int // file offset, or index within the object stream
+	stream int // object stream number for compressed objects, 0 otherwise
+}
+
+// Parse parses a PDF document.
+func Parse(data []byte) (*Document, error) {
+	if !bytes.HasPrefix(data, []byte("%PDF-")) {
+		return nil, fmt.Errorf("%w: missing header", ErrMalformed)
+	}
+	d := &Document{objects: map[int]Object{}, Version: "1.4"}
+	if end := bytes.IndexAny(data[5:], "\r\n"); end > 0 {
+		d.Version = string(bytes.TrimSpace(data[5 : 5+end]))
+	}
+
+	if err := d.readXref(data); err != nil {
+		d.objects = map[int]Object{}
+		if err := d.scan(data); err != nil {
+			return nil, err
+		}
+	}
+	if _, ok := d.Trailer["Encrypt"]; ok {
+		return nil, ErrEncrypted
+	}
+	if _, ok := d.Resolve(d.Trailer["Root"]).(Dict); !ok {
+		return nil, fmt.Errorf("%w: missing document catalog", ErrMalformed)
+	}
+
+	for num := range d.objects {
+		if num >= d.next {
+			d.next = num + 1
+		}
+	}
+	return d, nil
+}
+
+// readXref loads all objects listed in the cross-reference sections.
+func (d *Document) readXref(data []byte) error {
+	idx := bytes.LastIndex(data, []byte("startxref"))
+	if idx < 0 {
+		return ErrMalformed
+	}
+	p := &parser{data: data, pos: idx + len("startxref")}
+	tok, err := p.token()
+	offset, ok := tok.(int)
+	if err != nil || !ok {
+		return ErrMalformed
+	}
+
+	entries := map[int]xrefEntry{}
+	visited := map[int]bool{}
+	for offset >= 0 && !visited[offset] {
+		if offset >= len(data) {
+			return ErrMalformed
+		}
+		visited[offset] = true
+
+		var trailer Dict
+		if bytes.HasPrefix(data[offset:], []byte("xref")) {
+			trailer, err = readXrefTable(data, offset, entries)
+			if err != nil {
+				return err
+			}
+			if stm, ok := trailer["XRefStm"].(int); ok {
+				if _, err := readXrefStream(data, stm, entries); err != nil {
+					return err
+				}
+			}
+		} else {
+			trailer, err = readXrefStream(data, offset, entries)
+			if err != nil {
+				return err
+			}
+		}
+		if d.Trailer == nil {
+			d.Trailer = trailer
+		}
+
+		offset = -1
+		if prev, ok := trailer["Prev"].(int); ok {
+			offset = prev
+		}
+	}
+
+	objStreams := map[int][]Object{}
+	for num, e := range entries {
+		if e.free || e.stream != 0 {
+			continue
+		}
+		if e.offset <= 0 || e.offset >= len(data) {
+			return ErrMalformed
+		}
+		p := &parser{data: data, pos: e.offset}
+		n, o, err := p.indirect()
+		if err != nil || n != num {
+			return ErrMalformed
+		}
+		d.objects[num] = o
+	}
+	for num, e := range entries {
+		if e.free || e.stream == 0 {
+			continue
+		}
+		objs, ok := objStreams[e.stream]
+		if !ok {
+			_, objs, err = d.readObjectStream(e.stream)
+			if err != nil {
+				return err
+			}
+			objStreams[e.stream] = objs
+		}
+		if e.offset < len(objs) {
+			d.objects[num] = objs[e.offset]
+		}
+	}
+
+	d.Trailer = trailerCopy(d.Trailer)
+	return nil
+}
+
+// readXrefTable parses a classic "xref" table at offset into entries,
+// keeping entries that were already defined by a newer section.
+func readXrefTable(data []byte, offset int, entries map[int]xrefEntry) (Dict, error) {
+	p := &parser{data: data, pos: offset + len("xref")}
+	for {
+		tok, err := p.token()
+		if err != nil {
+			return nil, ErrMalformed
+		}
+		if tok == keyword("trailer") {
+			return p.dictAfterOpen()
+		}
+		start, ok := tok.(int)
+		if !ok {
+			return nil, ErrMalformed
+		}
+		tok, err = p.token()
+		count, ok := tok.(int)
+		if err != nil || !ok {
+			return nil, ErrMalformed
+		}
+		for i := 0; i < count; i++ {
+			off, err1 := p.token()
+			_, err2 := p.token()
+			kind, err3 := p.token()
+			if err1 != nil || err2 != nil || err3 != nil {
+				return nil, ErrMalformed
+			}
+			if _, exists := entries[start+i]; exists {
+				continue
+			}
+			o, _ := off.(int)
+			entries[start+i] = xrefEntry{free: kind != keyword("n"), offset: o}
+		}
+	}
+}
+
+// dictAfterOpen reads a dictionary including its opening "<<".
+func (p *parser) dictAfterOpen() (Dict, error) {
+	tok, err := p.token()
+	if err != nil || tok != keyword("<<") {
+		return nil, ErrMalformed
+	}
+	return p.dict()
+}
+
+// readXrefStream parses a cross-reference stream at offset into entries.
+func readXrefStream(data []byte, offset int, entries map[int]xrefEntry) (Dict, error) {
+	p := &parser{data: data, pos: offset}
+	_, o, err := p.indirect()
+	if err != nil {
+		return nil, ErrMalformed
+	}
+	s, ok := o.(*Stream)
+	if !ok || s.Dict["Type"] != Name("XRef") {
+		return nil, ErrMalformed
+	}
+	raw, err := Decode(s)
+	if err != nil {
+		return nil, err
+	}
+
+	w, _ := s.Dict["W"].(Array)
+	if len(w) != 3 {
+		return nil, ErrMalformed
+	}
+	var widths [3]int
+	rowLen := 0
+	for i := range widths {
+		widths[i], _ = w[i].(int)
+		rowLen += widths[i]
+	}
+	if rowLen == 0 {
+		return nil, ErrMalformed
+	}
+
+	index := Array{0, s.Dict["Size"]}
+	if idx, ok := s.Dict["Index"].(Array); ok {
+		index = idx
+	}
+	for i := 0; i+1 < len(index); i += 2 {
+		start, _ := index[i].(int)
+		count, _ := index[i+1].(int)
+		for j := 0; j < count; j++ {
+			if len(raw) < rowLen {
+				return nil, ErrMalformed
+			}
+			row := raw[:rowLen]
+			raw = raw[rowLen:]
+			if _, exists := entries[start+j]; exists {
+				continue
+			}
+			var fields [3]int
+			pos := 0
+			for k, width := range widths {
+				for b := 0; b < width; b++ {
+					fields[k] = fields[k]<<8 | int(row[pos])
+					pos++
+				}
+			}
+			kind := fields[0]
+			if widths[0] == 0 {
+				kind = 1
+			}
+			switch kind {
+			case 1:
+				entries[start+j] = xrefEntry{offset: fields[1]}
+			case 2:
+				entries[start+j] = xrefEntry{stream: fields[1], offset: fields[2]}
+			default:
+				entries[start+j] = xrefEntry{free: true}
+			}
+		}
+	}
+	return s.Dict, nil
+}
+
+// readObjectStream returns the object numbers and objects stored in object stream num.
+func (d *Document) readObjectStream(num int) ([]int, []Object, error) {
+	s, ok := d.objects[num].(*Stream)
+	if !ok {
+		return nil, nil, ErrMalformed
+	}
+	data, err := Decode(s)
+	if err != nil {
+		return nil, nil, err
+	}
+	n, _ := s.Dict["N"].(int)
+	first, _ := s.Dict["First"].(int)
+	// Every entry of the header takes at least two bytes, which bounds n
+	// before anything is allocated for it.
+	if n < 0 || first < 0 || first > len(data) || n > len(data)/2 {
+		return nil, nil, ErrMalformed
+	}
+
+	p := &parser{data: data}
+	nums := make([]int, n)
+	offsets := make([]int, n)
+	for i := 0; i < n; i++ {
+		numTok, err1 := p.token()
+		offTok, err2 := p.token()
+		if err1 != nil || err2 != nil {
+			return nil, nil, ErrMalformed
+		}
+		nums[i], _ = numTok.(int)
+		offsets[i], _ = offTok.(int)
+		if offsets[i] < 0 {
+			return nil, nil, ErrMalformed
+		}
+	}
+
+	objs := make([]Object, n)
+	for i, off := range offsets {
+		p := &parser{data: data, pos: first + off}
+		if objs[i], err = p.object(); err != nil {
+			return nil, nil, err
+		}
+	}
+	return nums, objs, nil
+}
+
+var objHeader = regexp.MustCompile(`(\d+)\s+(\d+)\s+obj\b`)
+
+// scan recovers a document whose cross-reference data is broken by
+// scanning the whole file for object definitions.
+func (d *Document) scan(data []byte) error {
+	var xrefStream Dict
+	pos := 0
+	for {
+		loc := objHeader.FindIndex(data[pos:])
+		if loc == nil {
+			break
+		}
+		p := &parser{data: data, pos: pos + loc[0]}
+		num, o, err := p.indirect()
+		if err != nil {
+			pos += loc[1]
+			continue
+		}
+		d.objects[num] = o
+		if s, ok := o.(*Stream); ok && s.Dict["Type"] == Name("XRef") {
+			xrefStream = s.Dict
+		}
+		pos = p.pos
+	}
+
+	for num, o := range d.objects {
+		if s, ok := o.(*Stream); ok && s.Dict["Type"] == Name("ObjStm") {
+			nums, objs, err := d.readObjectStream(num)
+			if err != nil {
+				continue
+			}
+			for i, n := range nums {
+				if _, exists := d.objects[n]; !exists {
+					d.objects[n] = objs[i]
+				}
+			}
+		}
+	}
+
+	if idx := bytes.LastIndex(data, []byte("trailer")); idx >= 0 {
+		p := &parser{data: data, pos: idx + len("trailer")}
+		if t, err := p.dictAfterOpen(); err == nil {
+			d.Trailer = t
+		}
+	}
+	if d.Trailer == nil {
+		d.Trailer = xrefStream
+	}
+	if d.Trailer == nil {
+		d.Trailer = Dict{}
+	}
+	d.Trailer = trailerCopy(d.Trailer)
+
+	if _, ok := d.Resolve(d.Trailer["Root"]).(Dict); !ok {
+		for num, o := range d.objects {
+			if dict, ok := o.(Dict); ok && dict["Type"] == Name("Catalog") {
+				d.Trailer["Root"] = Ref{Num: num}
+				break
+			}
+		}
+	}
+	return nil
+}
+
+// trailerCopy keeps only the trailer entries that survive a rewrite.
+func trailerCopy(t Dict) Dict {
+	out := Dict{}
+	for _, k := range []Name{"Root", "Info", "ID", "Encrypt"} {
+		if v, ok := t[k]; ok {
+			out[k] = v
+		}
+	}
+	return out
+}
+
+// indirect reads "num gen obj ... endobj" at the current position.
+func (p *parser) indirect() (int, Object, error) {
+	numTok, err := p.token()
+	if err != nil {
+		return 0, nil, err
+	}
+	num, ok := numTok.(int)
+	if !ok {
+		return 0, nil, ErrMalformed
+	}
+	if _, err := p.token(); err != nil {
+		return 0, nil, err
+	}
+	if tok, err := p.token(); err != nil || tok != keyword("obj") {
+		return 0, nil, ErrMalformed
+	}
+
+	o, err := p.object()
+	if err != nil {
+		return 0, nil, err
+	}
+	save := p.pos
+	tok, err := p.token()
+	if err != nil {
+		return num, o, nil
+	}
+	if tok == keyword("stream") {
+		dict, ok := o.(Dict)
+		if !ok {
+			return 0, nil, ErrMalformed
+		}
+		data, err := p.streamData(dict)
+		if err != nil {
+			return 0, nil, err
+		}
+		o = &Stream{Dict: dict, Data: data}
+		save = p.pos
+		tok, _ = p.token()
+	}
+	if tok != keyword("endobj") {
+		p.pos = save
+	}
+	return num, o, nil
+}
+
+// streamData reads the raw stream bytes following the "stream" keyword.
+func (p *parser) streamData(dict Dict) ([]byte, error) {
+	if p.pos < len(p.data) && p.data[p.pos] == '\r' {
+		p.pos++
+	}
+	if p.pos < len(p.data) && p.data[p.pos] == '\n' {
+		p.pos++
+	}
+	start := p.pos
+
+	if length, ok := dict["Length"].(int); ok && length >= 0 && start+length <= len(p.data) {
+		rest := &parser{data: p.data, pos: start + length}
+		rest.skipSpace()
+		if bytes.HasPrefix(p.data[rest.pos:], []byte("endstream")) {
+			p.pos = rest.pos + len("endstream")
+			return p.data[start : start+length], nil
+		}
+	}
+
+	end := bytes.Index(p.data[start:], []byte("endstream"))
+	if end < 0 {
+		return nil, fmt.Errorf("%w: unterminated stream", ErrMalformed)
+	}
+	data := p.data[start : start+end]
+	data = bytes.TrimSuffix(data, []byte("\n"))
+	data = bytes.TrimSuffix(data, []byte("\r"))
+	p.pos = start + end + len("endstream")
+	return data, nil
+}
+
+// Get returns the object with the given reference, or nil if it does not exist.
+func (d *Document) Get(ref Ref) Object {
+	return d.objects[ref.Num]
+}
+
+// Resolve follows o if it is a reference and returns the referenced object.
+func (d *Document) Resolve(o Object) Object {
+	for i := 0; i < 32; i++ {
+		ref, ok := o.(Ref)
+		if !ok {
+			return o
+		}
+		o = d.objects[ref.Num]
+	}
+	return nil
+}
+
+// Add stores o as a new indirect object and returns its reference.
+func (d *Document) Add(o Object) Ref {
+	ref := Ref{Num: d.next}
+	d.objects[d.next] = o
+	d.next++
+	return ref
+}
+
+// Set replaces the indirect object ref with o.
+func (d *Document) Set(ref Ref, o Object) {
+	d.objects[ref.Num] = o
+	if ref.Num >= d.next {
+		d.next = ref.Num + 1
+	}
+}
+
+// Catalog returns the document catalog.
+func (d *Document) Catalog() Dict {
+	c, _ := d.Resolve(d.Trailer["Root"]).(Dict)
+	return c
+}
+
+// Info returns the document information dictionary, creating it if needed.
+func (d *Document) Info() Dict {
+	if info, ok := d.Resolve(d.Trailer["Info"]).(Dict); ok {
+		return info
+	}
+	info := Dict{}
+	d.Trailer["Info"] = d.Add(info)
+	return info
+}
+
+// SetInfo sets a text entry in the document information dictionary.
+func (d *Document) SetInfo(key, value string) {
+	d.Info()[Name(key)] = TextString(value)
+}
+
+// Bytes serializes the document. Only objects reachable from the trailer are
+// written, and they are renumbered consecutively.
+func (d *Document) Bytes() []byte {
+	return d.AppendBytes(nil)
+}
+
+// AppendBytes serializes the document like Bytes, appending to dst, and
+// returns the extended buffer.
+func (d *Document) AppendBytes(dst []byte) []byte {
+	renumber := map[int]int{}
+	var order []int
+	var visit func(o Object)
+	visit = func(o Object) {
+		switch v := o.(type) {
+		case Ref:
+			if _, seen := renumber[v.Num]; seen {
+				return
+			}
+			if _, exists := d.objects[v.Num]; !exists {
+				return
+			}
+			renumber[v.Num] = 0
+			order = append(order, v.Num)
+			visit(d.objects[v.Num])
+		case Array:
+			for _, item := range v {
+				visit(item)
+			}
+		case Dict:
+			for _, item := range v {
+				visit(item)
+			}
+		case *Stream:
+			visit(v.Dict)
+		}
+	}
+	visit(d.Trailer)
+	sort.Ints(order)
+	for i, num := range order {
+		renumber[num] = i + 1
+	}
+
+	buf := bytes.NewBuffer(dst)
+	start := buf.Len()
+	buf.WriteString("%PDF-" + d.Version + "\n%\xe2\xe3\xcf\xd3\n")
+	offsets := make([]int, len(order))
+	for i, num := range order {
+		offsets[i] = buf.Len() - start
+		buf.WriteString(strconv.Itoa(i+1) + " 0 obj\n")
+		writeObject(buf, remap(d.objects[num], renumber))
+		buf.WriteString("\nendobj\n")
+	}
+
+	xref := buf.Len() - start
+	fmt.Fprintf(buf, "xref\n0 %d\n0000000000 65535 f \n", len(order)+1)
+	for _, off := range offsets {
+		fmt.Fprintf(buf, "%010d 00000 n \n", off)
+	}
+	trailer := remap(d.Trailer, renumber).(Dict)
+	trailer["Size"] = len(order) + 1
+	buf.WriteString("trailer\n")
+	writeObject(buf, trailer)
+	fmt.Fprintf(buf, "\nstartxref\n%d\n%%%%EOF\n", xref)
+	return buf.Bytes()
+}
+
+// remap returns a copy of o with references renumbered. References to
+// objects that are not written become null.
+func remap(o Object, renumber map[int]int) Object {
+	switch v := o.(type) {
+	case Ref:
+		if n, ok := renumber[v.Num]; ok && n > 0 {
+			return Ref{Num: n}
+		}
+		return nil
+	case Array:
+		out := make(Array, len(v))
+		for i, item := range v {
+			out[i] = remap(item, renumber)
+		}
+		return out
+	case Dict:
+		out := make(Dict, len(v))
+		for k, item := range v {
+			out[k] = remap(item, renumber)
+		}
+		return out
+	case *Stream:
+		return &Stream{Dict: remap(v.Dict, renumber).(Dict), Data: v.Data}
+	}
+	return o
+}
@@ -0,0 +1,465 @@
+package pdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"strings"
+	"testing"
+)
+
+// newTestDocument returns a document with the given number of Letter pages.
+func newTestDocument(pages int) *Document {
+	d := New()
+	root := d.Catalog()["Pages"].(Ref)
+	tree := d.Get(root).(Dict)
+	kids := Array{}
+	for i := 0; i < pages; i++ {
+		content := Encode(nil, []byte(fmt.Sprintf("BT /F1 12 Tf 72 720 Td (Page %d) Tj ET", i+1)))
+		kids = append(kids, d.Add(Dict{
+			"Type":     Name("Page"),
+			"Parent":   root,
+			"MediaBox": Array{0, 0, 612, 792},
+			"Contents": d.Add(content),
+		}))
+	}
+	tree["Kids"] = kids
+	tree["Count"] = pages
+	return d
+}
+
+func TestDocumentRoundTrip(t *testing.T) {
+	d := newTestDocument(3)
+	d.SetInfo("Title", "Report")
+	d.SetInfo("Subject", "Überblick")
+
+	parsed, err := Parse(d.Bytes())
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	pages, err := parsed.Pages()
+	if err != nil {
+		t.Fatalf("Pages() error = %v", err)
+	}
+	if len(pages) != 3 {
+		t.Errorf("Expected 3 pages, got %d", len(pages))
+	}
+	if got := string(parsed.Info()["Title"].(String)); got != "Report" {
+		t.Errorf("Expected title 'Report', got %q", got)
+	}
+	if got := parsed.Info()["Subject"].(String); !bytes.Equal(got, TextString("Überblick")) {
+		t.Errorf("Expected UTF-16 subject, got %q", got)
+	}
+
+	content, err := Decode(parsed.Resolve(parsed.Get(pages[1]).(Dict)["Contents"]).(*Stream))
+	if err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+	if !strings.Contains(string(content), "(Page 2)") {
+		t.Errorf("Expected second page content, got %q", content)
+	}
+}
+
+func TestAppendBytes(t *testing.T) {
+	d := newTestDocument(2)
+	want := d.Bytes()
+
+	got := d.AppendBytes([]byte("prefix"))
+	if !bytes.HasPrefix(got, []byte("prefix")) || !bytes.Equal(got[len("prefix"):], want) {
+		t.Errorf("Expected the document after the prefix")
+	}
+	reused := d.AppendBytes(make([]byte, 0, len(want)))
+	if !bytes.Equal(reused, want) {
+		t.Errorf("Expected the same document in a reused buffer")
+	}
+	if _, err := Parse(reused); err != nil {
+		t.Errorf("Parse() error = %v", err)
+	}
+}
+
+func TestParseXrefStream(t *testing.T) {
+	data := buildXrefStreamPDF()
+
+	d, err := Parse(data)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	pages, err := d.Pages()
+	if err != nil {
+		t.Fatalf("Pages() error = %v", err)
+	}
+	if len(pages) != 1 {
+		t.Fatalf("Expected 1 page, got %d", len(pages))
+	}
+	box := d.PageBox(pages[0], "MediaBox")
+	if box.Width() != 595 || box.Height() != 842 {
+		t.Errorf("Expected inherited A4 MediaBox, got %+v", box)
+	}
+	if got := d.PageRotation(pages[0]); got != 90 {
+		t.Errorf("Expected rotation 90, got %d", got)
+	}
+}
+
+func TestParseBrokenXref(t *testing.T) {
+	data := newTestDocument(2).Bytes()
+	idx := bytes.LastIndex(data, []byte("startxref"))
+	broken := append(append([]byte{}, data[:idx]...), []byte("startxref\n999999\n%%EOF\n")...)
+
+	d, err := Parse(broken)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	pages, err := d.Pages()
+	if err != nil || len(pages) != 2 {
+		t.Errorf("Expected 2 pages after recovery, got %d (err %v)", len(pages), err)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want error
+	}{
+		{name: "not a pdf", data: "hello", want: ErrMalformed},
+		{name: "no catalog", data: "%PDF-1.4\n1 0 obj\n<<>>\nendobj\ntrailer\n<<>>\n%%EOF", want: ErrMalformed},
+		{
+			name: "encrypted",
+			data: "%PDF-1.4\n1 0 obj\n<</Type/Catalog>>\nendobj\ntrailer\n<</Root 1 0 R/Encrypt<<>>>>\n%%EOF",
+			want: ErrEncrypted,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse([]byte(tt.data))
+			if err == nil || !strings.Contains(err.Error(), tt.want.Error()) {
+				t.Errorf("Parse() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadObjectStreamBounds(t *testing.T) {
+	tests := []struct {
+		name string
+		dict Dict
+		data string
+	}{
+		{name: "huge count", dict: Dict{"N": 1 << 40, "First": 4}, data: "1 0 <<>>"},
+		{name: "negative first", dict: Dict{"N": 1, "First": -4}, data: "1 0 <<>>"},
+		{name: "negative offset", dict: Dict{"N": 1, "First": 5}, data: "1 -9 <<>>"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := &Document{objects: map[int]Object{1: &Stream{Dict: tt.dict, Data: []byte(tt.data)}}}
+			if _, _, err := d.readObjectStream(1); !errors.Is(err, ErrMalformed) {
+				t.Errorf("readObjectStream() error = %v, want ErrMalformed", err)
+			}
+		})
+	}
+}
+
+func TestParseStrings(t *testing.T) {
+	p := &parser{data: []byte(`[(a\(b\)\\c\101) <48656C6C6F> /A#20B 1 0 R -.5 +3]`)}
+	o, err := p.object()
+	if err != nil {
+		t.Fatalf("object() error = %v", err)
+	}
+	arr := o.(Array)
+	want := Array{String(`a(b)\cA`), String("Hello"), Name("A B"), Ref{Num: 1}, -0.5, 3}
+	if fmt.Sprint(arr) != fmt.Sprint(want) {
+		t.Errorf("object() = %v, want %v", arr, want)
+	}
+
+	var buf bytes.Buffer
+	writeObject(&buf, arr)
+	reparsed, err := (&parser{data: buf.Bytes()}).object()
+	if err != nil || fmt.Sprint(reparsed) != fmt.Sprint(want) {
+		t.Errorf("Round trip = %v (err %v), want %v", reparsed, err, want)
+	}
+}
+
+// buildXrefStreamPDF returns a PDF 1.5 file whose objects live in an object
+// stream and are indexed by a predictor-encoded cross-reference stream.
+func buildXrefStreamPDF() []byte {
+	objs := []string{
+		"<</Type/Catalog/Pages 2 0 R>>",
+		"<</Type/Pages/Kids[3 0 R]/Count 1/MediaBox[0 0 595 842]/Rotate 90>>",
+		"<</Type/Page/Parent 2 0 R>>",
+	}
+	var header, body bytes.Buffer
+	for i, o := range objs {
+		fmt.Fprintf(&header, "%d %d ", i+1, body.Len())
+		body.WriteString(o + " ")
+	}
+	objStm := append(header.Bytes(), body.Bytes()...)
+
+	var buf bytes.Buffer
+	buf.WriteString("%PDF-1.5\n")
+	stmOffset := buf.Len()
+	fmt.Fprintf(&buf, "4 0 obj\n<</Type/ObjStm/N 3/First %d/Length %d>>\nstream\n%s\nendstream\nendobj\n",
+		header.Len(), len(objStm), objStm)
+
+	xrefOffset := buf.Len()
+	rows := [][]byte{
+		{0, 0, 0, 0},
+		{2, 0, 4, 0},
+		{2, 0, 4, 1},
+		{2, 0, 4, 2},
+		{1, byte(stmOffset >> 8), byte(stmOffset), 0},
+		{1, byte(xrefOffset >> 8), byte(xrefOffset), 0},
+	}
+	var raw bytes.Buffer
+	prev := make([]byte, 4)
+	for _, row := range rows {
+		raw.WriteByte(2) // PNG Up predictor
+		for i, b := range row {
+			raw.WriteByte(b - prev[i])
+		}
+		prev = row
+	}
+	var compressed bytes.Buffer
+	w := zlib.NewWriter(&compressed)
+	w.Write(raw.Bytes())
+	w.Close()
+
+	fmt.Fprintf(&buf, "5 0 obj\n<</Type/XRef/Size 6/W[1 2 1]/Root 1 0 R/Filter/FlateDecode/DecodeParms<</Columns 4/Predictor 12>>/Length %d>>\nstream\n",
+		compressed.Len())
+	buf.Write(compressed.Bytes())
+	fmt.Fprintf(&buf, "\nendstream\nendobj\nstartxref\n%d\n%%%%EOF\n", xrefOffset)
+	return buf.Bytes()
+}
+
+func TestMerge(t *testing.T) {
+	a, _ := Parse(newTestDocument(2).Bytes())
+	b, _ := Parse(buildXrefStreamPDF())
+
+	merged, err := Merge(a, b)
+	if err != nil {
+		t.Fatalf("Merge() error = %v", err)
+	}
+	d, err := Parse(merged.Bytes())
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	pages, err := d.Pages()
+	if err != nil {
+		t.Fatalf("Pages() error = %v", err)
+	}
+	if len(pages) != 3 {
+		t.Fatalf("Expected 3 pages, got %d", len(pages))
+	}
+	if box := d.PageBox(pages[0], "MediaBox"); box.Width() != 612 {
+		t.Errorf("Expected Letter first page, got %+v", box)
+	}
+	// The last page inherited its box and rotation in the source document.
+	if box := d.PageBox(pages[2], "MediaBox"); box.Width() != 595 || d.PageRotation(pages[2]) != 90 {
+		t.Errorf("Expected inherited attributes to be copied, got %+v rotation %d", box, d.PageRotation(pages[2]))
+	}
+}
+
+func TestImportPagesReplacesRange(t *testing.T) {
+	base, _ := Parse(newTestDocument(4).Bytes())
+	other, _ := Parse(newTestDocument(2).Bytes())
+
+	pages, _ := base.Pages()
+	otherPages, _ := other.Pages()
+	imported := base.ImportPages(other, otherPages)
+	pages = append(pages[:1], append(imported, pages[3:]...)...)
+	base.SetPages(pages)
+
+	d, err := Parse(base.Bytes())
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	got, _ := d.Pages()
+	var texts []string
+	for _, p := range got {
+		content, _ := Decode(d.Resolve(d.Get(p).(Dict)["Contents"]).(*Stream))
+		texts = append(texts, string(content[strings.Index(string(content), "("):strings.Index(string(content), ")")+1]))
+	}
+	want := "(Page 1) (Page 1) (Page 2) (Page 4)"
+	if strings.Join(texts, " ") != want {
+		t.Errorf("Expected pages %q, got %q", want, strings.Join(texts, " "))
+	}
+}
+
+func TestPageIsBlank(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    bool
+	}{
+		{name: "empty", content: "", want: true},
+		{name: "state only", content: "q 1 0 0 -1 0 792 cm 0.75 0 0 0.75 0 0 cm Q", want: true},
+		{name: "white background", content: "q 1 1 1 rg 0 0 612 792 re f Q", want: true},
+		{name: "gray white background", content: "1 g 0 0 612 792 re f*", want: true},
+		{name: "text", content: "BT /F1 12 Tf (Hello) Tj ET", want: false},
+		{name: "colored fill", content: "0.2 0.4 0.6 rg 0 0 100 100 re f", want: false},
+		{name: "stroke in white", content: "1 1 1 RG 0 0 m 100 100 l S", want: false},
+		{name: "image", content: "q 100 0 0 100 0 0 cm /Im1 Do Q", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := New()
+			root := d.Catalog()["Pages"].(Ref)
+			page := d.Add(Dict{"Type": Name("Page"), "Parent": root, "Contents": d.Add(Encode(nil, []byte(tt.content)))})
+			d.SetPages([]Ref{page})
+
+			got, err := d.PageIsBlank(page)
+			if err != nil {
+				t.Fatalf("PageIsBlank() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("PageIsBlank() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPageText(t *testing.T) {
+	cmap := `/CIDInit /ProcSet findresource begin
+12 dict begin
+begincmap
+1 begincodespacerange
+<0000> <FFFF>
+endcodespacerange
+1 beginbfchar
+<0001> <0048>
+endbfchar
+1 beginbfrange
+<0002> <0003> <0069>
+endbfrange
+endcmap
+end
+end`
+
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{name: "empty", content: "", want: ""},
+		{name: "lines", content: "BT /F1 12 Tf 72 720 Td (Hello) Tj 0 -14 Td (World) Tj ET", want: "Hello\nWorld"},
+		{name: "positioned words", content: "BT /F1 12 Tf 72 720 Td (Grand) Tj 40 0 Td (total) Tj ET", want: "Grand total"},
+		{name: "kerning and word gap", content: "BT /F1 12 Tf [(Gr) 20 (and) -1000 (total)] TJ ET", want: "Grand total"},
+		{name: "next line operators", content: "BT /F1 12 Tf 14 TL (one) Tj (two) ' 0 0 (three) \" ET", want: "one\ntwo\nthree"},
+		{name: "flipped like Chrome", content: "1 0 0 -1 0 792 cm BT /F1 12 Tf 1 0 0 -1 72 72 Tm (A) Tj 0 -14 Td (B) Tj ET", want: "A\nB"},
+		{name: "composite font", content: "BT /F2 10 Tf <000100020003> Tj ET", want: "Hij"},
+		{name: "form", content: "BT /F1 12 Tf (Before) Tj ET q 1 0 0 1 0 -100 cm /Fm1 Do Q", want: "Before\nInside"},
+		{name: "inline image", content: "BI /W 1 /H 1 /BPC 8 /CS /G ID \xffEI EI BT (After) Tj ET", want: "After"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := New()
+			root := d.Catalog()["Pages"].(Ref)
+			font := d.Add(Dict{
+				"Type":     Name("Font"),
+				"Subtype":  Name("Type0"),
+				"Encoding": Name("Identity-H"),
+				"DescendantFonts": Array{Dict{
+					"Subtype": Name("CIDFontType2"),
+					"DW":      1000,
+					"W":       Array{1, Array{700, 250}, 3, 3, 250},
+				}},
+				"ToUnicode": d.Add(Encode(nil, []byte(cmap))),
+			})
+			form := Encode(Dict{"Subtype": Name("Form"), "BBox": Array{0, 0, 612, 792}}, []byte("BT /F1 12 Tf (Inside) Tj ET"))
+			page := d.Add(Dict{
+				"Type":      Name("Page"),
+				"Parent":    root,
+				"Resources": Dict{"Font": Dict{"F2": font}, "XObject": Dict{"Fm1": d.Add(form)}},
+				"Contents":  d.Add(Encode(nil, []byte(tt.content))),
+			})
+			d.SetPages([]Ref{page})
+
+			got, err := d.PageText(page)
+			if err != nil {
+				t.Fatalf("PageText() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("PageText() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMatrix(t *testing.T) {
+	tests := []struct {
+		name   string
+		m      Matrix
+		x, y   float64
+		wx, wy float64
+	}{
+		{name: "rotate 90 moves bottom-left to top-left", m: RotateClockwise(90, 200, 100), x: 0, y: 0, wx: 0, wy: 200},
+		{name: "rotate 180", m: RotateClockwise(180, 200, 100), x: 0, y: 0, wx: 200, wy: 100},
+		{name: "rotate 270 moves bottom-left to bottom-right", m: RotateClockwise(270, 200, 100), x: 0, y: 0, wx: 100, wy: 0},
+		{name: "translate then scale", m: Translate(10, 20).Then(Scale(2, 3)), x: 1, y: 1, wx: 22, wy: 63},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			x, y := tt.m.Apply(tt.x, tt.y)
+			if x != tt.wx || y != tt.wy {
+				t.Errorf("Apply(%v, %v) = (%v, %v), want (%v, %v)", tt.x, tt.y, x, y, tt.wx, tt.wy)
+			}
+		})
+	}
+}
+
+func TestAddImage(t *testing.T) {
+	gray := image.NewGray(image.Rect(0, 0, 3, 2))
+	var jpg bytes.Buffer
+	if err := jpeg.Encode(&jpg, gray, nil); err != nil {
+		t.Fatalf("Failed to encode JPEG: %v", err)
+	}
+	translucent := image.NewNRGBA(image.Rect(0, 0, 2, 2))
+	translucent.Set(1, 1, color.NRGBA{R: 0xff, A: 0x80})
+	var pngData bytes.Buffer
+	if err := png.Encode(&pngData, translucent); err != nil {
+		t.Fatalf("Failed to encode PNG: %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		data      []byte
+		w, h      int
+		filter    Object
+		wantSMask bool
+	}{
+		{name: "jpeg passthrough", data: jpg.Bytes(), w: 3, h: 2, filter: Name("DCTDecode")},
+		{name: "png with alpha", data: pngData.Bytes(), w: 2, h: 2, filter: Name("FlateDecode"), wantSMask: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := New()
+			ref, w, h, err := doc.AddImage(tt.data)
+			if err != nil {
+				t.Fatalf("AddImage() error = %v", err)
+			}
+			if w != tt.w || h != tt.h {
+				t.Errorf("Expected %dx%d, got %dx%d", tt.w, tt.h, w, h)
+			}
+			img := doc.Resolve(ref).(*Stream)
+			if img.Dict["Filter"] != tt.filter {
+				t.Errorf("Expected filter %v, got %v", tt.filter, img.Dict["Filter"])
+			}
+			if _, ok := img.Dict["SMask"]; ok != tt.wantSMask {
+				t.Errorf("Expected soft mask %v, got %v", tt.wantSMask, ok)
+			}
+		})
+	}
+
+	if _, _, _, err := New().AddImage([]byte("not an image")); err == nil {
+		t.Error("Expected an error for invalid image data")
+	}
+}
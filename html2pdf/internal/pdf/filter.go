@@ -0,0 +1,149 @@
+package pdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"io"
+)
+
+// Decode returns the decoded content of s. Only FlateDecode (with optional
+// PNG predictors) is supported; streams with other filters return an error.
+func Decode(s *Stream) ([]byte, error) {
+	filters, params := streamFilters(s.Dict)
+	data := s.Data
+	for i, f := range filters {
+		switch f {
+		case "FlateDecode", "Fl":
+			r, err := zlib.NewReader(bytes.NewReader(data))
+			if err != nil {
+				return nil, fmt.Errorf("pdf: flate: %w", err)
+			}
+			out, err := io.ReadAll(r)
+			if err != nil && len(out) == 0 {
+				return nil, fmt.Errorf("pdf: flate: %w", err)
+			}
+			data = out
+			if i < len(params) {
+				if data, err = unpredict(data, params[i]); err != nil {
+					return nil, err
+				}
+			}
+		default:
+			return nil, fmt.Errorf("pdf: unsupported filter %s", f)
+		}
+	}
+	return data, nil
+}
+
+// Encode returns a FlateDecode stream holding data.
+func Encode(dict Dict, data []byte) *Stream {
+	var buf bytes.Buffer
+	w := zlib.NewWriter(&buf)
+	w.Write(data)
+	w.Close()
+
+	if dict == nil {
+		dict = Dict{}
+	}
+	dict["Filter"] = Name("FlateDecode")
+	delete(dict, "DecodeParms")
+	return &Stream{Dict: dict, Data: buf.Bytes()}
+}
+
+func streamFilters(d Dict) ([]Name, []Dict) {
+	var filters []Name
+	switch f := d["Filter"].(type) {
+	case Name:
+		filters = []Name{f}
+	case Array:
+		for _, item := range f {
+			if n, ok := item.(Name); ok {
+				filters = append(filters, n)
+			}
+		}
+	}
+	var params []Dict
+	switch p := d["DecodeParms"].(type) {
+	case Dict:
+		params = []Dict{p}
+	case Array:
+		for _, item := range p {
+			dp, _ := item.(Dict)
+			params = append(params, dp)
+		}
+	}
+	return filters, params
+}
+
+// unpredict reverses the PNG predictors used by xref and object streams.
+func unpredict(data []byte, params Dict) ([]byte, error) {
+	predictor, _ := params["Predictor"].(int)
+	if predictor < 10 {
+		return data, nil
+	}
+	columns := 1
+	if c, ok := params["Columns"].(int); ok {
+		columns = c
+	}
+	colors := 1
+	if c, ok := params["Colors"].(int); ok {
+		colors = c
+	}
+	bpc := 8
+	if b, ok := params["BitsPerComponent"].(int); ok {
+		bpc = b
+	}
+	bpp := (colors*bpc + 7) / 8
+	rowLen := (columns*colors*bpc + 7) / 8
+
+	var out []byte
+	prev := make([]byte, rowLen)
+	for len(data) > 0 {
+		if len(data) < rowLen+1 {
+			return nil, fmt.Errorf("pdf: truncated predictor row")
+		}
+		filter, row := data[0], append([]byte(nil), data[1:rowLen+1]...)
+		data = data[rowLen+1:]
+		for i := range row {
+			var left, up, upLeft byte
+			if i >= bpp {
+				left = row[i-bpp]
+				upLeft = prev[i-bpp]
+			}
+			up = prev[i]
+			switch filter {
+			case 1:
+				row[i] += left
+			case 2:
+				row[i] += up
+			case 3:
+				row[i] += byte((int(left) + int(up)) / 2)
+			case 4:
+				row[i] += paeth(left, up, upLeft)
+			}
+		}
+		out = append(out, row...)
+		prev = row
+	}
+	return out, nil
+}
+
+func paeth(a, b, c byte) byte {
+	p := int(a) + int(b) - int(c)
+	pa, pb, pc := abs(p-int(a)), abs(p-int(b)), abs(p-int(c))
+	if pa <= pb && pa <= pc {
+		return a
+	}
+	if pb <= pc {
+		return b
+	}
+	return c
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
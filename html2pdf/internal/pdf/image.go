@@ -0,0 +1,113 @@
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	_ "image/gif" // register GIF decoding
+	"image/jpeg"
+	_ "image/png" // register PNG decoding
+)
+
+// Image is an encoded image XObject that is not yet part of a document.
+type Image struct {
+	Stream *Stream
+	// SMask holds the alpha channel, or nil for opaque images.
+	SMask  *Stream
+	Width  int
+	Height int
+}
+
+// AddImage adds an image XObject for a JPEG, PNG or GIF image and returns
+// its reference and pixel size.
+func (d *Document) AddImage(data []byte) (ref Ref, width, height int, err error) {
+	img, err := NewImage(data)
+	if err != nil {
+		return Ref{}, 0, 0, err
+	}
+	return d.AddImageObject(img), img.Width, img.Height, nil
+}
+
+// AddImageObject adds an image created with NewImage and returns its reference.
+func (d *Document) AddImageObject(img *Image) Ref {
+	if img.SMask != nil {
+		img.Stream.Dict["SMask"] = d.Add(img.SMask)
+	}
+	return d.Add(img.Stream)
+}
+
+// NewImage encodes a JPEG, PNG or GIF image as an image XObject. Baseline RGB
+// and grayscale JPEGs are embedded as is; other images are stored
+// losslessly, with a soft mask for transparency. It does not touch any
+// document, so images can be encoded concurrently.
+func NewImage(data []byte) (*Image, error) {
+	cfg, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("pdf: image: %w", err)
+	}
+	if format == "jpeg" {
+		var cs Name
+		switch cfg.ColorModel {
+		case color.GrayModel:
+			cs = "DeviceGray"
+		case color.YCbCrModel:
+			cs = "DeviceRGB"
+		}
+		if cs != "" {
+			return &Image{Stream: &Stream{Dict: Dict{
+				"Type":             Name("XObject"),
+				"Subtype":          Name("Image"),
+				"Width":            cfg.Width,
+				"Height":           cfg.Height,
+				"ColorSpace":       cs,
+				"BitsPerComponent": 8,
+				"Filter":           Name("DCTDecode"),
+			}, Data: data}, Width: cfg.Width, Height: cfg.Height}, nil
+		}
+	}
+
+	var img image.Image
+	if format == "jpeg" {
+		img, err = jpeg.Decode(bytes.NewReader(data))
+	} else {
+		img, _, err = image.Decode(bytes.NewReader(data))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("pdf: image: %w", err)
+	}
+	b := img.Bounds()
+	rgb := make([]byte, 0, b.Dx()*b.Dy()*3)
+	alpha := make([]byte, 0, b.Dx()*b.Dy())
+	opaque := true
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			c := color.NRGBAModel.Convert(img.At(x, y)).(color.NRGBA)
+			rgb = append(rgb, c.R, c.G, c.B)
+			alpha = append(alpha, c.A)
+			opaque = opaque && c.A == 0xff
+		}
+	}
+
+	dict := Dict{
+		"Type":             Name("XObject"),
+		"Subtype":          Name("Image"),
+		"Width":            b.Dx(),
+		"Height":           b.Dy(),
+		"ColorSpace":       Name("DeviceRGB"),
+		"BitsPerComponent": 8,
+	}
+	out := &Image{Width: b.Dx(), Height: b.Dy()}
+	if !opaque {
+		out.SMask = Encode(Dict{
+			"Type":             Name("XObject"),
+			"Subtype":          Name("Image"),
+			"Width":            b.Dx(),
+			"Height":           b.Dy(),
+			"ColorSpace":       Name("DeviceGray"),
+			"BitsPerComponent": 8,
+		}, alpha)
+	}
+	out.Stream = Encode(dict, rgb)
+	return out, nil
+}
@@ -0,0 +1,320 @@
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+)
+
+// keyword is a bare token such as obj, endobj, stream, R or an operator.
+type keyword string
+
+// parser reads PDF objects from a byte slice.
+type parser struct {
+	data []byte
+	pos  int
+}
+
+func isWhitespace(c byte) bool {
+	switch c {
+	case 0, '\t', '\n', '\f', '\r', ' ':
+		return true
+	}
+	return false
+}
+
+func isDelimiter(c byte) bool {
+	switch c {
+	case '(', ')', '<', '>', '[', ']', '{', '}', '/', '%':
+		return true
+	}
+	return false
+}
+
+// skipSpace skips whitespace and comments.
+func (p *parser) skipSpace() {
+	for p.pos < len(p.data) {
+		c := p.data[p.pos]
+		if isWhitespace(c) {
+			p.pos++
+			continue
+		}
+		if c == '%' {
+			for p.pos < len(p.data) && p.data[p.pos] != '\n' && p.data[p.pos] != '\r' {
+				p.pos++
+			}
+			continue
+		}
+		return
+	}
+}
+
+// token reads the next token. Structural tokens are returned as keywords
+// ("<<", ">>", "[", "]"), other values as their Object representation.
+func (p *parser) token() (Object, error) {
+	p.skipSpace()
+	if p.pos >= len(p.data) {
+		return nil, fmt.Errorf("pdf: unexpected end of data")
+	}
+	c := p.data[p.pos]
+	switch {
+	case c == '/':
+		return p.readName(), nil
+	case c == '(':
+		return p.readLiteralString()
+	case c == '<':
+		if p.pos+1 < len(p.data) && p.data[p.pos+1] == '<' {
+			p.pos += 2
+			return keyword("<<"), nil
+		}
+		return p.readHexString()
+	case c == '>':
+		if p.pos+1 < len(p.data) && p.data[p.pos+1] == '>' {
+			p.pos += 2
+			return keyword(">>"), nil
+		}
+		return nil, fmt.Errorf("pdf: unexpected '>' at offset %d", p.pos)
+	case c == '[' || c == ']' || c == '{' || c == '}':
+		p.pos++
+		return keyword(c), nil
+	case c == '+' || c == '-' || c == '.' || (c >= '0' && c <= '9'):
+		return p.readNumber()
+	}
+
+	start := p.pos
+	for p.pos < len(p.data) && !isWhitespace(p.data[p.pos]) && !isDelimiter(p.data[p.pos]) {
+		p.pos++
+	}
+	if start == p.pos {
+		p.pos++
+		return nil, fmt.Errorf("pdf: unexpected character %q at offset %d", c, start)
+	}
+	switch word := string(p.data[start:p.pos]); word {
+	case "true":
+		return true, nil
+	case "false":
+		return false, nil
+	case "null":
+		return nil, nil
+	default:
+		return keyword(word), nil
+	}
+}
+
+func (p *parser) readName() Name {
+	p.pos++ // '/'
+	var name []byte
+	for p.pos < len(p.data) {
+		c := p.data[p.pos]
+		if isWhitespace(c) || isDelimiter(c) {
+			break
+		}
+		if c == '#' && p.pos+2 < len(p.data) {
+			if v, err := strconv.ParseUint(string(p.data[p.pos+1:p.pos+3]), 16, 8); err == nil {
+				name = append(name, byte(v))
+				p.pos += 3
+				continue
+			}
+		}
+		name = append(name, c)
+		p.pos++
+	}
+	return Name(name)
+}
+
+func (p *parser) readNumber() (Object, error) {
+	start := p.pos
+	p.pos++
+	for p.pos < len(p.data) {
+		c := p.data[p.pos]
+		if (c < '0' || c > '9') && c != '.' {
+			break
+		}
+		p.pos++
+	}
+	s := string(p.data[start:p.pos])
+	if !bytes.ContainsRune(p.data[start:p.pos], '.') {
+		if v, err := strconv.Atoi(s); err == nil {
+			return v, nil
+		}
+	}
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		if s == "-" || s == "+" || s == "." {
+			return 0, nil
+		}
+		return nil, fmt.Errorf("pdf: malformed number %q at offset %d", s, start)
+	}
+	return v, nil
+}
+
+func (p *parser) readLiteralString() (Object, error) {
+	p.pos++ // '('
+	var out []byte
+	depth := 1
+	for p.pos < len(p.data) {
+		c := p.data[p.pos]
+		p.pos++
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return String(out), nil
+			}
+		case '\\':
+			if p.pos >= len(p.data) {
+				continue
+			}
+			e := p.data[p.pos]
+			p.pos++
+			switch e {
+			case 'n':
+				c = '\n'
+			case 'r':
+				c = '\r'
+			case 't':
+				c = '\t'
+			case 'b':
+				c = '\b'
+			case 'f':
+				c = '\f'
+			case '\r':
+				if p.pos < len(p.data) && p.data[p.pos] == '\n' {
+					p.pos++
+				}
+				continue
+			case '\n':
+				continue
+			default:
+				if e >= '0' && e <= '7' {
+					v := int(e - '0')
+					for i := 0; i < 2 && p.pos < len(p.data) && p.data[p.pos] >= '0' && p.data[p.pos] <= '7'; i++ {
+						v = v*8 + int(p.data[p.pos]-'0')
+						p.pos++
+					}
+					c = byte(v)
+				} else {
+					c = e
+				}
+			}
+		}
+		out = append(out, c)
+	}
+	return nil, fmt.Errorf("pdf: unterminated string")
+}
+
+func (p *parser) readHexString() (Object, error) {
+	p.pos++ // '<'
+	var out []byte
+	var hi byte
+	half := false
+	for p.pos < len(p.data) {
+		c := p.data[p.pos]
+		p.pos++
+		if c == '>' {
+			if half {
+				out = append(out, hi<<4)
+			}
+			return String(out), nil
+		}
+		var v byte
+		switch {
+		case c >= '0' && c <= '9':
+			v = c - '0'
+		case c >= 'a' && c <= 'f':
+			v = c - 'a' + 10
+		case c >= 'A' && c <= 'F':
+			v = c - 'A' + 10
+		default:
+			continue
+		}
+		if half {
+			out = append(out, hi<<4|v)
+		} else {
+			hi = v
+		}
+		half = !half
+	}
+	return nil, fmt.Errorf("pdf: unterminated hex string")
+}
+
+// object reads a complete object, resolving "n g R" references, arrays and
+// dictionaries. Streams are not handled here; see parseIndirect.
+func (p *parser) object() (Object, error) {
+	tok, err := p.token()
+	if err != nil {
+		return nil, err
+	}
+	return p.objectFrom(tok)
+}
+
+func (p *parser) objectFrom(tok Object) (Object, error) {
+	switch t := tok.(type) {
+	case keyword:
+		switch t {
+		case "<<":
+			return p.dict()
+		case "[":
+			return p.array()
+		}
+		return t, nil
+	case int:
+		// Look ahead for "gen R".
+		save := p.pos
+		if gen, err := p.token(); err == nil {
+			if g, ok := gen.(int); ok {
+				if r, err := p.token(); err == nil && r == keyword("R") {
+					return Ref{Num: t, Gen: g}, nil
+				}
+			}
+		}
+		p.pos = save
+		return t, nil
+	}
+	return tok, nil
+}
+
+func (p *parser) array() (Array, error) {
+	arr := Array{}
+	for {
+		tok, err := p.token()
+		if err != nil {
+			return nil, err
+		}
+		if tok == keyword("]") {
+			return arr, nil
+		}
+		o, err := p.objectFrom(tok)
+		if err != nil {
+			return nil, err
+		}
+		arr = append(arr, o)
+	}
+}
+
+func (p *parser) dict() (Dict, error) {
+	d := Dict{}
+	for {
+		tok, err := p.token()
+		if err != nil {
+			return nil, err
+		}
+		if tok == keyword(">>") {
+			return d, nil
+		}
+		key, ok := tok.(Name)
+		if !ok {
+			return nil, fmt.Errorf("pdf: dictionary key is %v, not a name", tok)
+		}
+		v, err := p.object()
+		if err != nil {
+			return nil, err
+		}
+		if _, isKeyword := v.(keyword); isKeyword {
+			return nil, fmt.Errorf("pdf: unexpected %v in dictionary", v)
+		}
+		d[key] = v
+	}
+}
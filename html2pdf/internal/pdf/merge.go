@@ -0,0 +1,126 @@
+package pdf
+
+// importer copies objects from one document into another, renumbering
+// references as it goes. Objects shared by several imported pages, such as
+// fonts, are copied once.
+type importer struct {
+	dst, src *Document
+	mapped   map[int]Ref
+	pages    map[int]Ref
+}
+
+// ImportPages copies pages (and everything they reference) from src into d
+// and returns references to the new pages. Inherited attributes are copied
+// onto each page, and references to pages of src that are not imported,
+// such as link destinations, become null.
+func (d *Document) ImportPages(src *Document, pages []Ref) []Ref {
+	imp := &importer{dst: d, src: src, mapped: map[int]Ref{}, pages: map[int]Ref{}}
+
+	// Reserve the new page numbers first so pages can reference each other.
+	out := make([]Ref, len(pages))
+	for i, p := range pages {
+		out[i] = d.Add(nil)
+		imp.pages[p.Num] = out[i]
+		imp.mapped[p.Num] = out[i]
+	}
+	for i, p := range pages {
+		page, _ := src.Resolve(p).(Dict)
+		copied := Dict{}
+		for k, v := range page {
+			if k == "Parent" {
+				continue
+			}
+			copied[k] = imp.copy(v)
+		}
+		for _, key := range inheritable {
+			if _, ok := copied[key]; !ok {
+				if v := src.PageAttr(p, key); v != nil {
+					copied[key] = imp.copy(v)
+				}
+			}
+		}
+		d.Set(out[i], copied)
+	}
+	return out
+}
+
+// copy returns o with all references rewritten to objects copied into dst.
+func (imp *importer) copy(o Object) Object {
+	switch v := o.(type) {
+	case Ref:
+		if ref, ok := imp.mapped[v.Num]; ok {
+			return ref
+		}
+		target := imp.src.Get(v)
+		if dict, ok := target.(Dict); ok && dict["Type"] == Name("Page") {
+			return nil
+		}
+		ref := imp.dst.Add(nil)
+		imp.mapped[v.Num] = ref
+		imp.dst.Set(ref, imp.copy(target))
+		return ref
+	case Array:
+		out := make(Array, len(v))
+		for i, item := range v {
+			out[i] = imp.copy(item)
+		}
+		return out
+	case Dict:
+		out := make(Dict, len(v))
+		for k, item := range v {
+			out[k] = imp.copy(item)
+		}
+		return out
+	case *Stream:
+		return &Stream{Dict: imp.copy(v.Dict).(Dict), Data: v.Data}
+	}
+	return o
+}
+
+// SetPages replaces the page tree with a flat tree holding pages in order.
+// Inherited attributes are copied onto each page before it is re-parented.
+func (d *Document) SetPages(pages []Ref) {
+	root, ok := d.Catalog()["Pages"].(Ref)
+	if !ok {
+		root = d.Add(nil)
+		d.Catalog()["Pages"] = root
+	}
+
+	for _, p := range pages {
+		page, ok := d.Resolve(p).(Dict)
+		if !ok {
+			continue
+		}
+		for _, key := range inheritable {
+			if _, ok := page[key]; !ok {
+				if v := d.PageAttr(p, key); v != nil {
+					page[key] = v
+				}
+			}
+		}
+	}
+
+	kids := make(Array, len(pages))
+	for i, p := range pages {
+		kids[i] = p
+		if page, ok := d.Resolve(p).(Dict); ok {
+			page["Parent"] = root
+		}
+	}
+	d.Set(root, Dict{"Type": Name("Pages"), "Kids": kids, "Count": len(pages)})
+}
+
+// Merge returns a new document holding the pages of docs in order.
+func Merge(docs ...*Document) (*Document, error) {
+	out := New()
+	var pages []Ref
+	for _, doc := range docs {
+		src, err := doc.Pages()
+		if err != nil {
+			return nil, err
+		}
+		pages = append(pages, out.ImportPages(doc, src)...)
+	}
+	out.SetPages(pages)
+	return out, nil
+}
@@ -0,0 +1,204 @@
+// Package pdf implements the small subset of the PDF file format needed to
+// post-process documents produced by Chrome: parsing objects, editing them,
+// and writing the result back out.
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Object is a PDF object: nil, bool, int, float64, String, Name, Array, Dict,
+// Ref or *Stream.
+type Object interface{}
+
+// Name is a PDF name object such as /Type.
+type Name string
+
+// String is a PDF string object.
+type String []byte
+
+// Array is a PDF array object.
+type Array []Object
+
+// Dict is a PDF dictionary object.
+type Dict map[Name]Object
+
+// Ref is an indirect reference to an object.
+type Ref struct {
+	Num int
+	Gen int
+}
+
+// Stream is a PDF stream object. Data holds the encoded stream content.
+type Stream struct {
+	Dict Dict
+	Data []byte
+}
+
+// writeObject serializes o in PDF syntax.
+func writeObject(buf *bytes.Buffer, o Object) {
+	switch v := o.(type) {
+	case nil:
+		buf.WriteString("null")
+	case bool:
+		if v {
+			buf.WriteString("true")
+		} else {
+			buf.WriteString("false")
+		}
+	case int:
+		buf.WriteString(strconv.Itoa(v))
+	case float64:
+		buf.WriteString(formatReal(v))
+	case String:
+		writeString(buf, v)
+	case Name:
+		writeName(buf, v)
+	case Array:
+		buf.WriteByte('[')
+		for i, item := range v {
+			if i > 0 {
+				buf.WriteByte(' ')
+			}
+			writeObject(buf, item)
+		}
+		buf.WriteByte(']')
+	case Dict:
+		writeDict(buf, v)
+	case Ref:
+		fmt.Fprintf(buf, "%d %d R", v.Num, v.Gen)
+	case *Stream:
+		dict := make(Dict, len(v.Dict)+1)
+		for k, val := range v.Dict {
+			dict[k] = val
+		}
+		dict["Length"] = len(v.Data)
+		writeDict(buf, dict)
+		buf.WriteString("\nstream\n")
+		buf.Write(v.Data)
+		buf.WriteString("\nendstream")
+	default:
+		panic(fmt.Sprintf("pdf: cannot write object of type %T", o))
+	}
+}
+
+// writeDict writes a dictionary with its keys sorted so output is deterministic.
+func writeDict(buf *bytes.Buffer, d Dict) {
+	keys := make([]string, 0, len(d))
+	for k := range d {
+		keys = append(keys, string(k))
+	}
+	sort.Strings(keys)
+
+	buf.WriteString("<<")
+	for _, k := range keys {
+		writeName(buf, Name(k))
+		buf.WriteByte(' ')
+		writeObject(buf, d[Name(k)])
+	}
+	buf.WriteString(">>")
+}
+
+// formatReal formats a real number without exponent notation, as PDF requires.
+func formatReal(f float64) string {
+	s := strconv.FormatFloat(f, 'f', 5, 64)
+	s = trimZeros(s)
+	if s == "-0" {
+		return "0"
+	}
+	return s
+}
+
+func trimZeros(s string) string {
+	if !strings.Contains(s, ".") {
+		return s
+	}
+	for len(s) > 0 && s[len(s)-1] == '0' {
+		s = s[:len(s)-1]
+	}
+	if len(s) > 0 && s[len(s)-1] == '.' {
+		s = s[:len(s)-1]
+	}
+	return s
+}
+
+func writeString(buf *bytes.Buffer, s String) {
+	buf.WriteByte('(')
+	for _, c := range s {
+		switch c {
+		case '(', ')', '\\':
+			buf.WriteByte('\\')
+			buf.WriteByte(c)
+		case '\n':
+			buf.WriteString(`\n`)
+		case '\r':
+			buf.WriteString(`\r`)
+		default:
+			if c < 0x20 || c > 0x7e {
+				fmt.Fprintf(buf, "\\%03o", c)
+			} else {
+				buf.WriteByte(c)
+			}
+		}
+	}
+	buf.WriteByte(')')
+}
+
+func writeName(buf *bytes.Buffer, n Name) {
+	buf.WriteByte('/')
+	for i := 0; i < len(n); i++ {
+		c := n[i]
+		if c < 0x21 || c > 0x7e || c == '#' || isDelimiter(c) {
+			fmt.Fprintf(buf, "#%02X", c)
+		} else {
+			buf.WriteByte(c)
+		}
+	}
+}
+
+// Number returns o as a float64 when it is an int or a real.
+func Number(o Object) (float64, bool) {
+	switch v := o.(type) {
+	case int:
+		return float64(v), true
+	case float64:
+		return v, true
+	}
+	return 0, false
+}
+
+// TextString encodes s as a PDF text string, using UTF-16BE when s is not ASCII.
+func TextString(s string) String {
+	ascii := true
+	for i := 0; i < len(s); i++ {
+		if s[i] > 0x7e || (s[i] < 0x20 && s[i] != '\n' && s[i] != '\r' && s[i] != '\t') {
+			ascii = false
+			break
+		}
+	}
+	if ascii {
+		return String(s)
+	}
+	out := []byte{0xfe, 0xff}
+	for _, r := range s {
+		if r > 0xffff {
+			r -= 0x10000
+			hi, lo := 0xd800+(r>>10), 0xdc00+(r&0x3ff)
+			out = append(out, byte(hi>>8), byte(hi), byte(lo>>8), byte(lo))
+			continue
+		}
+		out = append(out, byte(r>>8), byte(r))
+	}
+	return String(out)
+}
+
+// Serialize returns o in PDF syntax, e.g. for use in a content stream.
+func Serialize(o Object) []byte {
+	var buf bytes.Buffer
+	writeObject(&buf, o)
+	return buf.Bytes()
+}
@@ -0,0 +1,114 @@
+package pdf
+
+import "fmt"
+
+// inheritable lists the page attributes that may be inherited from the page tree.
+var inheritable = []Name{"Resources", "MediaBox", "CropBox", "Rotate"}
+
+// Pages returns references to the document's pages in order.
+func (d *Document) Pages() ([]Ref, error) {
+	catalog := d.Catalog()
+	if catalog == nil {
+		return nil, fmt.Errorf("%w: missing document catalog", ErrMalformed)
+	}
+	root, ok := catalog["Pages"].(Ref)
+	if !ok {
+		return nil, fmt.Errorf("%w: missing page tree", ErrMalformed)
+	}
+
+	var pages []Ref
+	visited := map[int]bool{}
+	var walk func(ref Ref) error
+	walk = func(ref Ref) error {
+		if visited[ref.Num] {
+			return fmt.Errorf("%w: page tree cycle", ErrMalformed)
+		}
+		visited[ref.Num] = true
+		node, ok := d.Resolve(ref).(Dict)
+		if !ok {
+			return fmt.Errorf("%w: page tree node %d is not a dictionary", ErrMalformed, ref.Num)
+		}
+		if node["Type"] == Name("Page") || node["Kids"] == nil {
+			pages = append(pages, ref)
+			return nil
+		}
+		kids, _ := d.Resolve(node["Kids"]).(Array)
+		for _, kid := range kids {
+			kidRef, ok := kid.(Ref)
+			if !ok {
+				continue
+			}
+			if err := walk(kidRef); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if err := walk(root); err != nil {
+		return nil, err
+	}
+	return pages, nil
+}
+
+// PageAttr returns the value of an attribute of page, following the page
+// tree for inherited attributes.
+func (d *Document) PageAttr(page Ref, key Name) Object {
+	node, _ := d.Resolve(page).(Dict)
+	for i := 0; node != nil && i < 64; i++ {
+		if v, ok := node[key]; ok {
+			return d.Resolve(v)
+		}
+		node, _ = d.Resolve(node["Parent"]).(Dict)
+	}
+	return nil
+}
+
+// Box is a rectangle in PDF user space units (points).
+type Box struct {
+	LLX, LLY, URX, URY float64
+}
+
+// Width returns the width of the box.
+func (b Box) Width() float64 { return b.URX - b.LLX }
+
+// Height returns the height of the box.
+func (b Box) Height() float64 { return b.URY - b.LLY }
+
+// Array returns the box as a PDF rectangle.
+func (b Box) Array() Array {
+	return Array{b.LLX, b.LLY, b.URX, b.URY}
+}
+
+// PageBox returns the named box (e.g. MediaBox) of page, defaulting to US
+// Letter when it is missing.
+func (d *Document) PageBox(page Ref, key Name) Box {
+	arr, _ := d.PageAttr(page, key).(Array)
+	if len(arr) != 4 {
+		if key != "MediaBox" {
+			return d.PageBox(page, "MediaBox")
+		}
+		return Box{0, 0, 612, 792}
+	}
+	var v [4]float64
+	for i, item := range arr {
+		v[i], _ = Number(d.Resolve(item))
+	}
+	b := Box{v[0], v[1], v[2], v[3]}
+	if b.LLX > b.URX {
+		b.LLX, b.URX = b.URX, b.LLX
+	}
+	if b.LLY > b.URY {
+		b.LLY, b.URY = b.URY, b.LLY
+	}
+	return b
+}
+
+// PageRotation returns the page rotation in degrees, normalized to 0, 90, 180 or 270.
+func (d *Document) PageRotation(page Ref) int {
+	r, _ := d.PageAttr(page, "Rotate").(int)
+	r %= 360
+	if r < 0 {
+		r += 360
+	}
+	return r
+}
@@ -0,0 +1,503 @@
+package pdf
+
+import (
+	"bytes"
+	"math"
+	"strings"
+	"unicode/utf16"
+)
+
+// maxFormDepth limits how deeply form XObjects are followed when extracting
+// text, so self-referencing forms cannot recurse forever.
+const maxFormDepth = 8
+
+// PageText returns the text shown on page, in content stream order. Lines
+// are separated by newlines, and gaps between words that are positioned
+// rather than drawn as space glyphs become spaces. Text in form XObjects is
+// included; text drawn as images or paths is not.
+func (d *Document) PageText(page Ref) (string, error) {
+	content, err := d.PageContent(page)
+	if err != nil {
+		return "", err
+	}
+	resources, _ := d.PageAttr(page, "Resources").(Dict)
+	e := &textExtractor{doc: d, fonts: map[Ref]*font{}}
+	if err := e.run(content, resources, Identity, 0); err != nil {
+		return "", err
+	}
+	return e.text.String(), nil
+}
+
+// textState is the part of the graphics state that affects text.
+type textState struct {
+	ctm       Matrix
+	font      *font
+	size      float64
+	charSpace float64
+	wordSpace float64
+	scale     float64
+	leading   float64
+	rise      float64
+}
+
+// textExtractor runs content streams and collects the text they show.
+type textExtractor struct {
+	doc   *Document
+	fonts map[Ref]*font
+	text  strings.Builder
+
+	// Device space position where the last glyph ended, and the size of
+	// its font there, for deciding on line breaks and spaces.
+	started    bool
+	endX, endY float64
+	lastSize   float64
+}
+
+func (e *textExtractor) run(content []byte, resources Dict, ctm Matrix, depth int) error {
+	state := textState{ctm: ctm, scale: 1}
+	var stack []textState
+	var tm, tlm Matrix
+	var operands []Object
+
+	p := &parser{data: content}
+	for {
+		p.skipSpace()
+		if p.pos >= len(p.data) {
+			return nil
+		}
+		tok, err := p.object()
+		if err != nil {
+			// Stop at content we cannot parse, keeping the text so far.
+			return nil
+		}
+		op, ok := tok.(keyword)
+		if !ok {
+			operands = append(operands, tok)
+			continue
+		}
+		nums := numbers(operands)
+		switch op {
+		case "q":
+			stack = append(stack, state)
+		case "Q":
+			if n := len(stack); n > 0 {
+				state, stack = stack[n-1], stack[:n-1]
+			}
+		case "cm":
+			if len(nums) == 6 {
+				state.ctm = Matrix(nums).Then(state.ctm)
+			}
+		case "BT":
+			tm, tlm = Identity, Identity
+		case "Tf":
+			if len(operands) == 2 {
+				name, _ := operands[0].(Name)
+				state.font = e.font(resources, name)
+				state.size, _ = Number(operands[1])
+			}
+		case "Tc":
+			if len(nums) == 1 {
+				state.charSpace = nums[0]
+			}
+		case "Tw":
+			if len(nums) == 1 {
+				state.wordSpace = nums[0]
+			}
+		case "Tz":
+			if len(nums) == 1 {
+				state.scale = nums[0] / 100
+			}
+		case "TL":
+			if len(nums) == 1 {
+				state.leading = nums[0]
+			}
+		case "Ts":
+			if len(nums) == 1 {
+				state.rise = nums[0]
+			}
+		case "Td", "TD":
+			if len(nums) == 2 {
+				if op == "TD" {
+					state.leading = -nums[1]
+				}
+				tlm = Translate(nums[0], nums[1]).Then(tlm)
+				tm = tlm
+			}
+		case "Tm":
+			if len(nums) == 6 {
+				tlm = Matrix(nums)
+				tm = tlm
+			}
+		case "T*":
+			tlm = Translate(0, -state.leading).Then(tlm)
+			tm = tlm
+		case "Tj", "'", "\"":
+			if op != "Tj" {
+				if op == "\"" && len(operands) == 3 {
+					state.wordSpace, _ = Number(operands[0])
+					state.charSpace, _ = Number(operands[1])
+				}
+				tlm = Translate(0, -state.leading).Then(tlm)
+				tm = tlm
+			}
+			if len(operands) > 0 {
+				if s, ok := operands[len(operands)-1].(String); ok {
+					tm = e.show(s, tm, &state)
+				}
+			}
+		case "TJ":
+			if len(operands) == 1 {
+				arr, _ := operands[0].(Array)
+				for _, item := range arr {
+					switch v := item.(type) {
+					case String:
+						tm = e.show(v, tm, &state)
+					default:
+						if n, ok := Number(v); ok {
+							tm = Translate(-n/1000*state.size*state.scale, 0).Then(tm)
+						}
+					}
+				}
+			}
+		case "Do":
+			if len(operands) == 1 && depth < maxFormDepth {
+				name, _ := operands[0].(Name)
+				if err := e.form(resources, name, state.ctm, depth); err != nil {
+					return err
+				}
+			}
+		case "ID":
+			// Skip inline image data, which is not PDF syntax.
+			end := bytes.Index(p.data[p.pos:], []byte("EI"))
+			for end >= 0 {
+				at := p.pos + end
+				if (at == 0 || isWhitespace(p.data[at-1])) && (at+2 == len(p.data) || isWhitespace(p.data[at+2])) {
+					break
+				}
+				next := bytes.Index(p.data[at+2:], []byte("EI"))
+				if next < 0 {
+					end = -1
+					break
+				}
+				end += 2 + next
+			}
+			if end < 0 {
+				return nil
+			}
+			p.pos += end + 2
+		}
+		operands = operands[:0]
+	}
+}
+
+// show appends the text of s drawn at tm and returns the text matrix after
+// it.
+func (e *textExtractor) show(s String, tm Matrix, state *textState) Matrix {
+	f := state.font
+	if f == nil {
+		f = fallbackFont
+	}
+	for _, code := range f.codes(s) {
+		trm := Translate(0, state.rise).Then(tm).Then(state.ctm)
+		x, y := trm.Apply(0, 0)
+		size := math.Hypot(trm[2], trm[3]) * state.size
+		e.place(x, y, size)
+		e.text.WriteString(f.unicode(code))
+
+		tx := f.width(code)*state.size + state.charSpace
+		if code.single && code.value == ' ' {
+			tx += state.wordSpace
+		}
+		tm = Translate(tx*state.scale, 0).Then(tm)
+		e.endX, e.endY = Translate(0, state.rise).Then(tm).Then(state.ctm).Apply(0, 0)
+		e.lastSize = size
+	}
+	return tm
+}
+
+// place separates a glyph at (x, y) from the previous one with a newline
+// when it starts a new line, or a space when there is a gap before it.
+func (e *textExtractor) place(x, y, size float64) {
+	if !e.started {
+		e.started = true
+		return
+	}
+	threshold := math.Max(size, e.lastSize) / 2
+	switch {
+	case math.Abs(y-e.endY) > threshold:
+		e.text.WriteByte('\n')
+	case math.Abs(x-e.endX) > threshold*0.3 && !e.endsWithSpace():
+		e.text.WriteByte(' ')
+	}
+}
+
+func (e *textExtractor) endsWithSpace() bool {
+	s := e.text.String()
+	return s == "" || s[len(s)-1] == ' ' || s[len(s)-1] == '\n'
+}
+
+// form runs the content of the form XObject name with its own resources.
+func (e *textExtractor) form(resources Dict, name Name, ctm Matrix, depth int) error {
+	xobjects, _ := e.doc.Resolve(resources["XObject"]).(Dict)
+	stream, ok := e.doc.Resolve(xobjects[name]).(*Stream)
+	if !ok || stream.Dict["Subtype"] != Name("Form") {
+		return nil
+	}
+	data, err := Decode(stream)
+	if err != nil {
+		return err
+	}
+	if m := numbers(arrayOf(e.doc.Resolve(stream.Dict["Matrix"]))); len(m) == 6 {
+		ctm = Matrix(m).Then(ctm)
+	}
+	if r, ok := e.doc.Resolve(stream.Dict["Resources"]).(Dict); ok {
+		resources = r
+	}
+	return e.run(data, resources, ctm, depth+1)
+}
+
+// font returns the decoder for the font resource name, caching it by
+// reference so that forms sharing a font parse it once.
+func (e *textExtractor) font(resources Dict, name Name) *font {
+	fonts, _ := e.doc.Resolve(resources["Font"]).(Dict)
+	obj, ok := fonts[name]
+	if !ok {
+		return nil
+	}
+	ref, isRef := obj.(Ref)
+	if f, ok := e.fonts[ref]; ok && isRef {
+		return f
+	}
+	f := e.doc.newFont(e.doc.Resolve(obj))
+	if isRef {
+		e.fonts[ref] = f
+	}
+	return f
+}
+
+// charCode is a character code read from a string shown with a font.
+type charCode struct {
+	value  int
+	single bool
+}
+
+// font decodes the strings shown with a font into character codes, their
+// Unicode text and their widths.
+type font struct {
+	codeLen    int
+	toUnicode  map[int]string
+	widths     map[int]float64
+	dflt       float64
+	widthScale float64
+}
+
+// fallbackFont is used for text shown without a usable font: codes are
+// single bytes read as Latin-1, half an em wide.
+var fallbackFont = &font{codeLen: 1, dflt: 500, widthScale: 0.001}
+
+func (d *Document) newFont(o Object) *font {
+	dict, ok := o.(Dict)
+	if !ok {
+		return fallbackFont
+	}
+	f := &font{codeLen: 1, widths: map[int]float64{}, widthScale: 0.001}
+	switch dict["Subtype"] {
+	case Name("Type0"):
+		f.codeLen = 2
+		f.dflt = 1000
+		descendants := arrayOf(d.Resolve(dict["DescendantFonts"]))
+		if len(descendants) > 0 {
+			if cid, ok := d.Resolve(descendants[0]).(Dict); ok {
+				if v, ok := Number(d.Resolve(cid["DW"])); ok {
+					f.dflt = v
+				}
+				f.cidWidths(d, arrayOf(d.Resolve(cid["W"])))
+			}
+		}
+	case Name("Type3"):
+		if m := numbers(arrayOf(d.Resolve(dict["FontMatrix"]))); len(m) == 6 {
+			f.widthScale = m[0]
+		}
+		fallthrough
+	default:
+		first, _ := Number(d.Resolve(dict["FirstChar"]))
+		for i, w := range arrayOf(d.Resolve(dict["Widths"])) {
+			if v, ok := Number(d.Resolve(w)); ok {
+				f.widths[int(first)+i] = v
+			}
+		}
+		if desc, ok := d.Resolve(dict["FontDescriptor"]).(Dict); ok {
+			f.dflt, _ = Number(d.Resolve(desc["MissingWidth"]))
+		}
+	}
+	if s, ok := d.Resolve(dict["ToUnicode"]).(*Stream); ok {
+		if data, err := Decode(s); err == nil {
+			f.parseCMap(data)
+		}
+	}
+	return f
+}
+
+// cidWidths reads a CIDFont W array: "c [w1 w2 ...]" gives widths for
+// consecutive codes from c, "c1 c2 w" one width for the range c1..c2.
+func (f *font) cidWidths(d *Document, w Array) {
+	for i := 0; i+1 < len(w); {
+		first, ok := Number(d.Resolve(w[i]))
+		if !ok {
+			return
+		}
+		if list, ok := d.Resolve(w[i+1]).(Array); ok {
+			for j, v := range list {
+				if n, ok := Number(d.Resolve(v)); ok {
+					f.widths[int(first)+j] = n
+				}
+			}
+			i += 2
+			continue
+		}
+		if i+2 >= len(w) {
+			return
+		}
+		last, ok1 := Number(d.Resolve(w[i+1]))
+		width, ok2 := Number(d.Resolve(w[i+2]))
+		if !ok1 || !ok2 || last-first > 0xffff {
+			return
+		}
+		for c := int(first); c <= int(last); c++ {
+			f.widths[c] = width
+		}
+		i += 3
+	}
+}
+
+// parseCMap reads the code length and the bfchar and bfrange mappings of a
+// ToUnicode CMap.
+func (f *font) parseCMap(data []byte) {
+	f.toUnicode = map[int]string{}
+	p := &parser{data: data}
+	var operands []Object
+	for {
+		p.skipSpace()
+		if p.pos >= len(p.data) {
+			return
+		}
+		tok, err := p.object()
+		if err != nil {
+			return
+		}
+		op, ok := tok.(keyword)
+		if !ok {
+			operands = append(operands, tok)
+			continue
+		}
+		switch op {
+		case "endcodespacerange":
+			if len(operands) > 0 {
+				if s, ok := operands[0].(String); ok && len(s) > 0 {
+					f.codeLen = len(s)
+				}
+			}
+		case "endbfchar":
+			for i := 0; i+1 < len(operands); i += 2 {
+				src, ok1 := operands[i].(String)
+				dst, ok2 := operands[i+1].(String)
+				if ok1 && ok2 {
+					f.toUnicode[codeValue(src)] = utf16String(dst)
+				}
+			}
+		case "endbfrange":
+			for i := 0; i+2 < len(operands); i += 3 {
+				lo, ok1 := operands[i].(String)
+				hi, ok2 := operands[i+1].(String)
+				if !ok1 || !ok2 || codeValue(hi)-codeValue(lo) > 0xffff {
+					continue
+				}
+				switch dst := operands[i+2].(type) {
+				case String:
+					base := []byte(dst)
+					for c := codeValue(lo); c <= codeValue(hi); c++ {
+						f.toUnicode[c] = utf16String(base)
+						if len(base) > 0 {
+							base = append([]byte(nil), base...)
+							base[len(base)-1]++
+						}
+					}
+				case Array:
+					for j, v := range dst {
+						if s, ok := v.(String); ok {
+							f.toUnicode[codeValue(lo)+j] = utf16String(s)
+						}
+					}
+				}
+			}
+		}
+		operands = operands[:0]
+	}
+}
+
+// codes splits s into character codes.
+func (f *font) codes(s String) []charCode {
+	codes := make([]charCode, 0, len(s)/f.codeLen)
+	for i := 0; i+f.codeLen <= len(s); i += f.codeLen {
+		codes = append(codes, charCode{value: codeValue(s[i : i+f.codeLen]), single: f.codeLen == 1})
+	}
+	return codes
+}
+
+// unicode returns the text of code, falling back to Latin-1 for single byte
+// codes without a mapping.
+func (f *font) unicode(code charCode) string {
+	if s, ok := f.toUnicode[code.value]; ok {
+		return s
+	}
+	if code.single {
+		return string(rune(code.value))
+	}
+	return ""
+}
+
+// width returns the advance of code in text space units for a font size
+// of 1.
+func (f *font) width(code charCode) float64 {
+	if w, ok := f.widths[code.value]; ok {
+		return w * f.widthScale
+	}
+	return f.dflt * f.widthScale
+}
+
+// codeValue reads b as a big-endian number.
+func codeValue(b []byte) int {
+	v := 0
+	for _, c := range b {
+		v = v<<8 | int(c)
+	}
+	return v
+}
+
+// utf16String decodes UTF-16BE text from a CMap destination.
+func utf16String(b []byte) string {
+	units := make([]uint16, 0, len(b)/2)
+	for i := 0; i+1 < len(b); i += 2 {
+		units = append(units, uint16(b[i])<<8|uint16(b[i+1]))
+	}
+	return string(utf16.Decode(units))
+}
+
+// numbers returns objs as numbers, or nil if any is not a number.
+func numbers(objs []Object) []float64 {
+	nums := make([]float64, 0, len(objs))
+	for _, o := range objs {
+		v, ok := Number(o)
+		if !ok {
+			return nil
+		}
+		nums = append(nums, v)
+	}
+	return nums
+}
+
+// arrayOf returns o as an array, or nil.
+func arrayOf(o Object) Array {
+	a, _ := o.(Array)
+	return a
+}
@@ -0,0 +1,135 @@
+package pdf
+
+import (
+	"bytes"
+	"math"
+)
+
+// Matrix is a PDF transformation matrix [a b c d e f], mapping (x, y) to
+// (a*x + c*y + e, b*x + d*y + f).
+type Matrix [6]float64
+
+// Identity is the identity transformation.
+var Identity = Matrix{1, 0, 0, 1, 0, 0}
+
+// Translate returns a translation by (tx, ty).
+func Translate(tx, ty float64) Matrix {
+	return Matrix{1, 0, 0, 1, tx, ty}
+}
+
+// Scale returns a scaling by (sx, sy).
+func Scale(sx, sy float64) Matrix {
+	return Matrix{sx, 0, 0, sy, 0, 0}
+}
+
+// RotateClockwise returns the transformation that turns a width x height
+// area clockwise by degrees (a multiple of 90), keeping the result in the
+// positive quadrant.
+func RotateClockwise(degrees int, width, height float64) Matrix {
+	switch ((degrees % 360) + 360) % 360 {
+	case 90:
+		return Matrix{0, -1, 1, 0, 0, width}
+	case 180:
+		return Matrix{-1, 0, 0, -1, width, height}
+	case 270:
+		return Matrix{0, 1, -1, 0, height, 0}
+	}
+	return Identity
+}
+
+// Then returns the transformation that applies m followed by n.
+func (m Matrix) Then(n Matrix) Matrix {
+	return Matrix{
+		m[0]*n[0] + m[1]*n[2],
+		m[0]*n[1] + m[1]*n[3],
+		m[2]*n[0] + m[3]*n[2],
+		m[2]*n[1] + m[3]*n[3],
+		m[4]*n[0] + m[5]*n[2] + n[4],
+		m[4]*n[1] + m[5]*n[3] + n[5],
+	}
+}
+
+// Apply transforms the point (x, y).
+func (m Matrix) Apply(x, y float64) (float64, float64) {
+	return m[0]*x + m[2]*y + m[4], m[1]*x + m[3]*y + m[5]
+}
+
+// ApplyBox transforms b and returns the bounding box of the result.
+func (m Matrix) ApplyBox(b Box) Box {
+	x1, y1 := m.Apply(b.LLX, b.LLY)
+	x2, y2 := m.Apply(b.URX, b.URY)
+	x3, y3 := m.Apply(b.LLX, b.URY)
+	x4, y4 := m.Apply(b.URX, b.LLY)
+	return Box{
+		LLX: math.Min(math.Min(x1, x2), math.Min(x3, x4)),
+		LLY: math.Min(math.Min(y1, y2), math.Min(y3, y4)),
+		URX: math.Max(math.Max(x1, x2), math.Max(x3, x4)),
+		URY: math.Max(math.Max(y1, y2), math.Max(y3, y4)),
+	}
+}
+
+// String returns the matrix as operands of the cm operator.
+func (m Matrix) String() string {
+	var buf bytes.Buffer
+	for i, v := range m {
+		if i > 0 {
+			buf.WriteByte(' ')
+		}
+		buf.WriteString(formatReal(v))
+	}
+	return buf.String()
+}
+
+// TransformPage maps the content and annotations of page through m and
+// gives the page the new media box. Any rotation and crop boxes are
+// dropped, since m is expected to account for them.
+func (d *Document) TransformPage(page Ref, m Matrix, mediaBox Box) {
+	dict, ok := d.Resolve(page).(Dict)
+	if !ok {
+		return
+	}
+	for _, key := range inheritable {
+		if _, ok := dict[key]; !ok {
+			if v := d.PageAttr(page, key); v != nil {
+				dict[key] = v
+			}
+		}
+	}
+
+	contents := Array{d.Add(Encode(nil, []byte("q "+m.String()+" cm\n")))}
+	switch c := dict["Contents"].(type) {
+	case Array:
+		contents = append(contents, c...)
+	case Ref:
+		if arr, ok := d.Resolve(c).(Array); ok {
+			contents = append(contents, arr...)
+		} else {
+			contents = append(contents, c)
+		}
+	}
+	contents = append(contents, d.Add(Encode(nil, []byte("\nQ"))))
+	dict["Contents"] = contents
+
+	dict["MediaBox"] = mediaBox.Array()
+	for _, key := range []Name{"CropBox", "BleedBox", "TrimBox", "ArtBox", "Rotate"} {
+		delete(dict, key)
+	}
+
+	annots, _ := d.Resolve(dict["Annots"]).(Array)
+	for _, a := range annots {
+		annot, ok := d.Resolve(a).(Dict)
+		if !ok {
+			continue
+		}
+		rect, ok := d.Resolve(annot["Rect"]).(Array)
+		if !ok || len(rect) != 4 {
+			continue
+		}
+		var v [4]float64
+		for i, item := range rect {
+			v[i], _ = Number(d.Resolve(item))
+		}
+		annot["Rect"] = m.ApplyBox(Box{v[0], v[1], v[2], v[3]}).Array()
+		delete(annot, "QuadPoints")
+	}
+}
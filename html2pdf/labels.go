@@ -0,0 +1,169 @@
+package html2pdf
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"html/template"
+	"strconv"
+)
+
+// ErrInvalidLabelSheet is returned when a label sheet has no labels,
+// non-positive dimensions, or a grid that does not fit on its paper.
+var ErrInvalidLabelSheet = fmt.Errorf("invalid label sheet")
+
+// LabelSheet describes the grid of labels on a sheet. All lengths are in inches.
+type LabelSheet struct {
+	Paper   PaperSize
+	Columns int
+	Rows    int
+	// LabelWidth and LabelHeight are the size of a single label.
+	LabelWidth  float64
+	LabelHeight float64
+	// TopMargin and LeftMargin position the top-left label on the sheet.
+	TopMargin  float64
+	LeftMargin float64
+	// HorizontalPitch and VerticalPitch are the distances between the
+	// top-left corners of neighbouring labels. Zero means the label size.
+	HorizontalPitch float64
+	VerticalPitch   float64
+}
+
+var (
+	// Avery5160 is a US Letter sheet of 30 address labels, 2 5/8 x 1 in.
+	Avery5160 = LabelSheet{
+		Paper: Letter, Columns: 3, Rows: 10,
+		LabelWidth: 2.625, LabelHeight: 1,
+		TopMargin: 0.5, LeftMargin: 0.1875,
+		HorizontalPitch: 2.75, VerticalPitch: 1,
+	}
+	// Avery5163 is a US Letter sheet of 10 shipping labels, 4 x 2 in.
+	Avery5163 = LabelSheet{
+		Paper: Letter, Columns: 2, Rows: 5,
+		LabelWidth: 4, LabelHeight: 2,
+		TopMargin: 0.5, LeftMargin: 0.15625,
+		HorizontalPitch: 4.1875, VerticalPitch: 2,
+	}
+	// AveryL7160 is an A4 sheet of 21 address labels, 63.5 x 38.1 mm.
+	AveryL7160 = LabelSheet{
+		Paper: A4, Columns: 3, Rows: 7,
+		LabelWidth: 63.5 / mmPerInch, LabelHeight: 38.1 / mmPerInch,
+		TopMargin: 15.15 / mmPerInch, LeftMargin: 7.25 / mmPerInch,
+		HorizontalPitch: 66 / mmPerInch, VerticalPitch: 38.1 / mmPerInch,
+	}
+)
+
+// labelTolerance absorbs rounding in sheet dimensions converted from
+// millimetres when checking that the grid fits on the paper.
+const labelTolerance = 1e-6
+
+// validate reports ErrInvalidLabelSheet when the sheet cannot hold a label
+// or its grid extends beyond the paper.
+func (s LabelSheet) validate() error {
+	if err := s.Paper.validate(); err != nil {
+		return err
+	}
+	if s.Columns < 1 || s.Rows < 1 || s.LabelWidth <= 0 || s.LabelHeight <= 0 ||
+		s.TopMargin < 0 || s.LeftMargin < 0 || s.HorizontalPitch < 0 || s.VerticalPitch < 0 {
+		return fmt.Errorf("%w: %+v", ErrInvalidLabelSheet, s)
+	}
+	hPitch, vPitch := s.pitch()
+	right := s.LeftMargin + float64(s.Columns-1)*hPitch + s.LabelWidth
+	bottom := s.TopMargin + float64(s.Rows-1)*vPitch + s.LabelHeight
+	if right > s.Paper.Width+labelTolerance || bottom > s.Paper.Height+labelTolerance {
+		return fmt.Errorf("%w: the grid is %sin x %sin, larger than the paper", ErrInvalidLabelSheet, formatNumber(right), formatNumber(bottom))
+	}
+	return nil
+}
+
+// pitch returns the distances between neighbouring labels.
+func (s LabelSheet) pitch() (horizontal, vertical float64) {
+	horizontal, vertical = s.HorizontalPitch, s.VerticalPitch
+	if horizontal == 0 {
+		horizontal = s.LabelWidth
+	}
+	if vertical == 0 {
+		vertical = s.LabelHeight
+	}
+	return horizontal, vertical
+}
+
+// ConvertLabelsToPdf renders the html/template fragment, which may use
+// TemplateFuncs, once per item of data and places the results on the label
+// grid of sheet, adding sheets as needed.
+// The paper size of the sheet and zero margins are applied before opts, so
+// opts can still override them.
+func ConvertLabelsToPdf(ctx context.Context, sheet LabelSheet, fragment string, data []interface{}, opts ...Option) ([]byte, error) {
+	options := newOptions(opts)
+	validator, err := newDataValidator(options.dataSchema)
+	if err != nil {
+		return nil, err
+	}
+	for i, item := range data {
+		if err := validator.validate(item); err != nil {
+			return nil, fmt.Errorf("label %d: %w", i+1, err)
+		}
+	}
+	html, err := labelsHTML(sheet, fragment, data, options.templateFuncs())
+	if err != nil {
+		return nil, err
+	}
+	return ConvertHtmlToPdf(ctx, html, labelOptions(sheet, opts)...)
+}
+
+// labelOptions returns the options labels are printed with: the paper size
+// of sheet and zero margins, followed by opts.
+func labelOptions(sheet LabelSheet, opts []Option) []Option {
+	return append([]Option{WithPaperSize(sheet.Paper), WithMargins(0, 0, 0, 0)}, opts...)
+}
+
+// labelsHTML builds a document with one absolutely positioned box per label.
+func labelsHTML(sheet LabelSheet, fragment string, data []interface{}, funcs template.FuncMap) (string, error) {
+	if err := sheet.validate(); err != nil {
+		return "", err
+	}
+	tmpl, err := template.New("label").Funcs(funcs).Parse(fragment)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse label template: %w", err)
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, `<!DOCTYPE html>
+<html><head><style>
+@page { size: %[1]sin %[2]sin; margin: 0; }
+html, body { margin: 0; padding: 0; }
+.sheet { position: relative; width: %[1]sin; height: %[2]sin; overflow: hidden; break-after: page; }
+.sheet:last-child { break-after: auto; }
+.label { position: absolute; width: %[3]sin; height: %[4]sin; overflow: hidden; box-sizing: border-box; }
+</style></head><body>
+`, formatNumber(sheet.Paper.Width), formatNumber(sheet.Paper.Height), formatNumber(sheet.LabelWidth), formatNumber(sheet.LabelHeight))
+
+	perSheet := sheet.Columns * sheet.Rows
+	hPitch, vPitch := sheet.pitch()
+	for i, item := range data {
+		if i%perSheet == 0 {
+			if i > 0 {
+				buf.WriteString("</div>\n")
+			}
+			buf.WriteString(`<div class="sheet">` + "\n")
+		}
+		slot := i % perSheet
+		left := sheet.LeftMargin + float64(slot%sheet.Columns)*hPitch
+		top := sheet.TopMargin + float64(slot/sheet.Columns)*vPitch
+		fmt.Fprintf(&buf, `<div class="label" style="left: %sin; top: %sin;">`, formatNumber(left), formatNumber(top))
+		if err := tmpl.Execute(&buf, item); err != nil {
+			return "", fmt.Errorf("failed to render label %d: %w", i+1, err)
+		}
+		buf.WriteString("</div>\n")
+	}
+	if len(data) == 0 {
+		buf.WriteString(`<div class="sheet">` + "\n")
+	}
+	buf.WriteString("</div>\n</body></html>\n")
+	return buf.String(), nil
+}
+
+// formatNumber formats v for CSS or PDF content, without exponent notation.
+func formatNumber(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
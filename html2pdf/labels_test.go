@@ -0,0 +1,109 @@
+package html2pdf
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestLabelsHTML(t *testing.T) {
+	sheet := LabelSheet{
+		Paper: Letter, Columns: 2, Rows: 2,
+		LabelWidth: 4, LabelHeight: 5,
+		TopMargin: 0.5, LeftMargin: 0.25,
+		HorizontalPitch: 4,
+	}
+	data := []interface{}{
+		map[string]string{"Name": "Ada"},
+		map[string]string{"Name": "Grace"},
+		map[string]string{"Name": "Linus"},
+		map[string]string{"Name": "Ken"},
+		map[string]string{"Name": "<Rob>"},
+	}
+
+	html, err := labelsHTML(sheet, `<p>{{.Name}}</p>`, data, TemplateFuncs())
+	if err != nil {
+		t.Fatalf("labelsHTML() error = %v", err)
+	}
+	if n := strings.Count(html, `<div class="sheet">`); n != 2 {
+		t.Errorf("Expected 2 sheets, got %d", n)
+	}
+	if n := strings.Count(html, `<div class="label"`); n != 5 {
+		t.Errorf("Expected 5 labels, got %d", n)
+	}
+	for _, want := range []string{
+		`style="left: 0.25in; top: 0.5in;"><p>Ada</p>`,
+		`style="left: 4.25in; top: 0.5in;"><p>Grace</p>`,
+		`style="left: 0.25in; top: 5.5in;"><p>Linus</p>`,
+		`<p>&lt;Rob&gt;</p>`,
+		`@page { size: 8.5in 11in; margin: 0; }`,
+	} {
+		if !strings.Contains(html, want) {
+			t.Errorf("Expected HTML to contain %q", want)
+		}
+	}
+}
+
+func TestLabelsHTMLErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		sheet    LabelSheet
+		fragment string
+		wantErr  error
+	}{
+		{name: "no columns", sheet: LabelSheet{Paper: A4, Rows: 1, LabelWidth: 1, LabelHeight: 1}, fragment: "x", wantErr: ErrInvalidLabelSheet},
+		{name: "no paper", sheet: LabelSheet{Columns: 1, Rows: 1, LabelWidth: 1, LabelHeight: 1}, fragment: "x", wantErr: ErrInvalidPaperSize},
+		{
+			name:     "grid wider than paper",
+			sheet:    LabelSheet{Paper: Letter, Columns: 3, Rows: 1, LabelWidth: 3, LabelHeight: 1},
+			fragment: "x",
+			wantErr:  ErrInvalidLabelSheet,
+		},
+		{
+			name:     "grid taller than paper",
+			sheet:    LabelSheet{Paper: Letter, Columns: 1, Rows: 12, LabelWidth: 1, LabelHeight: 1, TopMargin: 0.5},
+			fragment: "x",
+			wantErr:  ErrInvalidLabelSheet,
+		},
+		{name: "bad template", sheet: Avery5160, fragment: "{{.Name"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := labelsHTML(tt.sheet, tt.fragment, []interface{}{nil}, TemplateFuncs())
+			if err == nil {
+				t.Fatal("Expected an error")
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("Expected %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestLabelPresetsFit(t *testing.T) {
+	for name, sheet := range map[string]LabelSheet{"Avery5160": Avery5160, "Avery5163": Avery5163, "AveryL7160": AveryL7160} {
+		if err := sheet.validate(); err != nil {
+			t.Errorf("%s: validate() error = %v", name, err)
+		}
+	}
+}
+
+func TestLabelOptions(t *testing.T) {
+	params := newOptions(labelOptions(Avery5160, nil)).printToPDFParams()
+	if params.PaperWidth != Letter.Width || params.PaperHeight != Letter.Height || params.MarginTop != 0 || params.MarginLeft != 0 {
+		t.Errorf("Expected the sheet's paper without margins, got %+v", params)
+	}
+
+	params = newOptions(labelOptions(Avery5160, []Option{WithPaperSize(A4), WithMargins(0.1, 0.1, 0.1, 0.1)})).printToPDFParams()
+	if params.PaperWidth != A4.Width || params.PaperHeight != A4.Height || params.MarginTop != 0.1 {
+		t.Errorf("Expected the caller's paper size and margins to win, got %+v", params)
+	}
+}
+
+func TestLabelErrorNumbering(t *testing.T) {
+	_, err := labelsHTML(Avery5160, "{{index .Lines 5}}", []interface{}{map[string]interface{}{"Lines": []string{"a"}}}, TemplateFuncs())
+	if err == nil || !strings.Contains(err.Error(), "label 1:") {
+		t.Errorf("Expected the error to name label 1, got %v", err)
+	}
+}
@@ -0,0 +1,91 @@
+package html2pdf
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Lint rule names reported in Issue.Rule.
+const (
+	RuleViewportUnits  = "viewport-units"
+	RulePositionFixed  = "position-fixed"
+	RuleNoPageBreaks   = "no-page-break-rules"
+	RuleInsecureAssets = "insecure-asset"
+)
+
+var (
+	viewportUnitPattern  = regexp.MustCompile(`(?i)\b\d*\.?\d+(vh|vw|vmin|vmax)\b`)
+	positionFixedPattern = regexp.MustCompile(`(?i)position\s*:\s*fixed`)
+	pageBreakPattern     = regexp.MustCompile(`(?i)(page-)?break-(before|after|inside)\s*:`)
+	httpAssetPattern     = regexp.MustCompile(`(?i)<(?:img|script|link|iframe|source|video|audio|embed|object)\b[^>]*?\s(?:src|href|data|srcset)\s*=\s*["']?(http://[^"'\s>]*)`)
+	httpCSSURLPattern    = regexp.MustCompile(`(?i)(?:url\(\s*["']?|@import\s+["'])(http://[^"')\s]*)`)
+)
+
+// Issue is a print problem found by LintHtmlForPrint.
+type Issue struct {
+	// Rule identifies the check, e.g. RuleViewportUnits.
+	Rule string
+	// Line is the 1-based line of the problem, or 0 for the whole document.
+	Line int
+	// Message describes the problem and how to fix it.
+	Message string
+}
+
+// String formats the issue as "line N: message (rule)".
+func (i Issue) String() string {
+	if i.Line == 0 {
+		return fmt.Sprintf("%s (%s)", i.Message, i.Rule)
+	}
+	return fmt.Sprintf("line %d: %s (%s)", i.Line, i.Message, i.Rule)
+}
+
+// LintHtmlForPrint statically checks HTML for common print problems without
+// rendering it: viewport-relative units, repeated position:fixed elements,
+// missing page-break rules, and assets loaded over plain http://. Issues are
+// sorted by line.
+func LintHtmlForPrint(html string) []Issue {
+	var issues []Issue
+	lineAt := func(offset int) int {
+		return strings.Count(html[:offset], "\n") + 1
+	}
+
+	for _, m := range viewportUnitPattern.FindAllStringIndex(html, -1) {
+		issues = append(issues, Issue{
+			Rule:    RuleViewportUnits,
+			Line:    lineAt(m[0]),
+			Message: fmt.Sprintf("%s is relative to the browser viewport, not the printed page; use mm, in, or %%, or convert with WithViewportUnitFix", html[m[0]:m[1]]),
+		})
+	}
+
+	if fixed := positionFixedPattern.FindAllStringIndex(html, -1); len(fixed) > 1 {
+		for _, m := range fixed {
+			issues = append(issues, Issue{
+				Rule:    RulePositionFixed,
+				Line:    lineAt(m[0]),
+				Message: "position: fixed elements repeat on every printed page and cover content; prefer header and footer templates",
+			})
+		}
+	}
+
+	if !pageBreakPattern.MatchString(html) && !strings.Contains(html, "data-html2pdf-break") {
+		issues = append(issues, Issue{
+			Rule:    RuleNoPageBreaks,
+			Message: "no page-break rules found; add break-inside: avoid to table rows and figures, and break-after: avoid to headings",
+		})
+	}
+
+	for _, pattern := range []*regexp.Regexp{httpAssetPattern, httpCSSURLPattern} {
+		for _, m := range pattern.FindAllStringSubmatchIndex(html, -1) {
+			issues = append(issues, Issue{
+				Rule:    RuleInsecureAssets,
+				Line:    lineAt(m[0]),
+				Message: fmt.Sprintf("asset %s is loaded over plain http; use https or bundle it", html[m[2]:m[3]]),
+			})
+		}
+	}
+
+	sort.SliceStable(issues, func(i, j int) bool { return issues[i].Line < issues[j].Line })
+	return issues
+}
@@ -0,0 +1,64 @@
+package html2pdf
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestLintHtmlForPrint(t *testing.T) {
+	tests := []struct {
+		name string
+		html string
+		want []string // rule@line
+	}{
+		{
+			name: "clean",
+			html: `<style>tr { break-inside: avoid; } .hero { height: 50mm; }</style>
+<img src="https://cdn.example.com/logo.png"><a href="http://example.com">link</a>`,
+		},
+		{
+			name: "viewport units",
+			html: "<style>tr { break-inside: avoid; }\n.hero { height: 100vh; width: 50.5vw; }</style>",
+			want: []string{"viewport-units@2", "viewport-units@2"},
+		},
+		{
+			name: "single fixed element is fine",
+			html: `<div style="position: fixed; top: 0" data-html2pdf-break></div>`,
+		},
+		{
+			name: "repeated fixed elements",
+			html: "<style>h1 { page-break-after: avoid; }\n.a { position:fixed }\n.b { POSITION: fixed }</style>",
+			want: []string{"position-fixed@2", "position-fixed@3"},
+		},
+		{
+			name: "no page breaks",
+			html: `<table><tr><td>1</td></tr></table>`,
+			want: []string{"no-page-break-rules@0"},
+		},
+		{
+			name: "http assets",
+			html: "<style>tr { break-inside: avoid; } @import 'http://fonts.example.com/a.css';</style>\n<link rel=\"stylesheet\" href=\"http://example.com/print.css\">\n<div style=\"background: url(http://example.com/bg.png)\"></div>",
+			want: []string{"insecure-asset@1", "insecure-asset@2", "insecure-asset@3"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, issue := range LintHtmlForPrint(tt.html) {
+				got = append(got, fmt.Sprintf("%s@%d", issue.Rule, issue.Line))
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Expected issues %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestIssueString(t *testing.T) {
+	issue := Issue{Rule: RuleViewportUnits, Line: 3, Message: "100vh is relative"}
+	if got, want := issue.String(), "line 3: 100vh is relative (viewport-units)"; got != want {
+		t.Errorf("Expected %q, got %q", want, got)
+	}
+}
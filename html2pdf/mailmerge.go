@@ -0,0 +1,142 @@
+package html2pdf
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"html/template"
+	texttemplate "text/template"
+)
+
+const (
+	// defaultMailMergeName names mail-merge outputs when WithMailMergeName is not used.
+	defaultMailMergeName = "document-{{.Index}}.pdf"
+	// defaultMailMergeCombinedName names a combined mail-merge document.
+	defaultMailMergeCombinedName = "mail-merge.pdf"
+)
+
+// MailMergeDocument is one output of MailMerge.
+type MailMergeDocument struct {
+	Name string
+	PDF  []byte
+}
+
+// mailMergeNameData is passed to the mail-merge name template.
+type mailMergeNameData struct {
+	// Index is the 1-based row number, or 0 for a combined document.
+	Index int
+	Row   map[string]interface{}
+}
+
+// WithMailMergeName sets the text/template used to name MailMerge outputs.
+// It is executed with .Index, the 1-based row number, and .Row, the row
+// itself, e.g. "letter-{{.Row.customer_id}}.pdf". For a combined document
+// .Index is 0 and .Row is nil.
+func WithMailMergeName(tmpl string) Option {
+	return func(o *options) {
+		o.mailMergeName = tmpl
+	}
+}
+
+// WithMailMergeCombined makes MailMerge return a single document with the
+// pages of every row in order instead of one document per row.
+func WithMailMergeCombined(enabled bool) Option {
+	return func(o *options) {
+		o.mailMergeCombined = enabled
+	}
+}
+
+// MailMerge executes tmpl once per row and converts each result to PDF, e.g.
+// to generate personalized letters. By default it returns one document per
+// row; see WithMailMergeCombined and WithMailMergeName.
+func MailMerge(ctx context.Context, tmpl *template.Template, rows []map[string]interface{}, opts ...Option) ([]MailMergeDocument, error) {
+	options := newOptions(opts)
+	nameTmpl := options.mailMergeName
+	switch {
+	case nameTmpl != "":
+	case options.mailMergeCombined:
+		nameTmpl = defaultMailMergeCombinedName
+	default:
+		nameTmpl = defaultMailMergeName
+	}
+	names, err := mailMergeNames(nameTmpl, rows, options.mailMergeCombined)
+	if err != nil {
+		return nil, err
+	}
+	validator, err := newDataValidator(options.dataSchema)
+	if err != nil {
+		return nil, err
+	}
+	for i, row := range rows {
+		if err := validator.validate(row); err != nil {
+			return nil, fmt.Errorf("row %d: %w", i+1, err)
+		}
+	}
+
+	docs := make([][]byte, 0, len(rows))
+	for i, row := range rows {
+		html, err := executeTemplate(tmpl, row, options)
+		if err != nil {
+			return nil, fmt.Errorf("failed to render row %d: %w", i+1, err)
+		}
+		buf, err := ConvertHtmlToPdf(ctx, html, opts...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert row %d: %w", i+1, err)
+		}
+		docs = append(docs, buf)
+	}
+
+	if options.mailMergeCombined {
+		if len(docs) == 0 {
+			return nil, nil
+		}
+		merged, err := MergePdf(docs)
+		if err != nil {
+			return nil, fmt.Errorf("failed to combine documents: %w", err)
+		}
+		return []MailMergeDocument{{Name: names[0], PDF: merged}}, nil
+	}
+	out := make([]MailMergeDocument, len(docs))
+	for i, buf := range docs {
+		out[i] = MailMergeDocument{Name: names[i], PDF: buf}
+	}
+	return out, nil
+}
+
+// mailMergeNames executes the name template for each row, or once for a
+// combined document, and rejects duplicate names.
+func mailMergeNames(nameTmpl string, rows []map[string]interface{}, combined bool) ([]string, error) {
+	t, err := texttemplate.New("name").Option("missingkey=error").Parse(nameTmpl)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse name template: %w", err)
+	}
+	execute := func(data mailMergeNameData) (string, error) {
+		var buf bytes.Buffer
+		if err := t.Execute(&buf, data); err != nil {
+			return "", err
+		}
+		return buf.String(), nil
+	}
+
+	if combined {
+		name, err := execute(mailMergeNameData{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to name combined document: %w", err)
+		}
+		return []string{name}, nil
+	}
+	names := make([]string, len(rows))
+	seen := make(map[string]int, len(rows))
+	for i, row := range rows {
+		name, err := execute(mailMergeNameData{Index: i + 1, Row: row})
+		if err != nil {
+			return nil, fmt.Errorf("failed to name row %d: %w", i+1, err)
+		}
+		if prev, ok := seen[name]; ok {
+			return nil, fmt.Errorf("rows %d and %d are both named %q", prev, i+1, name)
+		}
+		seen[name] = i + 1
+		names[i] = name
+	}
+	return names, nil
+}
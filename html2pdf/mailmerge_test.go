@@ -0,0 +1,57 @@
+package html2pdf
+
+import (
+	"context"
+	"html/template"
+	"reflect"
+	"testing"
+)
+
+func TestMailMergeNames(t *testing.T) {
+	rows := []map[string]interface{}{
+		{"id": "A-1", "name": "Ada"},
+		{"id": "B-2", "name": "Grace"},
+	}
+
+	tests := []struct {
+		name     string
+		tmpl     string
+		rows     []map[string]interface{}
+		combined bool
+		want     []string
+		wantErr  bool
+	}{
+		{name: "default", tmpl: defaultMailMergeName, rows: rows, want: []string{"document-1.pdf", "document-2.pdf"}},
+		{name: "row fields", tmpl: "letter-{{.Row.id}}.pdf", rows: rows, want: []string{"letter-A-1.pdf", "letter-B-2.pdf"}},
+		{name: "combined", tmpl: "letters.pdf", rows: rows, combined: true, want: []string{"letters.pdf"}},
+		{name: "duplicate", tmpl: "letter.pdf", rows: rows, wantErr: true},
+		{name: "missing key", tmpl: "{{.Row.missing}}.pdf", rows: rows, wantErr: true},
+		{name: "invalid template", tmpl: "{{.Index", rows: rows, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := mailMergeNames(tt.tmpl, tt.rows, tt.combined)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("Expected an error, got names %v", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("mailMergeNames() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestMailMergeTemplateError(t *testing.T) {
+	tmpl := template.Must(template.New("letter").Option("missingkey=error").Parse(`<p>Dear {{.name}}</p>`))
+	_, err := MailMerge(context.Background(), tmpl, []map[string]interface{}{{"id": 1}})
+	if err == nil {
+		t.Error("Expected an error for a row missing a template field")
+	}
+}
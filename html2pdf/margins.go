@@ -0,0 +1,17 @@
+package html2pdf
+
+// Margins are page margins in inches.
+type Margins struct {
+	Top    float64
+	Bottom float64
+	Left   float64
+	Right  float64
+}
+
+// WithMargins sets the page margins in inches. Without it pages are printed
+// without margins, edge to edge.
+func WithMargins(top, bottom, left, right float64) Option {
+	return func(o *options) {
+		o.margins = Margins{Top: top, Bottom: bottom, Left: left, Right: right}
+	}
+}
@@ -0,0 +1,28 @@
+package html2pdf
+
+import "testing"
+
+func TestWithMargins(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []Option
+		want Margins
+	}{
+		{name: "default", want: Margins{}},
+		{name: "custom", opts: []Option{WithMargins(1, 0.5, 0.75, 0.25)}, want: Margins{Top: 1, Bottom: 0.5, Left: 0.75, Right: 0.25}},
+		{name: "edge to edge", opts: []Option{WithMargins(0, 0, 0, 0)}, want: Margins{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params := newOptions(tt.opts).printToPDFParams()
+			got := Margins{Top: params.MarginTop, Bottom: params.MarginBottom, Left: params.MarginLeft, Right: params.MarginRight}
+			if got != tt.want {
+				t.Errorf("Expected margins %+v, got %+v", tt.want, got)
+			}
+		})
+	}
+
+	if newOptions([]Option{WithMargins(1, 1, 1, 1)}).fingerprint() == getDefaultOptions().fingerprint() {
+		t.Error("Expected margins to change the options fingerprint")
+	}
+}
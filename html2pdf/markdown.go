@@ -0,0 +1,83 @@
+package html2pdf
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/parser"
+)
+
+// DefaultMarkdownTheme is the style sheet ConvertMarkdownToPdf uses unless
+// WithMarkdownTheme replaces it.
+const DefaultMarkdownTheme = `body {
+	font-family: -apple-system, "Segoe UI", Helvetica, Arial, sans-serif;
+	font-size: 11pt;
+	line-height: 1.5;
+	color: #1f2328;
+}
+h1, h2 { border-bottom: 1px solid #d1d9e0; padding-bottom: 0.3em; }
+h1, h2, h3, h4, h5, h6 { margin: 1.2em 0 0.6em; line-height: 1.25; break-after: avoid; }
+a { color: #0969da; text-decoration: none; }
+code, pre { font-family: ui-monospace, Menlo, Consolas, monospace; font-size: 0.9em; }
+code { background: #eff1f3; border-radius: 4px; padding: 0.1em 0.3em; }
+pre { background: #f6f8fa; border-radius: 6px; padding: 0.8em 1em; white-space: pre-wrap; break-inside: avoid; }
+pre code { background: none; padding: 0; }
+blockquote { margin: 0; padding: 0 1em; color: #59636e; border-left: 0.25em solid #d1d9e0; }
+table { border-collapse: collapse; }
+th, td { border: 1px solid #d1d9e0; padding: 0.3em 0.8em; }
+tr { break-inside: avoid; }
+img { max-width: 100%; }
+hr { border: 0; border-top: 1px solid #d1d9e0; }`
+
+// markdown converts GitHub Flavored Markdown to HTML. Raw HTML in the input
+// is dropped.
+var markdown = goldmark.New(
+	goldmark.WithExtensions(extension.GFM),
+	goldmark.WithParserOptions(parser.WithAutoHeadingID()),
+)
+
+// WithMarkdownTheme replaces the style sheet of ConvertMarkdownToPdf, e.g. to
+// apply a corporate font and colors. An empty theme leaves the document
+// unstyled.
+func WithMarkdownTheme(css string) Option {
+	return func(o *options) {
+		o.markdownTheme = &css
+	}
+}
+
+// ConvertMarkdownToPdf converts GitHub Flavored Markdown, such as release
+// notes, to HTML styled with DefaultMarkdownTheme and prints it to PDF.
+// Raw HTML in md is dropped. Relative image paths resolve with WithBaseURL
+// or WithAssetDir.
+func ConvertMarkdownToPdf(ctx context.Context, md string, opts ...Option) ([]byte, error) {
+	doc, err := markdownHTML(md, newOptions(opts))
+	if err != nil {
+		return nil, err
+	}
+	return ConvertHtmlToPdf(ctx, doc, opts...)
+}
+
+// markdownHTML renders md into a complete HTML document with the theme of options.
+func markdownHTML(md string, options *options) (string, error) {
+	var body bytes.Buffer
+	if err := markdown.Convert([]byte(md), &body); err != nil {
+		return "", fmt.Errorf("failed to convert markdown: %w", err)
+	}
+	theme := DefaultMarkdownTheme
+	if options.markdownTheme != nil {
+		theme = *options.markdownTheme
+	}
+	var doc bytes.Buffer
+	doc.WriteString(`<!DOCTYPE html>
+<html><head><meta charset="utf-8">`)
+	if theme != "" {
+		fmt.Fprintf(&doc, "<style>\n%s\n</style>", theme)
+	}
+	doc.WriteString("</head><body>\n")
+	doc.Write(body.Bytes())
+	doc.WriteString("</body></html>\n")
+	return doc.String(), nil
+}
@@ -0,0 +1,77 @@
+package html2pdf
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMarkdownHTML(t *testing.T) {
+	md := "# Release 1.2\n\n- [x] Faster **exports**\n- ~~Old API~~\n\n| Change | Issue |\n|---|---|\n| Fix | #12 |\n\n<script>alert(1)</script>\n"
+
+	tests := []struct {
+		name        string
+		opts        []Option
+		contains    []string
+		notContains []string
+	}{
+		{
+			name: "default theme",
+			contains: []string{
+				`<meta charset="utf-8">`,
+				"<style>\n" + DefaultMarkdownTheme,
+				`<h1 id="release-12">Release 1.2</h1>`,
+				`<input checked="" disabled="" type="checkbox"`,
+				"<strong>exports</strong>",
+				"<del>Old API</del>",
+				"<table>",
+			},
+			notContains: []string{"<script>"},
+		},
+		{
+			name:        "custom theme",
+			opts:        []Option{WithMarkdownTheme("body { font-family: Sarabun; }")},
+			contains:    []string{"<style>\nbody { font-family: Sarabun; }\n</style>"},
+			notContains: []string{DefaultMarkdownTheme},
+		},
+		{
+			name:        "no theme",
+			opts:        []Option{WithMarkdownTheme("")},
+			notContains: []string{"<style>"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := markdownHTML(md, newOptions(tt.opts))
+			if err != nil {
+				t.Fatalf("markdownHTML() error = %v", err)
+			}
+			for _, s := range tt.contains {
+				if !strings.Contains(got, s) {
+					t.Errorf("Expected %q in %s", s, got)
+				}
+			}
+			for _, s := range tt.notContains {
+				if strings.Contains(got, s) {
+					t.Errorf("Expected no %q in %s", s, got)
+				}
+			}
+		})
+	}
+}
+
+func TestConvertMarkdownToPdf(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	pdfBytes, err := ConvertMarkdownToPdf(ctx, "# Release notes\n\nBug fixes and improvements.")
+	if err != nil {
+		t.Fatalf("ConvertMarkdownToPdf() error = %v", err)
+	}
+	if !bytes.HasPrefix(pdfBytes, []byte("%PDF-")) {
+		t.Error("Expected a PDF document")
+	}
+}
@@ -0,0 +1,106 @@
+package html2pdf
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/patipolchat/html2pdf/html2pdf/internal/pdf"
+)
+
+// WithPageCallback registers a function that MergePdf calls for every page
+// as it is added to the merged document, e.g. to build a table of contents.
+// docIndex is the index of the source document; pageInDoc and absolutePage
+// are 1-based page numbers in the source and merged documents.
+func WithPageCallback(fn func(docIndex, pageInDoc, absolutePage int)) Option {
+	return func(o *options) {
+		o.pageCallback = fn
+	}
+}
+
+// MergePdf concatenates the pages of docs into a single PDF. Of the options,
+// only WithPageCallback and WithChecksumMetadata apply.
+func MergePdf(docs [][]byte, opts ...Option) ([]byte, error) {
+	options := newOptions(opts)
+
+	out := pdf.New()
+	var pages []pdf.Ref
+	for i, b := range docs {
+		doc, err := pdf.Parse(b)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse document %d: %w", i, err)
+		}
+		src, err := doc.Pages()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read pages of document %d: %w", i, err)
+		}
+		for j := range src {
+			if options.pageCallback != nil {
+				options.pageCallback(i, j+1, len(pages)+j+1)
+			}
+		}
+		pages = append(pages, out.ImportPages(doc, src)...)
+	}
+	out.SetPages(pages)
+
+	buf := out.Bytes()
+	if options.checksumMetadata {
+		return addChecksumMetadata(buf)
+	}
+	return buf, nil
+}
+
+// ConvertHtmlDocsToPdf converts each HTML document, e.g. the chapters of a
+// report, and concatenates their pages into one PDF in order. Every
+// document starts on a new page. The options apply to each conversion,
+// except those describing the whole document: the cover and the prepended
+// PDFs are added once before the first document, the appended PDFs once
+// after the last, and WithPageCallback, WithClassification,
+// WithRetentionPolicy and WithChecksumMetadata apply to the merged result.
+func ConvertHtmlDocsToPdf(ctx context.Context, htmlDocs []string, opts ...Option) ([]byte, error) {
+	if len(htmlDocs) == 0 {
+		return nil, fmt.Errorf("no documents to convert")
+	}
+	docs := make([][]byte, len(htmlDocs))
+	for i, html := range htmlDocs {
+		buf, err := ConvertHtmlToPdf(ctx, html, chapterOptions(opts, i, len(htmlDocs))...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert document %d: %w", i+1, err)
+		}
+		docs[i] = buf
+	}
+
+	options := newOptions(opts)
+	buf, err := MergePdf(docs, WithPageCallback(options.pageCallback))
+	if err != nil {
+		return nil, err
+	}
+	merged := getDefaultOptions()
+	merged.classification, merged.classificationPosition = options.classification, options.classificationPosition
+	merged.retentionExpiry, merged.retentionPolicyID = options.retentionExpiry, options.retentionPolicyID
+	merged.checksumMetadata = options.checksumMetadata
+	if buf, err = postProcess(buf, merged); err != nil {
+		return nil, fmt.Errorf("failed to post-process PDF: %w", err)
+	}
+	return buf, nil
+}
+
+// chapterOptions returns the options document i of n is converted with by
+// ConvertHtmlDocsToPdf: opts without those applying to the merged result.
+// Only the first document keeps the cover and the prepended PDFs, and only
+// the last the appended PDFs.
+func chapterOptions(opts []Option, i, n int) []Option {
+	return append(opts[:len(opts):len(opts)], func(o *options) {
+		if i > 0 {
+			o.coverHTML = ""
+			o.prependPDFs = nil
+		}
+		if i < n-1 {
+			o.appendPDFs = nil
+		}
+		o.pageCallback = nil
+		o.classification, o.classificationPosition = "", 0
+		o.retentionExpiry, o.retentionPolicyID = time.Time{}, ""
+		o.checksumMetadata = false
+	})
+}
@@ -0,0 +1,142 @@
+package html2pdf
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/patipolchat/html2pdf/html2pdf/internal/pdf"
+)
+
+func TestMergePdf(t *testing.T) {
+	type call struct{ doc, page, abs int }
+	var calls []call
+	out, err := MergePdf([][]byte{testPDF(t, 2), testPDF(t, 1), testPDF(t, 3)},
+		WithPageCallback(func(docIndex, pageInDoc, absolutePage int) {
+			calls = append(calls, call{docIndex, pageInDoc, absolutePage})
+		}))
+	if err != nil {
+		t.Fatalf("MergePdf() error = %v", err)
+	}
+
+	doc, err := pdf.Parse(out)
+	if err != nil {
+		t.Fatalf("Failed to parse merged PDF: %v", err)
+	}
+	if pages, _ := doc.Pages(); len(pages) != 6 {
+		t.Errorf("Expected 6 pages, got %d", len(pages))
+	}
+	want := []call{{0, 1, 1}, {0, 2, 2}, {1, 1, 3}, {2, 1, 4}, {2, 2, 5}, {2, 3, 6}}
+	if !reflect.DeepEqual(calls, want) {
+		t.Errorf("Expected callbacks %v, got %v", want, calls)
+	}
+}
+
+func TestMergePdfInvalidInput(t *testing.T) {
+	if _, err := MergePdf([][]byte{testPDF(t, 1), []byte("not a pdf")}); err == nil {
+		t.Error("Expected an error for invalid input")
+	}
+}
+
+func TestConvertHtmlDocsToPdf(t *testing.T) {
+	dir := t.TempDir()
+	chapters := []string{"<h1>Introduction</h1>", "<h1>Results</h1>"}
+	for i, html := range chapters {
+		opts := newOptions([]Option{WithFixtures(dir, FixtureRecord)})
+		if err := recordFixture(document{html: html}, opts, newResult(testPDF(t, i+1))); err != nil {
+			t.Fatalf("recordFixture() error = %v", err)
+		}
+	}
+
+	ctx := context.Background()
+	out, err := ConvertHtmlDocsToPdf(ctx, chapters, WithFixtures(dir, FixtureReplay))
+	if err != nil {
+		t.Fatalf("ConvertHtmlDocsToPdf() error = %v", err)
+	}
+	doc, err := pdf.Parse(out)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if pages, _ := doc.Pages(); len(pages) != 3 {
+		t.Errorf("Expected 3 pages, got %d", len(pages))
+	}
+
+	if _, err := ConvertHtmlDocsToPdf(ctx, append(chapters, "<h1>Appendix</h1>"), WithFixtures(dir, FixtureReplay)); !errors.Is(err, ErrFixtureNotFound) {
+		t.Errorf("Expected ErrFixtureNotFound, got %v", err)
+	}
+	if _, err := ConvertHtmlDocsToPdf(ctx, nil); err == nil {
+		t.Error("Expected an error without documents")
+	}
+}
+
+func TestConvertHtmlDocsToPdfDocumentOptions(t *testing.T) {
+	dir := t.TempDir()
+	chapters := []string{"<h1>Introduction</h1>", "<h1>Results</h1>"}
+	opts := []Option{
+		WithFixtures(dir, FixtureReplay),
+		WithCoverHTML("<h1>Annual report</h1>"),
+		WithPrependPDF(testPDF(t, 1)),
+		WithAppendPDF(testPDF(t, 1)),
+		WithChecksumMetadata(true),
+	}
+	// The first chapter is recorded with the cover and the prepended page,
+	// the last with the appended page.
+	for i, html := range chapters {
+		recorded := newOptions(append(chapterOptions(opts, i, len(chapters)), WithFixtures(dir, FixtureRecord)))
+		if err := recordFixture(document{html: html}, recorded, newResult(testPDF(t, 2))); err != nil {
+			t.Fatalf("recordFixture() error = %v", err)
+		}
+	}
+
+	out, err := ConvertHtmlDocsToPdf(context.Background(), chapters, opts...)
+	if err != nil {
+		t.Fatalf("ConvertHtmlDocsToPdf() error = %v", err)
+	}
+	doc, err := pdf.Parse(out)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if pages, _ := doc.Pages(); len(pages) != 4 {
+		t.Errorf("Expected 4 pages, got %d", len(pages))
+	}
+	if _, ok := doc.Info()[checksumMetadataKey].(pdf.String); !ok {
+		t.Error("Expected checksum metadata on the merged document")
+	}
+}
+
+func TestChapterOptions(t *testing.T) {
+	opts := []Option{
+		WithCoverHTML("<h1>Cover</h1>"),
+		WithPrependPDF([]byte("%PDF-pre")),
+		WithAppendPDF([]byte("%PDF-post")),
+		WithClassification(ClassificationConfidential, BannerTop),
+		WithRetentionPolicy(time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC), "finance-7y"),
+		WithChecksumMetadata(true),
+		WithPageCallback(func(int, int, int) {}),
+		WithLandscape(true),
+	}
+	covers := 0
+	for i := 0; i < 3; i++ {
+		o := newOptions(chapterOptions(opts, i, 3))
+		if o.coverHTML != "" {
+			covers++
+		}
+		if got := len(o.prependPDFs) > 0; got != (i == 0) {
+			t.Errorf("Chapter %d: prepended PDFs = %v", i, got)
+		}
+		if got := len(o.appendPDFs) > 0; got != (i == 2) {
+			t.Errorf("Chapter %d: appended PDFs = %v", i, got)
+		}
+		if o.classification != "" || o.retentionPolicyID != "" || o.checksumMetadata || o.pageCallback != nil {
+			t.Errorf("Chapter %d kept document-level options", i)
+		}
+		if !o.landscape {
+			t.Errorf("Chapter %d lost its render options", i)
+		}
+	}
+	if covers != 1 {
+		t.Errorf("Expected the cover on exactly one chapter, got %d", covers)
+	}
+}
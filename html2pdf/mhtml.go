@@ -0,0 +1,60 @@
+package html2pdf
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"mime"
+	"net/mail"
+	"net/url"
+	"os"
+	"path/filepath"
+)
+
+// ErrInvalidMHTML is returned when the input to ConvertMHTMLToPdf is not a
+// multipart/related MHTML archive.
+var ErrInvalidMHTML = fmt.Errorf("invalid mhtml archive")
+
+// ConvertMHTMLToPdf converts an MHTML archive, such as a page saved with
+// Chrome's "Webpage, Single File", with the images and style sheets it
+// embeds. Chrome only opens MHTML from local files, so the archive is
+// written to a temporary file for the duration of the conversion.
+func ConvertMHTMLToPdf(ctx context.Context, data []byte, opts ...Option) ([]byte, error) {
+	if err := validateMHTML(data); err != nil {
+		return nil, err
+	}
+
+	dir, err := os.MkdirTemp("", "html2pdf-mhtml-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temporary directory: %w", err)
+	}
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, "page.mhtml")
+	if err := os.WriteFile(file, data, 0o600); err != nil {
+		return nil, fmt.Errorf("failed to write archive: %w", err)
+	}
+
+	u := url.URL{Scheme: "file", Path: filepath.ToSlash(file)}
+	res, err := convert(ctx, document{url: u.String()}, newOptions(opts))
+	if err != nil {
+		return nil, err
+	}
+	return res.PDF, nil
+}
+
+// validateMHTML checks that data starts with MIME headers declaring a
+// multipart/related body with a boundary.
+func validateMHTML(data []byte) error {
+	msg, err := mail.ReadMessage(bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidMHTML, err)
+	}
+	mediaType, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidMHTML, err)
+	}
+	if mediaType != "multipart/related" || params["boundary"] == "" {
+		return fmt.Errorf("%w: content type %q", ErrInvalidMHTML, mediaType)
+	}
+	return nil
+}
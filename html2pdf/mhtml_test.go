@@ -0,0 +1,82 @@
+package html2pdf
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testMHTML is a page with an embedded style sheet, like Chrome saves them.
+const testMHTML = "From: <Saved by Blink>\r\n" +
+	"Snapshot-Content-Location: https://example.com/report\r\n" +
+	"Subject: Report\r\n" +
+	"MIME-Version: 1.0\r\n" +
+	"Content-Type: multipart/related;\r\n" +
+	"\ttype=\"text/html\";\r\n" +
+	"\tboundary=\"----MultipartBoundary--abc\"\r\n" +
+	"\r\n" +
+	"------MultipartBoundary--abc\r\n" +
+	"Content-Type: text/html\r\n" +
+	"Content-Location: https://example.com/report\r\n" +
+	"\r\n" +
+	"<html><head><link rel=\"stylesheet\" href=\"https://example.com/style.css\"></head><body><h1>Quarterly report</h1></body></html>\r\n" +
+	"------MultipartBoundary--abc\r\n" +
+	"Content-Type: text/css\r\n" +
+	"Content-Location: https://example.com/style.css\r\n" +
+	"\r\n" +
+	"h1 { color: navy; }\r\n" +
+	"------MultipartBoundary--abc--\r\n"
+
+func TestValidateMHTML(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		wantErr bool
+	}{
+		{name: "chrome archive", data: testMHTML},
+		{name: "html", data: "<html><body>Hi</body></html>", wantErr: true},
+		{name: "wrong type", data: "Content-Type: text/html\r\n\r\n<p>Hi</p>", wantErr: true},
+		{name: "no boundary", data: "Content-Type: multipart/related\r\n\r\n", wantErr: true},
+		{name: "empty", data: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateMHTML([]byte(tt.data))
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidMHTML) {
+					t.Errorf("Expected ErrInvalidMHTML, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("validateMHTML() error = %v", err)
+			}
+		})
+	}
+}
+
+func TestConvertMHTMLToPdf(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	pdfBytes, err := ConvertMHTMLToPdf(ctx, []byte(testMHTML), WithPostRenderAssertion(func(doc TextIndex) error {
+		if !doc.Contains("Quarterly report") {
+			return errors.New("heading missing")
+		}
+		return nil
+	}))
+	if err != nil {
+		t.Fatalf("ConvertMHTMLToPdf() error = %v", err)
+	}
+	if !bytes.HasPrefix(pdfBytes, []byte("%PDF-")) {
+		t.Error("Expected a PDF document")
+	}
+
+	if _, err := ConvertMHTMLToPdf(ctx, []byte(strings.Repeat("x", 10))); !errors.Is(err, ErrInvalidMHTML) {
+		t.Errorf("Expected ErrInvalidMHTML, got %v", err)
+	}
+}
@@ -0,0 +1,109 @@
+package html2pdf
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"mime/multipart"
+	"path"
+	"strings"
+	"time"
+)
+
+// ConvertMultipartToPdf converts an HTML page uploaded together with its
+// assets in a multipart/form-data body, e.g. from an HTTP handler. Each part
+// is stored under its form field name, or its file name when the field name
+// is empty, so "css/style.css" is served at that path relative to the page.
+// entryHTML is the path of the page, e.g. "index.html". Parts are held in
+// memory; bound the request size with http.MaxBytesReader.
+func ConvertMultipartToPdf(ctx context.Context, r *multipart.Reader, entryHTML string, opts ...Option) ([]byte, error) {
+	fsys, err := readMultipart(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read multipart upload: %w", err)
+	}
+	return convertFS(ctx, fsys, entryHTML, opts...)
+}
+
+// readMultipart reads every part of r into a memFS.
+func readMultipart(r *multipart.Reader) (memFS, error) {
+	fsys := memFS{}
+	for {
+		part, err := r.NextPart()
+		if err == io.EOF {
+			return fsys, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		name := part.FormName()
+		if name == "" {
+			name = part.FileName()
+		}
+		name = strings.TrimPrefix(path.Clean("/"+name), "/")
+		if name == "" {
+			part.Close()
+			return nil, fmt.Errorf("part has no name")
+		}
+		data, err := io.ReadAll(part)
+		part.Close()
+		if err != nil {
+			return nil, err
+		}
+		fsys[name] = data
+	}
+}
+
+// memFS is a read-only file system of slash-separated paths to contents.
+// Directories are implied by the paths of the files they contain.
+type memFS map[string][]byte
+
+// Open implements fs.FS.
+func (m memFS) Open(name string) (fs.File, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrInvalid}
+	}
+	if data, ok := m[name]; ok {
+		return &memFile{Reader: bytes.NewReader(data), info: memInfo{name: path.Base(name), size: int64(len(data))}}, nil
+	}
+	prefix := name + "/"
+	if name == "." {
+		prefix = ""
+	}
+	for p := range m {
+		if strings.HasPrefix(p, prefix) {
+			return &memFile{Reader: bytes.NewReader(nil), info: memInfo{name: path.Base(name), dir: true}}, nil
+		}
+	}
+	return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+}
+
+// memFile is an open memFS file or directory.
+type memFile struct {
+	*bytes.Reader
+	info memInfo
+}
+
+func (f *memFile) Stat() (fs.FileInfo, error) { return f.info, nil }
+func (f *memFile) Close() error               { return nil }
+
+// memInfo describes a memFS entry.
+type memInfo struct {
+	name string
+	size int64
+	dir  bool
+}
+
+func (i memInfo) Name() string       { return i.name }
+func (i memInfo) Size() int64        { return i.size }
+func (i memInfo) ModTime() time.Time { return time.Time{} }
+func (i memInfo) IsDir() bool        { return i.dir }
+func (i memInfo) Sys() interface{}   { return nil }
+
+func (i memInfo) Mode() fs.FileMode {
+	if i.dir {
+		return fs.ModeDir | 0o555
+	}
+	return 0o444
+}
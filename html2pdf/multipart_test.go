@@ -0,0 +1,123 @@
+package html2pdf
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"mime/multipart"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testMultipart builds a multipart body with one part per field name.
+func testMultipart(t *testing.T, files map[string]string) *multipart.Reader {
+	t.Helper()
+	var buf bytes.Buffer
+	mw := multipart.NewWriter(&buf)
+	for name, content := range files {
+		w, err := mw.CreateFormFile(name, name)
+		if err != nil {
+			t.Fatalf("Failed to create part: %v", err)
+		}
+		if _, err := io.WriteString(w, content); err != nil {
+			t.Fatalf("Failed to write part: %v", err)
+		}
+	}
+	if err := mw.Close(); err != nil {
+		t.Fatalf("Failed to close multipart writer: %v", err)
+	}
+	return multipart.NewReader(&buf, mw.Boundary())
+}
+
+func TestReadMultipart(t *testing.T) {
+	fsys, err := readMultipart(testMultipart(t, map[string]string{
+		"index.html":      "<h1>Upload</h1>",
+		"/css/style.css":  "h1 { color: red; }",
+		"../img/logo.svg": "<svg/>",
+	}))
+	if err != nil {
+		t.Fatalf("readMultipart() error = %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		want    string
+		wantDir bool
+		wantErr error
+	}{
+		{name: "index.html", want: "<h1>Upload</h1>"},
+		{name: "css/style.css", want: "h1 { color: red; }"},
+		{name: "img/logo.svg", want: "<svg/>"},
+		{name: ".", wantDir: true},
+		{name: "css", wantDir: true},
+		{name: "missing.html", wantErr: fs.ErrNotExist},
+		{name: "../index.html", wantErr: fs.ErrInvalid},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			info, err := fs.Stat(fsys, tt.name)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("Stat() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Stat() error = %v", err)
+			}
+			if info.IsDir() != tt.wantDir {
+				t.Fatalf("Expected IsDir() = %v", tt.wantDir)
+			}
+			if tt.wantDir {
+				return
+			}
+			got, err := fs.ReadFile(fsys, tt.name)
+			if err != nil || string(got) != tt.want {
+				t.Errorf("ReadFile() = %q, %v, want %q", got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestMemFSServesIndex(t *testing.T) {
+	srv, err := newAssetServer(http.FileServer(http.FS(memFS{"index.html": []byte("<h1>Index</h1>")})))
+	if err != nil {
+		t.Fatalf("newAssetServer() error = %v", err)
+	}
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL("index.html"))
+	if err != nil {
+		t.Fatalf("Failed to fetch page: %v", err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK || string(body) != "<h1>Index</h1>" {
+		t.Errorf("Unexpected response %d %q", resp.StatusCode, body)
+	}
+}
+
+func TestConvertMultipartToPdf(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	got, err := ConvertMultipartToPdf(ctx, testMultipart(t, map[string]string{
+		"index.html":    `<html><head><link rel="stylesheet" href="css/style.css"></head><body><h1>Upload</h1></body></html>`,
+		"css/style.css": `h1 { color: #2c3e50; }`,
+	}), "index.html")
+	if err != nil {
+		t.Fatalf("ConvertMultipartToPdf() error = %v", err)
+	}
+	if !strings.HasPrefix(string(got), "%PDF") {
+		t.Errorf("ConvertMultipartToPdf() returned non-PDF content")
+	}
+
+	_, err = ConvertMultipartToPdf(ctx, testMultipart(t, map[string]string{"index.html": "<h1>x</h1>"}), "missing.html")
+	if !errors.Is(err, ErrHTMLFileNotFound) {
+		t.Errorf("Expected ErrHTMLFileNotFound, got %v", err)
+	}
+}
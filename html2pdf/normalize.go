@@ -0,0 +1,63 @@
+package html2pdf
+
+import (
+	"math"
+
+	"github.com/patipolchat/html2pdf/html2pdf/internal/pdf"
+)
+
+// NormalizePdf rotates and scales every page of a PDF to the target size, so
+// documents assembled from mixed page sizes (merged sections, appended
+// external PDFs) print on a single media size. Pages whose orientation differs
+// from the target are turned a quarter clockwise, then scaled to fit and
+// centered. Pages already at the target size are left unchanged.
+func NormalizePdf(data []byte, targetSize PaperSize) ([]byte, error) {
+	if err := targetSize.validate(); err != nil {
+		return nil, err
+	}
+	return editPDF(data, func(doc *pdf.Document) error {
+		return normalizePages(doc, targetSize)
+	})
+}
+
+// normalizePages fits every page of doc onto targetSize.
+func normalizePages(doc *pdf.Document, targetSize PaperSize) error {
+	pages, err := doc.Pages()
+	if err != nil {
+		return err
+	}
+	target := pdf.Box{URX: targetSize.Width * pointsPerInch, URY: targetSize.Height * pointsPerInch}
+
+	for _, p := range pages {
+		box := doc.PageBox(p, "CropBox")
+		rotation := doc.PageRotation(p)
+
+		// Move the visible box to the origin and apply the page's own rotation.
+		m := pdf.Translate(-box.LLX, -box.LLY).Then(pdf.RotateClockwise(rotation, box.Width(), box.Height()))
+		w, h := box.Width(), box.Height()
+		if rotation == 90 || rotation == 270 {
+			w, h = h, w
+		}
+
+		turn := w != h && target.Width() != target.Height() && (w > h) != (target.Width() > target.Height())
+		if turn {
+			m = m.Then(pdf.RotateClockwise(90, w, h))
+			w, h = h, w
+		}
+
+		if !turn && rotation == 0 && box.LLX == 0 && box.LLY == 0 &&
+			nearlyEqual(w, target.Width()) && nearlyEqual(h, target.Height()) {
+			continue
+		}
+
+		scale := math.Min(target.Width()/w, target.Height()/h)
+		m = m.Then(pdf.Scale(scale, scale)).Then(pdf.Translate((target.Width()-w*scale)/2, (target.Height()-h*scale)/2))
+		doc.TransformPage(p, m, target)
+	}
+	return nil
+}
+
+// nearlyEqual compares page dimensions in points, allowing for rounding.
+func nearlyEqual(a, b float64) bool {
+	return math.Abs(a-b) < 0.5
+}
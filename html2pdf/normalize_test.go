@@ -0,0 +1,72 @@
+package html2pdf
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/patipolchat/html2pdf/html2pdf/internal/pdf"
+)
+
+// mixedSizePDF returns a PDF with a Letter page, a landscape A5 page and a
+// Letter page rotated by 90 degrees.
+func mixedSizePDF(t *testing.T) []byte {
+	t.Helper()
+	doc := pdf.New()
+	content := doc.Add(pdf.Encode(nil, []byte("BT /F1 12 Tf 72 72 Td (Text) Tj ET")))
+	pages := []pdf.Ref{
+		doc.Add(pdf.Dict{"Type": pdf.Name("Page"), "MediaBox": pdf.Array{0, 0, 612, 792}, "Contents": content}),
+		doc.Add(pdf.Dict{"Type": pdf.Name("Page"), "MediaBox": pdf.Array{0, 0, 595, 420}, "Contents": content}),
+		doc.Add(pdf.Dict{"Type": pdf.Name("Page"), "MediaBox": pdf.Array{0, 0, 612, 792}, "Rotate": 90, "Contents": content}),
+	}
+	doc.SetPages(pages)
+	return doc.Bytes()
+}
+
+func TestNormalizePdf(t *testing.T) {
+	out, err := NormalizePdf(mixedSizePDF(t), A4)
+	if err != nil {
+		t.Fatalf("NormalizePdf() error = %v", err)
+	}
+	doc, err := pdf.Parse(out)
+	if err != nil {
+		t.Fatalf("Failed to parse output: %v", err)
+	}
+	pages, _ := doc.Pages()
+	if len(pages) != 3 {
+		t.Fatalf("Expected 3 pages, got %d", len(pages))
+	}
+
+	wantW, wantH := A4.Width*pointsPerInch, A4.Height*pointsPerInch
+	for i, p := range pages {
+		box := doc.PageBox(p, "MediaBox")
+		if math.Abs(box.Width()-wantW) > 0.01 || math.Abs(box.Height()-wantH) > 0.01 {
+			t.Errorf("Page %d: expected A4 media box, got %+v", i+1, box)
+		}
+		if r := doc.PageRotation(p); r != 0 {
+			t.Errorf("Page %d: expected rotation to be folded into content, got %d", i+1, r)
+		}
+	}
+}
+
+func TestNormalizePdfKeepsMatchingPages(t *testing.T) {
+	in := testPDF(t, 2)
+	out, err := NormalizePdf(in, Letter)
+	if err != nil {
+		t.Fatalf("NormalizePdf() error = %v", err)
+	}
+	doc, _ := pdf.Parse(out)
+	pages, _ := doc.Pages()
+	for _, p := range pages {
+		if _, ok := doc.Get(p).(pdf.Dict)["Contents"].(pdf.Array); ok {
+			t.Error("Expected Letter pages to be left untransformed")
+		}
+	}
+}
+
+func TestNormalizePdfInvalidSize(t *testing.T) {
+	_, err := NormalizePdf(testPDF(t, 1), PaperSize{Width: 0, Height: 11})
+	if !errors.Is(err, ErrInvalidPaperSize) {
+		t.Errorf("Expected ErrInvalidPaperSize, got %v", err)
+	}
+}
@@ -0,0 +1,92 @@
+package html2pdf
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/chromedp/cdproto/page"
+	"github.com/chromedp/chromedp"
+)
+
+// maxOverflowSelectors limits how many offending elements an overflow error lists.
+const maxOverflowSelectors = 10
+
+// ErrContentOverflow is returned by WithFailOnOverflow when elements are
+// wider than the printable area.
+var ErrContentOverflow = fmt.Errorf("content overflows the printable area")
+
+// overflowScript returns selectors of the outermost elements that extend past
+// the right or left edge of the layout viewport.
+const overflowScript = `(() => {
+	const width = document.documentElement.clientWidth;
+	const selector = (el) => {
+		const parts = [];
+		for (; el && el.nodeType === Node.ELEMENT_NODE && el !== document.documentElement; el = el.parentElement) {
+			if (el.id) {
+				parts.unshift("#" + CSS.escape(el.id));
+				break;
+			}
+			let part = el.localName;
+			if (el.parentElement) {
+				const siblings = Array.from(el.parentElement.children).filter((s) => s.localName === el.localName);
+				if (siblings.length > 1) {
+					part += ":nth-of-type(" + (siblings.indexOf(el) + 1) + ")";
+				}
+			}
+			parts.unshift(part);
+		}
+		return parts.join(" > ");
+	};
+	const offenders = [];
+	for (const el of document.body ? document.body.querySelectorAll("*") : []) {
+		if (offenders.some((o) => o.contains(el))) {
+			continue;
+		}
+		const rect = el.getBoundingClientRect();
+		if (rect.width > 0 && (rect.right > width + 1 || rect.left < -1)) {
+			offenders.push(el);
+		}
+	}
+	return offenders.map(selector);
+})()`
+
+// WithFailOnOverflow makes the conversion fail with ErrContentOverflow when
+// elements are wider than the printable area and would be cut off, e.g. wide
+// tables. The error lists selectors of the offending elements.
+func WithFailOnOverflow(enabled bool) Option {
+	return func(o *options) {
+		o.failOnOverflow = enabled
+	}
+}
+
+// overflowSelectors lays the page out at the printable width of params and
+// returns selectors of the elements that do not fit.
+func overflowSelectors(ctx context.Context, params *page.PrintToPDFParams) ([]string, error) {
+	paperW, _ := paperDimensions(params)
+	width := (paperW - params.MarginLeft - params.MarginRight) * cssPixelsPerInch / printScale(params)
+	if _, _, err := measureContent(ctx, width); err != nil {
+		return nil, err
+	}
+	var selectors []string
+	if err := chromedp.Evaluate(overflowScript, &selectors).Do(ctx); err != nil {
+		return nil, err
+	}
+	return selectors, nil
+}
+
+// overflowError returns an ErrContentOverflow error listing selectors, or nil.
+func overflowError(selectors []string) error {
+	if len(selectors) == 0 {
+		return nil
+	}
+	list := selectors
+	if len(list) > maxOverflowSelectors {
+		list = list[:maxOverflowSelectors]
+	}
+	msg := strings.Join(list, ", ")
+	if n := len(selectors) - len(list); n > 0 {
+		msg += fmt.Sprintf(" and %d more", n)
+	}
+	return fmt.Errorf("%w: %s", ErrContentOverflow, msg)
+}
@@ -0,0 +1,61 @@
+package html2pdf
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestOverflowError(t *testing.T) {
+	if err := overflowError(nil); err != nil {
+		t.Errorf("Expected no error without offenders, got %v", err)
+	}
+
+	err := overflowError([]string{"#totals", "body > table:nth-of-type(2)"})
+	if !errors.Is(err, ErrContentOverflow) {
+		t.Fatalf("Expected ErrContentOverflow, got %v", err)
+	}
+	if !strings.HasSuffix(err.Error(), ": #totals, body > table:nth-of-type(2)") {
+		t.Errorf("Expected selectors in error, got %q", err)
+	}
+
+	many := make([]string, maxOverflowSelectors+3)
+	for i := range many {
+		many[i] = fmt.Sprintf("#t%d", i)
+	}
+	if err := overflowError(many); !strings.HasSuffix(err.Error(), "and 3 more") {
+		t.Errorf("Expected truncated list, got %q", err)
+	}
+}
+
+func TestConvertHtmlToPdfWithFailOnOverflow(t *testing.T) {
+	tests := []struct {
+		name    string
+		html    string
+		wantErr error
+	}{
+		{name: "fits", html: `<html><body><table><tr><td>Narrow</td></tr></table></body></html>`},
+		{name: "too wide", html: `<html><body><table id="wide" style="width: 3000px"><tr><td>Wide</td></tr></table></body></html>`, wantErr: ErrContentOverflow},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			defer cancel()
+
+			_, err := ConvertHtmlToPdf(ctx, tt.html, WithFailOnOverflow(true))
+			if tt.wantErr == nil {
+				if err != nil {
+					t.Fatalf("ConvertHtmlToPdf() error = %v", err)
+				}
+				return
+			}
+			if !errors.Is(err, tt.wantErr) || !strings.Contains(err.Error(), "#wide") {
+				t.Errorf("Expected %v naming #wide, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
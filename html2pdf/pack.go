@@ -0,0 +1,108 @@
+package html2pdf
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// PackSection is one independently rendered part of a report pack.
+type PackSection struct {
+	// Name identifies the section in errors and in PackResult.
+	Name string
+	// HTML is the content of the section.
+	HTML string
+}
+
+// PackCache stores rendered section PDFs by a hash of their content and the
+// options that affect the output. Implementations must be safe for
+// concurrent use if packs are rendered concurrently.
+type PackCache interface {
+	Get(key string) ([]byte, bool)
+	Put(key string, pdf []byte)
+}
+
+// PackResult is a rendered report pack.
+type PackResult struct {
+	// PDF holds the pages of every section, in order.
+	PDF []byte
+	// Rendered lists the sections that were converted.
+	Rendered []string
+	// Reused lists the sections taken from the cache.
+	Reused []string
+}
+
+// RenderPack converts the sections of a report pack and merges them into one
+// document. Sections whose content and options match an earlier render are
+// taken from cache instead of being converted again, so regenerating a pack
+// after one section changed only renders that section. cache may be nil.
+// Of the options, WithPageCallback and WithChecksumMetadata also apply to the
+// merged document.
+func RenderPack(ctx context.Context, sections []PackSection, cache PackCache, opts ...Option) (*PackResult, error) {
+	options := newOptions(opts)
+	res := &PackResult{}
+	docs := make([][]byte, len(sections))
+	for i, s := range sections {
+		html, err := applyStarterTemplate(s.HTML, options)
+		if err != nil {
+			return nil, err
+		}
+		key := dedupKey(html, options)
+		if cache != nil {
+			if buf, ok := cache.Get(key); ok {
+				docs[i] = buf
+				res.Reused = append(res.Reused, s.Name)
+				continue
+			}
+		}
+		r, err := convert(ctx, document{html: html}, options)
+		if err != nil {
+			return nil, fmt.Errorf("failed to render section %s: %w", s.Name, err)
+		}
+		if cache != nil {
+			cache.Put(key, r.PDF)
+		}
+		docs[i] = r.PDF
+		res.Rendered = append(res.Rendered, s.Name)
+	}
+	merged, err := MergePdf(docs, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to merge sections: %w", err)
+	}
+	res.PDF = merged
+	return res, nil
+}
+
+// MemoryPackCache is a PackCache held in memory. It is safe for concurrent
+// use and never evicts entries.
+type MemoryPackCache struct {
+	mu   sync.Mutex
+	pdfs map[string][]byte
+}
+
+// NewMemoryPackCache returns an empty MemoryPackCache.
+func NewMemoryPackCache() *MemoryPackCache {
+	return &MemoryPackCache{pdfs: map[string][]byte{}}
+}
+
+// Get returns the PDF stored under key.
+func (c *MemoryPackCache) Get(key string) ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	buf, ok := c.pdfs[key]
+	return buf, ok
+}
+
+// Put stores pdf under key.
+func (c *MemoryPackCache) Put(key string, pdf []byte) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.pdfs[key] = pdf
+}
+
+// Len returns the number of stored PDFs.
+func (c *MemoryPackCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.pdfs)
+}
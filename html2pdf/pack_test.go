@@ -0,0 +1,87 @@
+package html2pdf
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/patipolchat/html2pdf/html2pdf/internal/pdf"
+)
+
+func TestRenderPackFromCache(t *testing.T) {
+	sections := []PackSection{
+		{Name: "summary", HTML: "<h1>Summary</h1>"},
+		{Name: "details", HTML: "<h1>Details</h1>"},
+	}
+	cache := NewMemoryPackCache()
+	options := getDefaultOptions()
+	cache.Put(dedupKey(sections[0].HTML, options), testPDF(t, 1))
+	cache.Put(dedupKey(sections[1].HTML, options), testPDF(t, 2))
+
+	var pages []int
+	res, err := RenderPack(context.Background(), sections, cache, WithPageCallback(func(docIndex, pageInDoc, absolutePage int) {
+		pages = append(pages, absolutePage)
+	}))
+	if err != nil {
+		t.Fatalf("RenderPack() error = %v", err)
+	}
+	if want := []string{"summary", "details"}; !reflect.DeepEqual(res.Reused, want) || len(res.Rendered) > 0 {
+		t.Errorf("Expected %v reused and none rendered, got %v and %v", want, res.Reused, res.Rendered)
+	}
+	doc, err := pdf.Parse(res.PDF)
+	if err != nil {
+		t.Fatalf("Failed to parse merged PDF: %v", err)
+	}
+	if got, _ := doc.Pages(); len(got) != 3 {
+		t.Errorf("Expected 3 pages, got %d", len(got))
+	}
+	if want := []int{1, 2, 3}; !reflect.DeepEqual(pages, want) {
+		t.Errorf("Expected page callbacks %v, got %v", want, pages)
+	}
+}
+
+func TestMemoryPackCache(t *testing.T) {
+	cache := NewMemoryPackCache()
+	if _, ok := cache.Get("a"); ok {
+		t.Error("Expected an empty cache")
+	}
+	cache.Put("a", []byte("%PDF-a"))
+	cache.Put("a", []byte("%PDF-b"))
+	if buf, ok := cache.Get("a"); !ok || string(buf) != "%PDF-b" {
+		t.Errorf("Expected the latest PDF, got %q, %v", buf, ok)
+	}
+	if cache.Len() != 1 {
+		t.Errorf("Expected 1 entry, got %d", cache.Len())
+	}
+}
+
+func TestRenderPack(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	sections := []PackSection{
+		{Name: "summary", HTML: "<h1>Summary</h1>"},
+		{Name: "details", HTML: "<h1>Details</h1>"},
+	}
+	cache := NewMemoryPackCache()
+	first, err := RenderPack(ctx, sections, cache)
+	if err != nil {
+		t.Fatalf("RenderPack() error = %v", err)
+	}
+	if len(first.Rendered) != 2 || len(first.Reused) != 0 {
+		t.Errorf("Expected both sections rendered, got %+v", first)
+	}
+
+	sections[1].HTML = "<h1>Details, revised</h1>"
+	second, err := RenderPack(ctx, sections, cache)
+	if err != nil {
+		t.Fatalf("RenderPack() error = %v", err)
+	}
+	if !reflect.DeepEqual(second.Rendered, []string{"details"}) || !reflect.DeepEqual(second.Reused, []string{"summary"}) {
+		t.Errorf("Expected only details to be rendered, got %+v", second)
+	}
+	if cache.Len() != 3 {
+		t.Errorf("Expected 3 cached sections, got %d", cache.Len())
+	}
+}
@@ -0,0 +1,67 @@
+package html2pdf
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// WithPageRanges prints only the given pages, e.g. "1-2,5" or "3-" for the
+// third page onwards. Malformed ranges make the conversion fail with
+// ErrInvalidPageRange. Page ranges cannot be combined with WithSectionHeaders.
+func WithPageRanges(ranges string) Option {
+	return func(o *options) {
+		o.pageRanges = ranges
+	}
+}
+
+// parsePageRanges parses a comma-separated list of pages ("5") and ranges
+// ("1-3", "4-", "-2") in Chrome's page range syntax.
+func parsePageRanges(s string) ([]PageRange, error) {
+	var ranges []PageRange
+	for _, item := range strings.Split(s, ",") {
+		item = strings.TrimSpace(item)
+		r, err := parsePageRange(item)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %q in %q", ErrInvalidPageRange, item, s)
+		}
+		ranges = append(ranges, r)
+	}
+	return ranges, nil
+}
+
+// parsePageRange parses a single page or range.
+func parsePageRange(item string) (PageRange, error) {
+	from, to, isRange := strings.Cut(item, "-")
+	if !isRange {
+		n, err := parsePageNumber(item)
+		return PageRange{From: n, To: n}, err
+	}
+	r := PageRange{From: 1}
+	var err error
+	if from = strings.TrimSpace(from); from != "" {
+		if r.From, err = parsePageNumber(from); err != nil {
+			return r, err
+		}
+	}
+	if to = strings.TrimSpace(to); to != "" {
+		if r.To, err = parsePageNumber(to); err != nil {
+			return r, err
+		}
+		if r.To < r.From {
+			return r, fmt.Errorf("range ends before it starts")
+		}
+	} else if from == "" {
+		return r, fmt.Errorf("range has no bounds")
+	}
+	return r, nil
+}
+
+// parsePageNumber parses a 1-based page number.
+func parsePageNumber(s string) (int, error) {
+	n, err := strconv.Atoi(s)
+	if err != nil || n < 1 {
+		return 0, fmt.Errorf("not a page number: %q", s)
+	}
+	return n, nil
+}
@@ -0,0 +1,56 @@
+package html2pdf
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestParsePageRanges(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    []PageRange
+		wantErr bool
+	}{
+		{in: "1", want: []PageRange{{From: 1, To: 1}}},
+		{in: "1-2,5", want: []PageRange{{From: 1, To: 2}, {From: 5, To: 5}}},
+		{in: " 3 - 4 , 7- ", want: []PageRange{{From: 3, To: 4}, {From: 7}}},
+		{in: "-2", want: []PageRange{{From: 1, To: 2}}},
+		{in: "", wantErr: true},
+		{in: "1,,2", wantErr: true},
+		{in: "0", wantErr: true},
+		{in: "3-1", wantErr: true},
+		{in: "a-b", wantErr: true},
+		{in: "-", wantErr: true},
+		{in: "1-2-3", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := parsePageRanges(tt.in)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidPageRange) {
+					t.Errorf("Expected ErrInvalidPageRange, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parsePageRanges() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parsePageRanges() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWithPageRanges(t *testing.T) {
+	if got := newOptions([]Option{WithPageRanges("1-2,5")}).printToPDFParams().PageRanges; got != "1-2,5" {
+		t.Errorf("Expected page ranges to be forwarded, got %q", got)
+	}
+
+	_, err := ConvertHtmlToPdf(context.Background(), "<p>x</p>", WithPageRanges("2-1"))
+	if !errors.Is(err, ErrInvalidPageRange) {
+		t.Errorf("Expected ErrInvalidPageRange, got %v", err)
+	}
+}
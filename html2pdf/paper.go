@@ -0,0 +1,138 @@
+package html2pdf
+
+import (
+	"fmt"
+	"strings"
+)
+
+var (
+	// ErrInvalidPaperSize is returned when a paper size is not positive or
+	// larger than PDF viewers support.
+	ErrInvalidPaperSize = fmt.Errorf("invalid paper size")
+	// ErrInvalidScale is returned when a print scale is outside the range Chrome supports.
+	ErrInvalidScale = fmt.Errorf("invalid scale")
+)
+
+const (
+	// minScale and maxScale bound the print scale Chrome accepts.
+	minScale = 0.1
+	maxScale = 2.0
+	// pointsPerInch converts Chrome's inch-based sizes to PDF points.
+	pointsPerInch = 72
+	// cssPixelsPerInch converts CSS pixels to inches.
+	cssPixelsPerInch = 96
+	// mmPerInch converts millimetres to inches.
+	mmPerInch = 25.4
+	// maxPaperSize is the longest page side in inches. PDF viewers such as
+	// Acrobat cannot display pages larger than 14400 points.
+	maxPaperSize = 200
+)
+
+// PaperSize is a page size in inches, in portrait orientation.
+type PaperSize struct {
+	Width  float64
+	Height float64
+}
+
+var (
+	// A0 is ISO A0 paper, 841 x 1189 mm.
+	A0 = PaperSize{Width: 841 / mmPerInch, Height: 1189 / mmPerInch}
+	// A1 is ISO A1 paper, 594 x 841 mm.
+	A1 = PaperSize{Width: 594 / mmPerInch, Height: 841 / mmPerInch}
+	// A2 is ISO A2 paper, 420 x 594 mm.
+	A2 = PaperSize{Width: 420 / mmPerInch, Height: 594 / mmPerInch}
+	// A3 is ISO A3 paper, 297 x 420 mm.
+	A3 = PaperSize{Width: 297 / mmPerInch, Height: 420 / mmPerInch}
+	// A4 is ISO A4 paper, 210 x 297 mm.
+	A4 = PaperSize{Width: 210 / mmPerInch, Height: 297 / mmPerInch}
+	// A5 is ISO A5 paper, 148 x 210 mm.
+	A5 = PaperSize{Width: 148 / mmPerInch, Height: 210 / mmPerInch}
+	// A6 is ISO A6 paper, 105 x 148 mm.
+	A6 = PaperSize{Width: 105 / mmPerInch, Height: 148 / mmPerInch}
+	// Letter is US Letter paper, 8.5 x 11 in.
+	Letter = PaperSize{Width: 8.5, Height: 11}
+	// Legal is US Legal paper, 8.5 x 14 in.
+	Legal = PaperSize{Width: 8.5, Height: 14}
+	// Tabloid is US Tabloid paper, 11 x 17 in.
+	Tabloid = PaperSize{Width: 11, Height: 17}
+	// Ledger is US Ledger paper, 17 x 11 in. It is Tabloid turned on its
+	// side, and the one preset that is wider than it is tall.
+	Ledger = PaperSize{Width: 17, Height: 11}
+)
+
+// paperSizes maps lower-case preset names to sizes for PaperSizeByName.
+var paperSizes = map[string]PaperSize{
+	"a0": A0, "a1": A1, "a2": A2, "a3": A3, "a4": A4, "a5": A5, "a6": A6,
+	"letter": Letter, "legal": Legal, "tabloid": Tabloid, "ledger": Ledger,
+}
+
+// PaperSizeByName returns the preset size with the given name, e.g. "A4" or
+// "letter", ignoring case. It reports false for unknown names.
+func PaperSizeByName(name string) (PaperSize, bool) {
+	size, ok := paperSizes[strings.ToLower(strings.TrimSpace(name))]
+	return size, ok
+}
+
+// validate reports ErrInvalidPaperSize when either dimension is not positive
+// or exceeds maxPaperSize.
+func (s PaperSize) validate() error {
+	if s.Width <= 0 || s.Height <= 0 {
+		return fmt.Errorf("%w: %gx%gin", ErrInvalidPaperSize, s.Width, s.Height)
+	}
+	if s.Width > maxPaperSize || s.Height > maxPaperSize {
+		return fmt.Errorf("%w: %gx%gin exceeds %din", ErrInvalidPaperSize, s.Width, s.Height, maxPaperSize)
+	}
+	return nil
+}
+
+// WithPaperSize sets the paper size of the generated PDF, e.g. A4 or Letter.
+// Chrome prints on Letter paper by default. A size that is not positive makes
+// the conversion fail with ErrInvalidPaperSize.
+func WithPaperSize(size PaperSize) Option {
+	return func(o *options) {
+		o.paperSize = &size
+	}
+}
+
+// WithCustomPaperSize sets a paper size that has no preset, e.g. 4 x 6 in
+// shipping labels, with width and height in inches. Sizes that are not
+// positive or exceed 200 inches make the conversion fail with
+// ErrInvalidPaperSize. For receipt rolls whose length follows the content,
+// use WithRollPaper.
+func WithCustomPaperSize(width, height float64) Option {
+	return WithPaperSize(PaperSize{Width: width, Height: height})
+}
+
+// WithLandscape prints in landscape orientation, e.g. for wide tables. The
+// paper size stays given in portrait; Chrome swaps width and height.
+func WithLandscape(enabled bool) Option {
+	return func(o *options) {
+		o.landscape = enabled
+	}
+}
+
+// WithScale scales the rendered page, e.g. 0.75 to shrink a wide dashboard
+// so it fits the paper. The scale must be between 0.1 and 2; other values
+// make the conversion fail with ErrInvalidScale.
+func WithScale(scale float64) Option {
+	return func(o *options) {
+		o.scale = scale
+	}
+}
+
+// validateScale reports ErrInvalidScale when scale is set and out of range.
+func validateScale(scale float64) error {
+	if scale != 0 && (scale < minScale || scale > maxScale) {
+		return fmt.Errorf("%w: %g is not between %g and %g", ErrInvalidScale, scale, minScale, maxScale)
+	}
+	return nil
+}
+
+// WithPreferCSSPageSize makes a size declared with the CSS @page rule, e.g.
+// @page { size: A5 landscape; }, take precedence over the paper size and
+// orientation options. Pages without an @page size use the paper size.
+func WithPreferCSSPageSize(enabled bool) Option {
+	return func(o *options) {
+		o.preferCSSPageSize = enabled
+	}
+}
@@ -0,0 +1,192 @@
+package html2pdf
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestWithPaperSize(t *testing.T) {
+	tests := []struct {
+		name       string
+		opts       []Option
+		wantWidth  float64
+		wantHeight float64
+	}{
+		{name: "default", wantWidth: 0, wantHeight: 0},
+		{name: "A4", opts: []Option{WithPaperSize(A4)}, wantWidth: A4.Width, wantHeight: A4.Height},
+		{name: "Letter", opts: []Option{WithPaperSize(Letter)}, wantWidth: 8.5, wantHeight: 11},
+		{name: "custom", opts: []Option{WithCustomPaperSize(4, 6)}, wantWidth: 4, wantHeight: 6},
+		{name: "last size wins", opts: []Option{WithPaperSize(Letter), WithPaperSize(A4)}, wantWidth: A4.Width, wantHeight: A4.Height},
+		{name: "roll paper overrides width", opts: []Option{WithPaperSize(A4), WithRollPaper(80)}, wantWidth: 80 / mmPerInch, wantHeight: A4.Height},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params := newOptions(tt.opts).printToPDFParams()
+			if params.PaperWidth != tt.wantWidth || params.PaperHeight != tt.wantHeight {
+				t.Errorf("Expected %gx%g, got %gx%g", tt.wantWidth, tt.wantHeight, params.PaperWidth, params.PaperHeight)
+			}
+		})
+	}
+
+	if newOptions([]Option{WithPaperSize(A4)}).fingerprint() == getDefaultOptions().fingerprint() {
+		t.Error("Expected the paper size to change the options fingerprint")
+	}
+}
+
+func TestPaperSizeByName(t *testing.T) {
+	tests := []struct {
+		name   string
+		want   PaperSize
+		wantOK bool
+	}{
+		{name: "A4", want: A4, wantOK: true},
+		{name: "a3", want: A3, wantOK: true},
+		{name: " Letter ", want: Letter, wantOK: true},
+		{name: "LEDGER", want: Ledger, wantOK: true},
+		{name: "B5"},
+		{name: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := PaperSizeByName(tt.name)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("PaperSizeByName(%q) = %v, %v, want %v, %v", tt.name, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestPaperSizePresets(t *testing.T) {
+	// Each ISO A size halves the previous one along its long side.
+	iso := []PaperSize{A0, A1, A2, A3, A4, A5, A6}
+	for i := 1; i < len(iso); i++ {
+		if d := iso[i].Height - iso[i-1].Width; d < -0.01 || d > 0.01 {
+			t.Errorf("A%d height %g does not match A%d width %g", i, iso[i].Height, i-1, iso[i-1].Width)
+		}
+	}
+	if Ledger.Width != Tabloid.Height || Ledger.Height != Tabloid.Width {
+		t.Errorf("Expected Ledger %v to be Tabloid %v rotated", Ledger, Tabloid)
+	}
+	for name, size := range paperSizes {
+		if err := size.validate(); err != nil {
+			t.Errorf("%s: %v", name, err)
+		}
+	}
+}
+
+func TestWithLandscape(t *testing.T) {
+	if newOptions(nil).printToPDFParams().Landscape {
+		t.Error("Expected portrait by default")
+	}
+	params := newOptions([]Option{WithPaperSize(A4), WithLandscape(true)}).printToPDFParams()
+	if !params.Landscape || params.PaperWidth != A4.Width {
+		t.Errorf("Expected landscape A4 given in portrait, got %+v", params)
+	}
+	if newOptions([]Option{WithLandscape(true), WithLandscape(false)}).printToPDFParams().Landscape {
+		t.Error("Expected WithLandscape(false) to restore portrait")
+	}
+}
+
+func TestWithScale(t *testing.T) {
+	if got := newOptions(nil).printToPDFParams().Scale; got != 0 {
+		t.Errorf("Expected Chrome's default scale, got %v", got)
+	}
+	if got := newOptions([]Option{WithScale(0.75)}).printToPDFParams().Scale; got != 0.75 {
+		t.Errorf("Expected scale 0.75, got %v", got)
+	}
+
+	tests := []struct {
+		scale   float64
+		wantErr bool
+	}{
+		{scale: 0},
+		{scale: 0.1},
+		{scale: 2},
+		{scale: 0.05, wantErr: true},
+		{scale: 2.5, wantErr: true},
+		{scale: -1, wantErr: true},
+	}
+	for _, tt := range tests {
+		if err := validateScale(tt.scale); (err != nil) != tt.wantErr || (err != nil && !errors.Is(err, ErrInvalidScale)) {
+			t.Errorf("validateScale(%v) = %v, wantErr %v", tt.scale, err, tt.wantErr)
+		}
+	}
+
+	_, err := ConvertHtmlToPdf(context.Background(), "<p>x</p>", WithScale(3))
+	if !errors.Is(err, ErrInvalidScale) {
+		t.Errorf("Expected ErrInvalidScale, got %v", err)
+	}
+}
+
+func TestWithPreferCSSPageSize(t *testing.T) {
+	if newOptions(nil).printToPDFParams().PreferCSSPageSize {
+		t.Error("Expected the paper size options to win by default")
+	}
+	if !newOptions([]Option{WithPreferCSSPageSize(true)}).printToPDFParams().PreferCSSPageSize {
+		t.Error("Expected PreferCSSPageSize to be set")
+	}
+}
+
+func TestConvertHtmlToPdfWithInvalidPaperSize(t *testing.T) {
+	tests := []struct {
+		name string
+		opt  Option
+	}{
+		{name: "zero height", opt: WithPaperSize(PaperSize{Width: 8.5})},
+		{name: "negative width", opt: WithCustomPaperSize(-4, 6)},
+		{name: "too long", opt: WithCustomPaperSize(4, 250)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ConvertHtmlToPdf(context.Background(), "<p>x</p>", tt.opt)
+			if !errors.Is(err, ErrInvalidPaperSize) {
+				t.Errorf("Expected ErrInvalidPaperSize, got %v", err)
+			}
+		})
+	}
+}
+
+func TestConvertHtmlToPdfWithPaperSize(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	res, err := ConvertHtmlToResult(ctx, "<html><body><h1>A4</h1></body></html>", WithPaperSize(A4))
+	if err != nil {
+		t.Fatalf("ConvertHtmlToResult() error = %v", err)
+	}
+	pages, err := res.PageInfos()
+	if err != nil {
+		t.Fatalf("PageInfos() error = %v", err)
+	}
+	if len(pages) != 1 || !pages[0].Matches(A4) {
+		t.Errorf("Expected one A4 page, got %+v", pages)
+	}
+
+	res, err = ConvertHtmlToResult(ctx, "<html><body><h1>Wide</h1></body></html>", WithPaperSize(A4), WithLandscape(true))
+	if err != nil {
+		t.Fatalf("ConvertHtmlToResult() error = %v", err)
+	}
+	pages, err = res.PageInfos()
+	if err != nil {
+		t.Fatalf("PageInfos() error = %v", err)
+	}
+	if len(pages) != 1 || !pages[0].Matches(A4) || !pages[0].Landscape() {
+		t.Errorf("Expected one landscape A4 page, got %+v", pages)
+	}
+
+	a5 := PaperSize{Width: 148 / mmPerInch, Height: 210 / mmPerInch}
+	res, err = ConvertHtmlToResult(ctx, "<html><head><style>@page { size: A5 landscape; }</style></head><body>A5</body></html>",
+		WithPaperSize(A4), WithPreferCSSPageSize(true))
+	if err != nil {
+		t.Fatalf("ConvertHtmlToResult() error = %v", err)
+	}
+	pages, err = res.PageInfos()
+	if err != nil {
+		t.Fatalf("PageInfos() error = %v", err)
+	}
+	if len(pages) != 1 || !pages[0].Matches(a5) || !pages[0].Landscape() {
+		t.Errorf("Expected one landscape A5 page from CSS, got %+v", pages)
+	}
+}
@@ -0,0 +1,160 @@
+package html2pdf
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/patipolchat/html2pdf/html2pdf/internal/pdf"
+)
+
+// ErrDuplicatePortfolioName is returned by CreatePortfolio when two inputs
+// would be embedded under the same file name, such as "a" and "a.pdf".
+var ErrDuplicatePortfolioName = fmt.Errorf("duplicate portfolio file name")
+
+// Input is a document to convert. Exactly one of HTML and FileName should be set.
+type Input struct {
+	// HTML is the HTML content to convert.
+	HTML string
+	// FileName is the path of an HTML file to convert.
+	FileName string
+}
+
+// convert converts the input to PDF with the given options.
+func (in Input) convert(ctx context.Context, opts ...Option) ([]byte, error) {
+	if in.FileName != "" {
+		return ConvertHtmlFileToPdf(ctx, in.FileName, opts...)
+	}
+	return ConvertHtmlToPdf(ctx, in.HTML, opts...)
+}
+
+// CreatePortfolio converts each input to PDF and embeds the results, under
+// their map keys, in a PDF portfolio (collection). The portfolio's cover page
+// lists the embedded documents for viewers without portfolio support.
+func CreatePortfolio(ctx context.Context, inputs map[string]Input, opts ...Option) ([]byte, error) {
+	names := make([]string, 0, len(inputs))
+	for name := range inputs {
+		names = append(names, name)
+	}
+	if _, err := portfolioNames(names); err != nil {
+		return nil, err
+	}
+
+	docs := make(map[string][]byte, len(inputs))
+	for name, in := range inputs {
+		b, err := in.convert(ctx, opts...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert %s: %w", name, err)
+		}
+		docs[name] = b
+	}
+	return buildPortfolio(docs)
+}
+
+// portfolioNames sorts names in the order of their name tree keys, which
+// must be in lexical order of the encoded strings, and fails if two of them
+// map to the same file name.
+func portfolioNames(names []string) ([]string, error) {
+	sorted := append([]string(nil), names...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return bytes.Compare(pdf.TextString(portfolioFileName(sorted[i])), pdf.TextString(portfolioFileName(sorted[j]))) < 0
+	})
+	for i := 1; i < len(sorted); i++ {
+		if file := portfolioFileName(sorted[i]); file == portfolioFileName(sorted[i-1]) {
+			a, b := sorted[i-1], sorted[i]
+			if b < a {
+				a, b = b, a
+			}
+			return nil, fmt.Errorf("%w: %q and %q are both embedded as %q", ErrDuplicatePortfolioName, a, b, file)
+		}
+	}
+	return sorted, nil
+}
+
+// buildPortfolio embeds docs in a new PDF collection.
+func buildPortfolio(docs map[string][]byte) ([]byte, error) {
+	names := make([]string, 0, len(docs))
+	for name := range docs {
+		names = append(names, name)
+	}
+	names, err := portfolioNames(names)
+	if err != nil {
+		return nil, err
+	}
+
+	doc := pdf.New()
+	doc.Version = "1.7"
+	var files pdf.Array
+	for _, name := range names {
+		b := docs[name]
+		fileName := portfolioFileName(name)
+		embedded := pdf.Encode(pdf.Dict{
+			"Type":    pdf.Name("EmbeddedFile"),
+			"Subtype": pdf.Name("application/pdf"),
+			"Params":  pdf.Dict{"Size": len(b)},
+		}, b)
+		spec := doc.Add(pdf.Dict{
+			"Type": pdf.Name("Filespec"),
+			"F":    pdf.TextString(fileName),
+			"UF":   pdf.TextString(fileName),
+			"Desc": pdf.TextString(name),
+			"EF":   pdf.Dict{"F": doc.Add(embedded)},
+		})
+		files = append(files, pdf.TextString(fileName), spec)
+	}
+
+	catalog := doc.Catalog()
+	catalog["Names"] = pdf.Dict{"EmbeddedFiles": pdf.Dict{"Names": files}}
+	catalog["Collection"] = pdf.Dict{"Type": pdf.Name("Collection"), "View": pdf.Name("D")}
+	catalog["PageMode"] = pdf.Name("UseAttachments")
+	doc.SetPages([]pdf.Ref{portfolioCover(doc, names)})
+	return doc.Bytes(), nil
+}
+
+// portfolioFileName returns the embedded file name for a portfolio entry.
+func portfolioFileName(name string) string {
+	if strings.HasSuffix(strings.ToLower(name), ".pdf") {
+		return name
+	}
+	return name + ".pdf"
+}
+
+// portfolioCover adds a Letter page listing names and returns it.
+func portfolioCover(doc *pdf.Document, names []string) pdf.Ref {
+	var content bytes.Buffer
+	content.WriteString("BT /F1 16 Tf 72 720 Td ")
+	content.Write(pdf.Serialize(pdf.String("PDF Portfolio")))
+	content.WriteString(" Tj /F1 11 Tf 0 -28 Td ")
+	content.Write(pdf.Serialize(pdf.String(fmt.Sprintf("This document contains %d embedded documents:", len(names)))))
+	content.WriteString(" Tj")
+	for _, name := range names {
+		content.WriteString(" 0 -16 Td ")
+		content.Write(pdf.Serialize(pdf.String("- " + asciiOnly(name))))
+		content.WriteString(" Tj")
+	}
+	content.WriteString(" ET")
+
+	font := doc.Add(pdf.Dict{
+		"Type":     pdf.Name("Font"),
+		"Subtype":  pdf.Name("Type1"),
+		"BaseFont": pdf.Name("Helvetica"),
+	})
+	return doc.Add(pdf.Dict{
+		"Type":      pdf.Name("Page"),
+		"MediaBox":  pdf.Array{0, 0, 612, 792},
+		"Resources": pdf.Dict{"Font": pdf.Dict{"F1": font}},
+		"Contents":  doc.Add(pdf.Encode(nil, content.Bytes())),
+	})
+}
+
+// asciiOnly replaces characters the standard Helvetica font cannot show.
+func asciiOnly(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r < 0x20 || r > 0x7e {
+			return '?'
+		}
+		return r
+	}, s)
+}
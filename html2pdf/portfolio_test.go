@@ -0,0 +1,103 @@
+package html2pdf
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/patipolchat/html2pdf/html2pdf/internal/pdf"
+)
+
+func TestBuildPortfolio(t *testing.T) {
+	invoice, report := testPDF(t, 1), testPDF(t, 2)
+	out, err := buildPortfolio(map[string][]byte{
+		"report":      report,
+		"invoice.pdf": invoice,
+	})
+	if err != nil {
+		t.Fatalf("buildPortfolio() error = %v", err)
+	}
+
+	doc, err := pdf.Parse(out)
+	if err != nil {
+		t.Fatalf("Failed to parse portfolio: %v", err)
+	}
+	catalog := doc.Catalog()
+	if _, ok := doc.Resolve(catalog["Collection"]).(pdf.Dict); !ok {
+		t.Fatalf("Expected a /Collection dictionary in the catalog")
+	}
+	pages, _ := doc.Pages()
+	if len(pages) != 1 {
+		t.Errorf("Expected a single cover page, got %d", len(pages))
+	}
+
+	names, _ := doc.Resolve(catalog["Names"]).(pdf.Dict)
+	tree, _ := doc.Resolve(names["EmbeddedFiles"]).(pdf.Dict)
+	entries, _ := doc.Resolve(tree["Names"]).(pdf.Array)
+	want := []struct {
+		name string
+		data []byte
+	}{
+		{"invoice.pdf", invoice},
+		{"report.pdf", report},
+	}
+	if len(entries) != 2*len(want) {
+		t.Fatalf("Expected %d name tree entries, got %d", 2*len(want), len(entries))
+	}
+	for i, w := range want {
+		if got, _ := entries[2*i].(pdf.String); string(got) != w.name {
+			t.Errorf("Entry %d: expected name %q, got %q", i, w.name, got)
+		}
+		spec, _ := doc.Resolve(entries[2*i+1]).(pdf.Dict)
+		ef, _ := doc.Resolve(spec["EF"]).(pdf.Dict)
+		stream, ok := doc.Resolve(ef["F"]).(*pdf.Stream)
+		if !ok {
+			t.Fatalf("Entry %d: expected an embedded file stream", i)
+		}
+		data, err := pdf.Decode(stream)
+		if err != nil {
+			t.Fatalf("Entry %d: failed to decode embedded file: %v", i, err)
+		}
+		if !bytes.Equal(data, w.data) {
+			t.Errorf("Entry %d: embedded file does not match the input", i)
+		}
+	}
+}
+
+func TestCreatePortfolioMissingFile(t *testing.T) {
+	_, err := CreatePortfolio(context.Background(), map[string]Input{
+		"missing": {FileName: "does-not-exist.html"},
+	})
+	if !errors.Is(err, ErrHTMLFileNotFound) {
+		t.Errorf("Expected ErrHTMLFileNotFound, got %v", err)
+	}
+}
+
+func TestPortfolioNames(t *testing.T) {
+	// "é" is encoded as UTF-16BE, whose 0xFE 0xFF marker sorts after ASCII.
+	got, err := portfolioNames([]string{"zeta", "été", "alpha.pdf", "Beta"})
+	if err != nil {
+		t.Fatalf("portfolioNames() error = %v", err)
+	}
+	want := []string{"Beta", "alpha.pdf", "zeta", "été"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("portfolioNames() = %q, want %q", got, want)
+	}
+	for i := 1; i < len(got); i++ {
+		if bytes.Compare(pdf.TextString(portfolioFileName(got[i-1])), pdf.TextString(portfolioFileName(got[i]))) >= 0 {
+			t.Errorf("Keys %q and %q are not in lexical order", got[i-1], got[i])
+		}
+	}
+}
+
+func TestCreatePortfolioDuplicateNames(t *testing.T) {
+	_, err := CreatePortfolio(context.Background(), map[string]Input{
+		"a":     {HTML: "<p>A</p>"},
+		"a.pdf": {HTML: "<p>Also A</p>"},
+	})
+	if !errors.Is(err, ErrDuplicatePortfolioName) {
+		t.Errorf("Expected ErrDuplicatePortfolioName, got %v", err)
+	}
+}
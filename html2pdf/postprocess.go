@@ -0,0 +1,99 @@
+package html2pdf
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+
+	"github.com/patipolchat/html2pdf/html2pdf/internal/pdf"
+)
+
+// WithTrimBlankPages removes the pages at the end of the document that print
+// nothing visible, such as the blank final page Chrome emits when content
+// ends exactly at a page boundary. Blank pages followed by content, e.g.
+// deliberately empty pages, are kept, as is at least one page.
+func WithTrimBlankPages(enabled bool) Option {
+	return func(o *options) {
+		o.trimBlankPages = enabled
+	}
+}
+
+// postProcess applies the PDF transformations requested in options.
+func postProcess(buf []byte, options *options) ([]byte, error) {
+	var err error
+	if options.trimBlankPages {
+		if buf, err = options.editPDF(buf, trimBlankPages); err != nil {
+			return nil, err
+		}
+	}
+	if options.pageBackground != nil {
+		if buf, err = options.editPDF(buf, addPageBackground(options.pageBackground, options.pageBackgroundFit)); err != nil {
+			return nil, err
+		}
+	}
+	if options.classification != "" && options.classificationPosition != 0 {
+		if buf, err = options.editPDF(buf, addClassification(options.classification, options.classificationPosition)); err != nil {
+			return nil, err
+		}
+	}
+	if !options.retentionExpiry.IsZero() || options.retentionPolicyID != "" {
+		if buf, err = options.editPDF(buf, addRetentionPolicy(options.retentionExpiry, options.retentionPolicyID)); err != nil {
+			return nil, err
+		}
+	}
+	if options.checksumMetadata {
+		return options.editPDF(buf, setChecksumMetadata(buf))
+	}
+	return buf, nil
+}
+
+// addChecksumMetadata records the SHA-256 of buf in the document info dictionary.
+func addChecksumMetadata(buf []byte) ([]byte, error) {
+	return editPDF(buf, setChecksumMetadata(buf))
+}
+
+// setChecksumMetadata returns an edit recording the SHA-256 of buf, taken
+// before the edit, in the document info dictionary.
+func setChecksumMetadata(buf []byte) func(*pdf.Document) error {
+	sum := sha256.Sum256(buf)
+	return func(doc *pdf.Document) error {
+		doc.SetInfo(checksumMetadataKey, hex.EncodeToString(sum[:]))
+		return nil
+	}
+}
+
+// editPDF parses buf, applies fn to the document and serializes the result.
+func editPDF(buf []byte, fn func(*pdf.Document) error) ([]byte, error) {
+	doc, err := pdf.Parse(buf)
+	if err != nil {
+		return nil, err
+	}
+	if err := fn(doc); err != nil {
+		return nil, err
+	}
+	return doc.Bytes(), nil
+}
+
+// trimBlankPages removes the trailing blank pages from doc, keeping at least
+// one page.
+func trimBlankPages(doc *pdf.Document) error {
+	pages, err := doc.Pages()
+	if err != nil {
+		return err
+	}
+	n := len(pages)
+	for n > 1 {
+		blank, err := doc.PageIsBlank(pages[n-1])
+		if err != nil {
+			return err
+		}
+		if !blank {
+			break
+		}
+		n--
+	}
+	if n == len(pages) {
+		return nil
+	}
+	doc.SetPages(pages[:n])
+	return nil
+}
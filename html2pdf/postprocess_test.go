@@ -0,0 +1,79 @@
+package html2pdf
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+
+	"github.com/patipolchat/html2pdf/html2pdf/internal/pdf"
+)
+
+func TestWithChecksumMetadata(t *testing.T) {
+	buf := testPDF(t, 1)
+	opts := getDefaultOptions()
+	WithChecksumMetadata(true)(opts)
+
+	out, err := postProcess(buf, opts)
+	if err != nil {
+		t.Fatalf("postProcess() error = %v", err)
+	}
+	doc, err := pdf.Parse(out)
+	if err != nil {
+		t.Fatalf("Failed to parse output: %v", err)
+	}
+
+	sum := sha256.Sum256(buf)
+	got, _ := doc.Info()[checksumMetadataKey].(pdf.String)
+	if string(got) != hex.EncodeToString(sum[:]) {
+		t.Errorf("Expected embedded checksum %x, got %q", sum, got)
+	}
+}
+
+func TestPostProcessWithoutOptions(t *testing.T) {
+	buf := testPDF(t, 1)
+	out, err := postProcess(buf, getDefaultOptions())
+	if err != nil {
+		t.Fatalf("postProcess() error = %v", err)
+	}
+	if string(out) != string(buf) {
+		t.Error("postProcess() should not modify the PDF without options")
+	}
+}
+
+func TestWithTrimBlankPages(t *testing.T) {
+	text := "BT /F1 12 Tf 72 720 Td (Content) Tj ET"
+	blank := "q 1 0 0 -1 0 792 cm Q"
+
+	tests := []struct {
+		name      string
+		contents  []string
+		wantPages int
+	}{
+		{name: "trailing blank page", contents: []string{text, text, blank}, wantPages: 2},
+		{name: "several trailing blank pages", contents: []string{text, blank, blank}, wantPages: 1},
+		{name: "blank page in the middle", contents: []string{text, blank, text}, wantPages: 3},
+		{name: "blank pages in the middle and at the end", contents: []string{text, blank, text, blank}, wantPages: 3},
+		{name: "no blank pages", contents: []string{text, text}, wantPages: 2},
+		{name: "only blank pages", contents: []string{blank, blank}, wantPages: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := getDefaultOptions()
+			WithTrimBlankPages(true)(opts)
+
+			out, err := postProcess(testPDFWithContents(t, tt.contents...), opts)
+			if err != nil {
+				t.Fatalf("postProcess() error = %v", err)
+			}
+			doc, err := pdf.Parse(out)
+			if err != nil {
+				t.Fatalf("Failed to parse output: %v", err)
+			}
+			pages, _ := doc.Pages()
+			if len(pages) != tt.wantPages {
+				t.Errorf("Expected %d pages, got %d", tt.wantPages, len(pages))
+			}
+		})
+	}
+}
@@ -0,0 +1,89 @@
+package html2pdf
+
+import (
+	"github.com/patipolchat/html2pdf/html2pdf/internal/pdf"
+)
+
+// Score deductions for the problems a QualityReport detects. Repeated
+// problems of one kind are capped so a single issue cannot zero the score.
+const (
+	overflowPenalty        = 30
+	missingImagePenalty    = 10
+	maxMissingImagePenalty = 30
+	fontPenalty            = 5
+	maxFontPenalty         = 20
+	blankPagePenalty       = 10
+	maxBlankPagePenalty    = 20
+)
+
+// missingImagesScript returns the distinct URLs of images that did not load.
+const missingImagesScript = `[...new Set(Array.from(document.images)
+	.filter((img) => (img.currentSrc || img.src) && (!img.complete || img.naturalWidth === 0))
+	.map((img) => img.currentSrc || img.src))]`
+
+// QualityReport scores how likely a document is to look as intended, so
+// pipelines can hold back suspicious documents for human review.
+type QualityReport struct {
+	// Score runs from 100, when no problems were detected, down to 0.
+	Score int
+	// Overflow lists selectors of elements wider than the printable area.
+	Overflow []string
+	// MissingImages lists the URLs of images that failed to load.
+	MissingImages []string
+	// MissingFonts lists requested font families that rendered no text,
+	// e.g. web fonts that failed to load.
+	MissingFonts []string
+	// FallbackFonts lists text rendered with a substitute font.
+	FallbackFonts []FontFallback
+	// BlankPages lists the 1-based numbers of pages that print nothing.
+	BlankPages []int
+}
+
+// WithQualityReport fills Result.Quality with a heuristic score based on
+// overflowing content, missing images, missing or substituted fonts and
+// blank pages.
+func WithQualityReport(enabled bool) Option {
+	return func(o *options) {
+		o.qualityReport = enabled
+	}
+}
+
+// addFonts records the font problems of a font report.
+func (q *QualityReport) addFonts(fonts *FontReport) {
+	q.MissingFonts = fonts.Unused
+	q.FallbackFonts = fonts.Fallbacks
+}
+
+// addBlankPages records the blank pages of buf.
+func (q *QualityReport) addBlankPages(buf []byte) error {
+	doc, err := pdf.Parse(buf)
+	if err != nil {
+		return err
+	}
+	pages, err := doc.Pages()
+	if err != nil {
+		return err
+	}
+	for i, p := range pages {
+		blank, err := doc.PageIsBlank(p)
+		if err != nil {
+			return err
+		}
+		if blank {
+			q.BlankPages = append(q.BlankPages, i+1)
+		}
+	}
+	return nil
+}
+
+// score computes Score from the detected problems.
+func (q *QualityReport) score() {
+	penalty := 0
+	if len(q.Overflow) > 0 {
+		penalty += overflowPenalty
+	}
+	penalty += min(len(q.MissingImages)*missingImagePenalty, maxMissingImagePenalty)
+	penalty += min((len(q.MissingFonts)+len(q.FallbackFonts))*fontPenalty, maxFontPenalty)
+	penalty += min(len(q.BlankPages)*blankPagePenalty, maxBlankPagePenalty)
+	q.Score = max(0, 100-penalty)
+}
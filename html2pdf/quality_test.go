@@ -0,0 +1,101 @@
+package html2pdf
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestQualityReportScore(t *testing.T) {
+	tests := []struct {
+		name   string
+		report QualityReport
+		want   int
+	}{
+		{name: "clean", want: 100},
+		{name: "overflow", report: QualityReport{Overflow: []string{"table", "pre"}}, want: 70},
+		{name: "missing image", report: QualityReport{MissingImages: []string{"a.png"}}, want: 90},
+		{name: "missing images are capped", report: QualityReport{MissingImages: []string{"a", "b", "c", "d", "e"}}, want: 70},
+		{name: "fonts", report: QualityReport{MissingFonts: []string{"Brand"}, FallbackFonts: []FontFallback{{Requested: "Arial", Used: "Noto Sans Thai"}}}, want: 90},
+		{name: "blank pages", report: QualityReport{BlankPages: []int{2, 3, 4}}, want: 80},
+		{
+			name: "everything",
+			report: QualityReport{
+				Overflow:      []string{"table"},
+				MissingImages: []string{"a", "b", "c"},
+				MissingFonts:  []string{"A", "B", "C", "D"},
+				BlankPages:    []int{1, 2},
+			},
+			want: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.report.score()
+			if tt.report.Score != tt.want {
+				t.Errorf("Expected score %d, got %d", tt.want, tt.report.Score)
+			}
+		})
+	}
+}
+
+func TestQualityReportAddBlankPages(t *testing.T) {
+	text := "BT /F1 12 Tf 72 720 Td (Content) Tj ET"
+	blank := "q 1 1 1 rg 0 0 612 792 re f Q"
+
+	var q QualityReport
+	if err := q.addBlankPages(testPDFWithContents(t, text, blank, text, blank)); err != nil {
+		t.Fatalf("addBlankPages() error = %v", err)
+	}
+	if want := []int{2, 4}; !reflect.DeepEqual(q.BlankPages, want) {
+		t.Errorf("Expected blank pages %v, got %v", want, q.BlankPages)
+	}
+
+	if err := q.addBlankPages([]byte("not a pdf")); err == nil {
+		t.Error("Expected an error for invalid PDF data")
+	}
+}
+
+func TestWithQualityReport(t *testing.T) {
+	opts := newOptions([]Option{WithQualityReport(true)})
+	if !opts.qualityReport {
+		t.Error("Expected the quality report to be enabled")
+	}
+	if opts.fingerprint() == getDefaultOptions().fingerprint() {
+		t.Error("Expected WithQualityReport to change the options fingerprint")
+	}
+}
+
+func TestConvertHtmlToResultWithQualityReport(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	defer srv.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	html := fmt.Sprintf(`<html><body><img src="%s/logo.png"><div id="wide" style="width: 3000px">Wide</div></body></html>`, srv.URL)
+	res, err := ConvertHtmlToResult(ctx, html, WithQualityReport(true))
+	if err != nil {
+		t.Fatalf("ConvertHtmlToResult() error = %v", err)
+	}
+	if res.Quality == nil {
+		t.Fatal("Expected a quality report")
+	}
+	if res.FontReport != nil {
+		t.Error("Expected no font report without WithFontReport")
+	}
+	if want := []string{srv.URL + "/logo.png"}; !reflect.DeepEqual(res.Quality.MissingImages, want) {
+		t.Errorf("Expected missing images %v, got %v", want, res.Quality.MissingImages)
+	}
+	if want := []string{"#wide"}; !reflect.DeepEqual(res.Quality.Overflow, want) {
+		t.Errorf("Expected overflow %v, got %v", want, res.Quality.Overflow)
+	}
+	if res.Quality.Score > 60 {
+		t.Errorf("Expected score at most 60, got %d", res.Quality.Score)
+	}
+}
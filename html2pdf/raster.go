@@ -0,0 +1,467 @@
+package html2pdf
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"math"
+	"runtime"
+	"sort"
+	"sync"
+
+	"github.com/chromedp/cdproto/emulation"
+	"github.com/chromedp/cdproto/page"
+	"github.com/chromedp/chromedp"
+	"github.com/patipolchat/html2pdf/html2pdf/internal/pdf"
+)
+
+const (
+	// rasterImageName is the resource name of a rasterized page image.
+	rasterImageName = "Html2pdfPage"
+	// rasterFontName is the resource name of the font of the text layer.
+	rasterFontName = "Html2pdfText"
+)
+
+// ErrRasterUnsupported is returned when WithRasterizeOutput is combined with
+// a print option that only applies to pages printed by Chrome.
+var ErrRasterUnsupported = fmt.Errorf("option not supported with rasterized output")
+
+// WithRasterizeOutput produces an image-only PDF with no extractable text,
+// rendering each page at dpi dots per inch, for documents where copying or
+// text extraction must be prevented. The page is laid out at the printable
+// width and cut into pages of the printable height at forced page breaks and
+// between lines of text. The print scale applies; header and footer
+// templates, page ranges, section headers and CSS page sizes fail with
+// ErrRasterUnsupported. A zero dpi disables rasterizing.
+func WithRasterizeOutput(dpi int) Option {
+	return func(o *options) {
+		o.rasterDPI = dpi
+		o.rasterText = false
+	}
+}
+
+// WithSearchableRaster rasterizes pages like WithRasterizeOutput but adds an
+// invisible text layer at the positions of the HTML text, so archives of
+// image-based pages stay searchable. The text layer uses a standard font and
+// only holds Latin-1 characters.
+func WithSearchableRaster(dpi int) Option {
+	return func(o *options) {
+		o.rasterDPI = dpi
+		o.rasterText = dpi > 0
+	}
+}
+
+// validateRaster rejects print parameters that only Chrome's own printing
+// applies, so they are not silently dropped from rasterized output.
+func (o *options) validateRaster() error {
+	params := o.printToPDFParams()
+	switch {
+	case params.DisplayHeaderFooter:
+		return fmt.Errorf("%w: header and footer templates", ErrRasterUnsupported)
+	case params.PageRanges != "":
+		return fmt.Errorf("%w: page ranges", ErrRasterUnsupported)
+	case len(o.sections) > 0:
+		return fmt.Errorf("%w: section headers", ErrRasterUnsupported)
+	case params.PreferCSSPageSize:
+		return fmt.Errorf("%w: CSS page size", ErrRasterUnsupported)
+	}
+	return nil
+}
+
+// rasterLayout describes how rasterized pages are placed on the paper, in
+// inches, and the print scale of the content.
+type rasterLayout struct {
+	paperW, paperH         float64
+	left, top              float64
+	printableW, printableH float64
+	scale                  float64
+}
+
+// newRasterLayout returns the layout for params.
+func newRasterLayout(params *page.PrintToPDFParams) rasterLayout {
+	w, h := paperDimensions(params)
+	return rasterLayout{
+		paperW:     w,
+		paperH:     h,
+		left:       params.MarginLeft,
+		top:        params.MarginTop,
+		printableW: w - params.MarginLeft - params.MarginRight,
+		printableH: h - params.MarginTop - params.MarginBottom,
+		scale:      printScale(params),
+	}
+}
+
+// pageWidthPx and pageHeightPx return the printable area in CSS pixels of
+// the scaled content.
+func (l rasterLayout) pageWidthPx() float64  { return l.printableW * cssPixelsPerInch / l.scale }
+func (l rasterLayout) pageHeightPx() float64 { return l.printableH * cssPixelsPerInch / l.scale }
+
+// pointsPerPx returns the size on paper of a CSS pixel of the content.
+func (l rasterLayout) pointsPerPx() float64 {
+	return float64(pointsPerInch) / cssPixelsPerInch * l.scale
+}
+
+// rasterSlice is the part of the laid-out content shown on one rasterized
+// page, in CSS pixels from the top of the document.
+type rasterSlice struct {
+	top, bottom float64
+}
+
+// printRaster screenshots the page in slices that fit the printable area and
+// returns them as an image-only PDF.
+func printRaster(ctx context.Context, params *page.PrintToPDFParams, dpi int, searchable bool) ([]byte, error) {
+	layout := newRasterLayout(params)
+	if layout.printableW <= 0 || layout.printableH <= 0 {
+		return nil, fmt.Errorf("%w: no printable area", ErrInvalidPaperSize)
+	}
+	slices, capture, err := prepareRasterCapture(ctx, layout, dpi)
+	if err != nil {
+		return nil, err
+	}
+	images, err := encodeRasterPages(len(slices), rasterWorkers(), capture)
+	if err != nil {
+		return nil, err
+	}
+	var overlays [][]byte
+	if searchable {
+		boxes, err := collectTextBoxes(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to collect text positions: %w", err)
+		}
+		overlays = textOverlays(boxes, layout, slices)
+	}
+	return buildRasterPDF(images, layout, slices, overlays), nil
+}
+
+// rasterBreaksScript returns the positions of forced page breaks, like
+// layoutScript, and the vertical extent of every line of text and of every
+// element that should not be cut: replaced elements and those with
+// break-inside: avoid.
+const rasterBreaksScript = `(() => {
+	const forced = (v) => ["page", "always", "left", "right", "recto", "verso"].includes(v);
+	const avoid = (v) => ["avoid", "avoid-page"].includes(v);
+	const breaks = [], blocks = [];
+	const root = document.body || document.documentElement;
+	for (const el of root.querySelectorAll("*")) {
+		const style = getComputedStyle(el);
+		if (style.display === "none") {
+			continue;
+		}
+		const rect = el.getBoundingClientRect();
+		const top = rect.top + window.scrollY, bottom = rect.bottom + window.scrollY;
+		if (forced(style.breakBefore) || forced(style.pageBreakBefore)) {
+			breaks.push(top);
+		}
+		if (forced(style.breakAfter) || forced(style.pageBreakAfter)) {
+			breaks.push(bottom);
+		}
+		if (el.matches("img, svg, canvas, video, iframe, object, embed") || avoid(style.breakInside) || avoid(style.pageBreakInside)) {
+			blocks.push([top, bottom]);
+		}
+	}
+	const walker = document.createTreeWalker(root, NodeFilter.SHOW_TEXT);
+	const range = document.createRange();
+	while (walker.nextNode()) {
+		range.selectNodeContents(walker.currentNode);
+		for (const rect of range.getClientRects()) {
+			if (rect.height > 0) {
+				blocks.push([rect.top + window.scrollY, rect.bottom + window.scrollY]);
+			}
+		}
+	}
+	return {breaks, blocks};
+})()`
+
+// rasterBreaks is the result of rasterBreaksScript.
+type rasterBreaks struct {
+	Breaks []float64    `json:"breaks"`
+	Blocks [][2]float64 `json:"blocks"`
+}
+
+// prepareRasterCapture lays the page out at the printable width, decides
+// where pages end, and returns the slices with a function capturing slice i
+// as PNG.
+func prepareRasterCapture(ctx context.Context, layout rasterLayout, dpi int) ([]rasterSlice, func(i int) ([]byte, error), error) {
+	widthPx, heightPx := layout.pageWidthPx(), layout.pageHeightPx()
+	_, contentH, err := measureContent(ctx, widthPx)
+	if err != nil {
+		return nil, nil, err
+	}
+	var breaks rasterBreaks
+	if err := chromedp.Evaluate(rasterBreaksScript, &breaks).Do(ctx); err != nil {
+		return nil, nil, err
+	}
+	// Device pixels per CSS pixel, so the scaled content is captured at dpi.
+	scale := float64(dpi) / cssPixelsPerInch * layout.scale
+	if err := emulation.SetDeviceMetricsOverride(int64(math.Round(widthPx)), int64(math.Round(heightPx)), scale, false).Do(ctx); err != nil {
+		return nil, nil, err
+	}
+
+	slices := rasterSlices(contentH, heightPx, breaks.Breaks, breaks.Blocks)
+	capture := func(i int) ([]byte, error) {
+		s := slices[i]
+		img, err := page.CaptureScreenshot().
+			WithFormat(page.CaptureScreenshotFormatPng).
+			WithCaptureBeyondViewport(true).
+			WithClip(&page.Viewport{X: 0, Y: s.top, Width: widthPx, Height: s.bottom - s.top, Scale: 1}).
+			Do(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to capture page %d: %w", i+1, err)
+		}
+		return img, nil
+	}
+	return slices, capture, nil
+}
+
+// rasterSlices cuts content of height contentH into pages of at most pageH
+// CSS pixels. A page ends at the first forced break on it; otherwise it ends
+// above the first block, such as a line of text, that its bottom edge would
+// cut through. Blocks taller than a page are cut where the page ends.
+func rasterSlices(contentH, pageH float64, breaks []float64, blocks [][2]float64) []rasterSlice {
+	sort.Float64s(breaks)
+	var slices []rasterSlice
+	top := 0.0
+	for {
+		bottom := top + pageH
+		forced := false
+		for _, b := range breaks {
+			// A pixel of slack keeps breaks at the edges from adding empty pages.
+			if b > top+1 && b <= bottom && b < contentH-1 {
+				bottom, forced = b, true
+				break
+			}
+		}
+		if !forced && bottom >= contentH-1 {
+			return append(slices, rasterSlice{top: top, bottom: math.Max(top+1, math.Min(bottom, contentH))})
+		}
+		for moved := !forced; moved; {
+			moved = false
+			for _, b := range blocks {
+				if b[0] > top && b[0] < bottom && b[1] > bottom && b[1]-b[0] <= pageH {
+					bottom, moved = b[0], true
+				}
+			}
+		}
+		slices = append(slices, rasterSlice{top: top, bottom: bottom})
+		top = bottom
+	}
+}
+
+// rasterWorkers returns how many pages are encoded concurrently: one per
+// CPU available to the process.
+func rasterWorkers() int {
+	n := runtime.GOMAXPROCS(0)
+	if cpus := DetectResourceLimits().CPUs; cpus > 0 && int(math.Ceil(cpus)) < n {
+		n = int(math.Ceil(cpus))
+	}
+	if n < 1 {
+		n = 1
+	}
+	return n
+}
+
+// encodeRasterPages captures n pages one after another and encodes them as
+// image XObjects on up to workers goroutines; only the encoding runs in
+// parallel. Capturing pauses while a screenshot already waits for every
+// worker, which bounds the backlog of PNGs, but every encoded image is kept
+// in memory until the whole document is built.
+func encodeRasterPages(n, workers int, capture func(i int) ([]byte, error)) ([]*pdf.Image, error) {
+	type job struct {
+		i    int
+		data []byte
+	}
+	images := make([]*pdf.Image, n)
+	jobs := make(chan job, workers)
+	failed := make(chan struct{})
+	var (
+		wg       sync.WaitGroup
+		once     sync.Once
+		firstErr error
+	)
+	fail := func(err error) {
+		once.Do(func() {
+			firstErr = err
+			close(failed)
+		})
+	}
+
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range jobs {
+				img, err := pdf.NewImage(j.data)
+				if err != nil {
+					fail(fmt.Errorf("failed to encode page %d: %w", j.i+1, err))
+					continue
+				}
+				images[j.i] = img
+			}
+		}()
+	}
+
+capture:
+	for i := 0; i < n; i++ {
+		data, err := capture(i)
+		if err != nil {
+			fail(err)
+			break
+		}
+		select {
+		case jobs <- job{i, data}:
+		case <-failed:
+			break capture
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	return images, nil
+}
+
+// buildRasterPDF places the image of each slice at the top of the printable
+// area of layout. overlays, when not nil, holds extra content drawn on each
+// page, which may use the standard font rasterFontName.
+func buildRasterPDF(images []*pdf.Image, layout rasterLayout, slices []rasterSlice, overlays [][]byte) []byte {
+	doc := pdf.New()
+	mediaBox := pdf.Box{URX: layout.paperW * pointsPerInch, URY: layout.paperH * pointsPerInch}
+
+	var font pdf.Ref
+	if overlays != nil {
+		font = doc.Add(pdf.Dict{
+			"Type":     pdf.Name("Font"),
+			"Subtype":  pdf.Name("Type1"),
+			"BaseFont": pdf.Name("Helvetica"),
+			"Encoding": pdf.Name("WinAnsiEncoding"),
+		})
+	}
+
+	pages := make([]pdf.Ref, 0, len(images))
+	for i, image := range images {
+		img := doc.AddImageObject(image)
+		h := (slices[i].bottom - slices[i].top) * layout.pointsPerPx()
+		m := pdf.Scale(layout.printableW*pointsPerInch, round2(h)).
+			Then(pdf.Translate(layout.left*pointsPerInch, round2((layout.paperH-layout.top)*pointsPerInch-h)))
+		content := []byte(fmt.Sprintf("q %s cm /%s Do Q\n", m, rasterImageName))
+		if i < len(overlays) {
+			content = append(content, overlays[i]...)
+		}
+		resources := pdf.Dict{"XObject": pdf.Dict{rasterImageName: img}}
+		if overlays != nil {
+			resources["Font"] = pdf.Dict{rasterFontName: font}
+		}
+		pages = append(pages, doc.Add(pdf.Dict{
+			"Type":      pdf.Name("Page"),
+			"MediaBox":  mediaBox.Array(),
+			"Resources": resources,
+			"Contents":  doc.Add(pdf.Encode(nil, content)),
+		}))
+	}
+	doc.SetPages(pages)
+	return doc.Bytes()
+}
+
+// textBox is a word and its position in the document, in CSS pixels.
+type textBox struct {
+	Text   string  `json:"text"`
+	X      float64 `json:"x"`
+	Y      float64 `json:"y"`
+	Width  float64 `json:"width"`
+	Height float64 `json:"height"`
+}
+
+// textBoxesScript returns the position of every visible word on the page.
+const textBoxesScript = `(() => {
+	const boxes = [];
+	const walker = document.createTreeWalker(document.body || document.documentElement, NodeFilter.SHOW_TEXT);
+	const range = document.createRange();
+	while (walker.nextNode()) {
+		const node = walker.currentNode;
+		const parent = node.parentElement;
+		if (parent && getComputedStyle(parent).visibility !== "visible") {
+			continue;
+		}
+		for (const match of node.data.matchAll(/\S+/g)) {
+			range.setStart(node, match.index);
+			range.setEnd(node, match.index + match[0].length);
+			for (const rect of range.getClientRects()) {
+				if (rect.width > 0 && rect.height > 0) {
+					boxes.push({
+						text: match[0],
+						x: rect.left + window.scrollX,
+						y: rect.top + window.scrollY,
+						width: rect.width,
+						height: rect.height,
+					});
+					break;
+				}
+			}
+		}
+	}
+	return boxes;
+})()`
+
+// collectTextBoxes returns the words on the page with their positions.
+func collectTextBoxes(ctx context.Context) ([]textBox, error) {
+	var boxes []textBox
+	if err := chromedp.Evaluate(textBoxesScript, &boxes).Do(ctx); err != nil {
+		return nil, err
+	}
+	return boxes, nil
+}
+
+// textOverlays returns, for each slice, content showing the words of boxes
+// that start on it in invisible text (render mode 3).
+func textOverlays(boxes []textBox, layout rasterLayout, slices []rasterSlice) [][]byte {
+	ptPerPx := layout.pointsPerPx()
+
+	overlays := make([][]byte, len(slices))
+	for _, b := range boxes {
+		i := sort.Search(len(slices), func(i int) bool { return slices[i].bottom > b.Y })
+		if i == len(slices) || b.Y < slices[i].top {
+			continue
+		}
+		text := latin1(b.Text)
+		size := b.Height * ptPerPx * 0.8
+		// Helvetica averages about half an em per character; scale the
+		// text horizontally so selections match the rendered word.
+		scale := 100 * b.Width * ptPerPx / (0.5 * size * float64(len(text)))
+		x := layout.left*pointsPerInch + b.X*ptPerPx
+		baseline := b.Y - slices[i].top + b.Height*0.8
+		y := (layout.paperH-layout.top)*pointsPerInch - baseline*ptPerPx
+
+		var buf bytes.Buffer
+		fmt.Fprintf(&buf, "BT 3 Tr /%s %s Tf %s Tz 1 0 0 1 %s %s Tm ",
+			rasterFontName, formatNumber(round2(size)), formatNumber(round2(scale)),
+			formatNumber(round2(x)), formatNumber(round2(y)))
+		buf.Write(pdf.Serialize(pdf.String(text)))
+		buf.WriteString(" Tj ET\n")
+		overlays[i] = append(overlays[i], buf.Bytes()...)
+	}
+	for i := range overlays {
+		if overlays[i] == nil {
+			overlays[i] = []byte{}
+		}
+	}
+	return overlays
+}
+
+// latin1 encodes s for a standard font, replacing characters outside Latin-1.
+func latin1(s string) []byte {
+	out := make([]byte, 0, len(s))
+	for _, r := range s {
+		if r > 0xff {
+			r = '?'
+		}
+		out = append(out, byte(r))
+	}
+	return out
+}
+
+// round2 rounds v to two decimal places.
+func round2(v float64) float64 {
+	return math.Round(v*100) / 100
+}
@@ -0,0 +1,218 @@
+package html2pdf
+
+import (
+	"context"
+	"errors"
+	"math"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/chromedp/cdproto/page"
+	"github.com/patipolchat/html2pdf/html2pdf/internal/pdf"
+)
+
+func TestNewRasterLayout(t *testing.T) {
+	params := page.PrintToPDF().WithPaperWidth(8).WithPaperHeight(10).
+		WithMarginLeft(0.5).WithMarginRight(0.25).WithMarginTop(1).WithMarginBottom(2)
+	got := newRasterLayout(params)
+	want := rasterLayout{paperW: 8, paperH: 10, left: 0.5, top: 1, printableW: 7.25, printableH: 7, scale: 1}
+	if got != want {
+		t.Errorf("Expected %+v, got %+v", want, got)
+	}
+}
+
+func TestBuildRasterPDF(t *testing.T) {
+	layout := rasterLayout{paperW: 8, paperH: 10, left: 0.5, top: 1, printableW: 7, printableH: 8, scale: 1}
+	png := testPNG(t, 70, 80)
+	images, err := encodeRasterPages(2, 1, func(int) ([]byte, error) { return png, nil })
+	if err != nil {
+		t.Fatalf("encodeRasterPages() error = %v", err)
+	}
+	// A full page of 8in at 96 CSS pixels per inch, then a half page.
+	slices := []rasterSlice{{top: 0, bottom: 768}, {top: 768, bottom: 1152}}
+	out := buildRasterPDF(images, layout, slices, [][]byte{[]byte("% overlay\n")})
+	doc, err := pdf.Parse(out)
+	if err != nil {
+		t.Fatalf("Failed to parse output: %v", err)
+	}
+	pages, _ := doc.Pages()
+	if len(pages) != 2 {
+		t.Fatalf("Expected 2 pages, got %d", len(pages))
+	}
+	placements := []string{"504 0 0 576 36 72 cm", "504 0 0 288 36 360 cm"}
+	for i, p := range pages {
+		box := doc.PageBox(p, "MediaBox")
+		if math.Abs(box.Width()-576) > 0.01 || math.Abs(box.Height()-720) > 0.01 {
+			t.Errorf("Page %d: expected 576x720pt, got %+v", i+1, box)
+		}
+		content, _ := doc.PageContent(p)
+		if !strings.Contains(string(content), placements[i]+" /"+rasterImageName+" Do") {
+			t.Errorf("Page %d: unexpected image placement %q", i+1, content)
+		}
+		if got := strings.Contains(string(content), "% overlay"); got != (i == 0) {
+			t.Errorf("Page %d: expected overlay %v, got %v", i+1, i == 0, got)
+		}
+	}
+}
+
+func TestEncodeRasterPages(t *testing.T) {
+	pngs := [][]byte{testPNG(t, 1, 1), testPNG(t, 2, 1), testPNG(t, 3, 1), testPNG(t, 4, 1), testPNG(t, 5, 1)}
+	var captured int32
+	images, err := encodeRasterPages(len(pngs), 3, func(i int) ([]byte, error) {
+		atomic.AddInt32(&captured, 1)
+		return pngs[i], nil
+	})
+	if err != nil {
+		t.Fatalf("encodeRasterPages() error = %v", err)
+	}
+	for i, img := range images {
+		if img == nil || img.Width != i+1 {
+			t.Errorf("Page %d: expected %d pixel wide image in order, got %+v", i+1, i+1, img)
+		}
+	}
+
+	captured = 0
+	_, err = encodeRasterPages(100, 2, func(i int) ([]byte, error) {
+		atomic.AddInt32(&captured, 1)
+		return []byte("bad"), nil
+	})
+	if err == nil {
+		t.Fatal("Expected an error for invalid image data")
+	}
+	if n := atomic.LoadInt32(&captured); n == 100 {
+		t.Errorf("Expected capturing to stop after the first failure, captured %d pages", n)
+	}
+
+	captureErr := errors.New("capture failed")
+	if _, err := encodeRasterPages(3, 2, func(int) ([]byte, error) { return nil, captureErr }); !errors.Is(err, captureErr) {
+		t.Errorf("Expected capture error, got %v", err)
+	}
+}
+
+func TestTextOverlays(t *testing.T) {
+	// Printable area of 1 x 1 in, i.e. 96 CSS pixels per page.
+	layout := rasterLayout{paperW: 2, paperH: 2, left: 0.5, top: 0.5, printableW: 1, printableH: 1, scale: 1}
+	boxes := []textBox{
+		{Text: "Invoice", X: 0, Y: 0, Width: 42, Height: 20},
+		{Text: "Café€", X: 10, Y: 100, Width: 30, Height: 10},
+		{Text: "lost", X: 0, Y: 500, Width: 20, Height: 10},
+	}
+	// The first page ends early, above a line that would have been cut.
+	overlays := textOverlays(boxes, layout, []rasterSlice{{top: 0, bottom: 90}, {top: 90, bottom: 186}})
+	if len(overlays) != 2 {
+		t.Fatalf("Expected 2 overlays, got %d", len(overlays))
+	}
+
+	first := string(overlays[0])
+	if !strings.Contains(first, "3 Tr /"+rasterFontName+" 12 Tf 75 Tz 1 0 0 1 36 96 Tm (Invoice) Tj") {
+		t.Errorf("Unexpected first page overlay %q", first)
+	}
+	second := string(overlays[1])
+	if !strings.Contains(second, `(Caf\351?) Tj`) || !strings.Contains(second, "1 0 0 1 43.5 94.5 Tm") {
+		t.Errorf("Unexpected second page overlay %q", second)
+	}
+	if strings.Contains(first+second, "lost") {
+		t.Error("Expected words beyond the last page to be dropped")
+	}
+}
+
+func TestWithSearchableRaster(t *testing.T) {
+	o := newOptions([]Option{WithSearchableRaster(150)})
+	if o.rasterDPI != 150 || !o.rasterText {
+		t.Errorf("Expected searchable raster at 150 dpi, got dpi=%d text=%v", o.rasterDPI, o.rasterText)
+	}
+	o = newOptions([]Option{WithSearchableRaster(150), WithRasterizeOutput(100)})
+	if o.rasterDPI != 100 || o.rasterText {
+		t.Errorf("Expected image-only raster at 100 dpi, got dpi=%d text=%v", o.rasterDPI, o.rasterText)
+	}
+}
+
+func TestRasterSlices(t *testing.T) {
+	tests := []struct {
+		name     string
+		contentH float64
+		breaks   []float64
+		blocks   [][2]float64
+		want     []rasterSlice
+	}{
+		{name: "empty", contentH: 0, want: []rasterSlice{{0, 1}}},
+		{name: "one page", contentH: 80, want: []rasterSlice{{0, 80}}},
+		{name: "exact pages", contentH: 200, want: []rasterSlice{{0, 100}, {100, 200}}},
+		{name: "fixed height", contentH: 250, want: []rasterSlice{{0, 100}, {100, 200}, {200, 250}}},
+		{
+			name:     "line on page edge",
+			contentH: 250,
+			blocks:   [][2]float64{{80, 95}, {95, 110}, {110, 125}},
+			want:     []rasterSlice{{0, 95}, {95, 195}, {195, 250}},
+		},
+		{
+			name:     "nested blocks",
+			contentH: 150,
+			blocks:   [][2]float64{{60, 105}, {90, 102}},
+			want:     []rasterSlice{{0, 60}, {60, 150}},
+		},
+		{name: "block taller than a page", contentH: 250, blocks: [][2]float64{{50, 200}}, want: []rasterSlice{{0, 100}, {100, 200}, {200, 250}}},
+		{
+			name:     "forced breaks",
+			contentH: 250,
+			breaks:   []float64{130, 40, 0, 250},
+			blocks:   [][2]float64{{30, 50}},
+			want:     []rasterSlice{{0, 40}, {40, 130}, {130, 230}, {230, 250}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := rasterSlices(tt.contentH, 100, tt.breaks, tt.blocks)
+			if len(got) != len(tt.want) {
+				t.Fatalf("rasterSlices() = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("rasterSlices() = %v, want %v", got, tt.want)
+					break
+				}
+			}
+		})
+	}
+}
+
+func TestRasterLayoutScale(t *testing.T) {
+	params := page.PrintToPDF().WithPaperWidth(8).WithPaperHeight(10).WithScale(0.5)
+	layout := newRasterLayout(params)
+	if layout.pageWidthPx() != 1536 || layout.pageHeightPx() != 1920 {
+		t.Errorf("Expected a 1536x1920px page at half scale, got %gx%g", layout.pageWidthPx(), layout.pageHeightPx())
+	}
+	if layout.pointsPerPx() != 0.375 {
+		t.Errorf("Expected 0.375pt per CSS pixel, got %g", layout.pointsPerPx())
+	}
+}
+
+func TestValidateRaster(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    []Option
+		wantErr bool
+	}{
+		{name: "plain"},
+		{name: "scale and margins", opts: []Option{WithScale(0.8), WithMargins(1, 1, 1, 1), WithPaperSize(A4)}},
+		{name: "header", opts: []Option{WithHeaderTemplate("<span class=pageNumber></span>")}, wantErr: true},
+		{name: "page ranges", opts: []Option{WithPageRanges("1-2")}, wantErr: true},
+		{name: "sections", opts: []Option{WithSectionHeaders(map[PageRange]HeaderFooter{{From: 1, To: 1}: {Header: "A"}})}, wantErr: true},
+		{name: "CSS page size", opts: []Option{WithPreferCSSPageSize(true)}, wantErr: true},
+		{name: "raw params", opts: []Option{WithPrintToPDFParams(func(p *page.PrintToPDFParams) { p.DisplayHeaderFooter = true })}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := newOptions(append(tt.opts, WithRasterizeOutput(150))).validateRaster()
+			if tt.wantErr != errors.Is(err, ErrRasterUnsupported) || (!tt.wantErr && err != nil) {
+				t.Errorf("validateRaster() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	_, err := ConvertHtmlToPdf(context.Background(), "<p>x</p>", WithRasterizeOutput(150), WithPageRanges("1"))
+	if !errors.Is(err, ErrRasterUnsupported) {
+		t.Errorf("Expected ErrRasterUnsupported before rendering, got %v", err)
+	}
+}
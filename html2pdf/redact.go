@@ -0,0 +1,68 @@
+package html2pdf
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/chromedp/chromedp"
+)
+
+// redactScript replaces the elements matching the selectors in its argument
+// with empty black boxes of the same size. Replacing the element rather than
+// hiding its content also drops its ::before and ::after content, attributes
+// Chrome can turn into PDF content (links, alt text, titles), form values,
+// and the pixels of a matched <canvas>, <img> or <iframe>.
+const redactScript = `((selectors) => {
+	const style = document.createElement("style");
+	style.textContent = "html2pdf-redacted::before, html2pdf-redacted::after { content: none !important; }";
+	document.documentElement.appendChild(style);
+	const layout = ["position", "top", "right", "bottom", "left", "float", "clear", "vertical-align",
+		"margin-top", "margin-right", "margin-bottom", "margin-left", "flex", "align-self", "grid-area"];
+	const redact = (el) => {
+		if (!el.isConnected || el === document.documentElement || el === document.body) {
+			return;
+		}
+		const computed = getComputedStyle(el);
+		const rect = el.getBoundingClientRect();
+		const box = document.createElement("html2pdf-redacted");
+		for (const prop of layout) {
+			box.style.setProperty(prop, computed.getPropertyValue(prop));
+		}
+		const display = computed.display === "inline" ? "inline-block" : computed.display;
+		box.style.setProperty("display", display, "important");
+		box.style.setProperty("box-sizing", "border-box", "important");
+		box.style.setProperty("width", rect.width + "px", "important");
+		box.style.setProperty("height", rect.height + "px", "important");
+		box.style.setProperty("background", "#000", "important");
+		box.style.setProperty("-webkit-print-color-adjust", "exact", "important");
+		box.style.setProperty("print-color-adjust", "exact", "important");
+		el.replaceWith(box);
+	};
+	const matches = [];
+	for (const selector of selectors) {
+		matches.push(...document.querySelectorAll(selector));
+	}
+	matches.forEach(redact);
+})(%s)`
+
+// WithRedactSelectors replaces the elements matching any of the CSS
+// selectors with black boxes of the same size, so their text, generated
+// content, links, images, canvases and frames never reach the PDF and
+// personal data can be redacted at generation time.
+func WithRedactSelectors(selectors []string) Option {
+	return func(o *options) {
+		o.redactSelectors = selectors
+	}
+}
+
+// redact redacts the elements matching selectors.
+func redact(selectors []string) chromedp.Action {
+	return chromedp.ActionFunc(func(ctx context.Context) error {
+		quoted, err := json.Marshal(selectors)
+		if err != nil {
+			return err
+		}
+		return chromedp.Evaluate(fmt.Sprintf(redactScript, quoted), nil).Do(ctx)
+	})
+}
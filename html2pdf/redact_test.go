@@ -0,0 +1,76 @@
+package html2pdf
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/chromedp/cdproto/emulation"
+	"github.com/chromedp/chromedp"
+)
+
+// leakScript returns everything about the page that could end up in a PDF
+// as text: the markup, including attributes and form values, and the
+// generated content of every element.
+const leakScript = `(() => {
+	const parts = [document.body.outerHTML];
+	for (const el of document.querySelectorAll("*")) {
+		parts.push(getComputedStyle(el, "::before").content, getComputedStyle(el, "::after").content);
+		if ("value" in el) {
+			parts.push(String(el.value));
+		}
+	}
+	return parts.join("\n");
+})()`
+
+func TestRedactRemovesSecrets(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	allocCtx, cancelAlloc := chromedp.NewExecAllocator(ctx, allocatorOptions(getDefaultOptions())...)
+	defer cancelAlloc()
+	ctx, cancelTab := chromedp.NewContext(allocCtx)
+	defer cancelTab()
+
+	html := `<html><head><style>
+		#label::before { content: "SECRET-before"; }
+		#label::after { content: "SECRET-after"; }
+		</style></head><body>
+		<p>Name: <span class="pii">SECRET-text</span></p>
+		<p class="pii" id="label"></p>
+		<a class="pii" href="mailto:SECRET-link" title="SECRET-title">mail</a>
+		<img class="pii" alt="SECRET-alt" src="data:image/gif;base64,R0lGODlhAQABAIAAAAAAAP///yH5BAEAAAAALAAAAAABAAEAAAIBRAA7">
+		<input class="pii" value="SECRET-value">
+		<canvas class="pii" id="drawing" width="200" height="50"></canvas>
+		<iframe class="pii" srcdoc="<p>SECRET-frame</p>"></iframe>
+		<div class="pii"><span class="pii">SECRET-nested</span></div>
+		<script>document.getElementById("drawing").getContext("2d").fillText("SECRET-canvas", 10, 20);</script>
+		</body></html>`
+
+	var page string
+	err := chromedp.Run(ctx,
+		chromedp.Navigate("data:text/html,"+url.PathEscape(html)),
+		redact([]string{".pii"}),
+		emulation.SetEmulatedMedia().WithMedia("print"),
+		chromedp.Evaluate(leakScript, &page),
+	)
+	if err != nil {
+		t.Fatalf("Failed to redact page: %v", err)
+	}
+
+	if strings.Contains(page, "SECRET") {
+		t.Errorf("Redacted page still contains a secret:\n%s", page)
+	}
+	for _, tag := range []string{"<canvas", "<iframe", "<img", "<input"} {
+		if strings.Contains(page, tag) {
+			t.Errorf("Expected matched %s> to be removed", tag)
+		}
+	}
+	if !strings.Contains(page, "Name:") {
+		t.Error("Expected content outside the selectors to be kept")
+	}
+	if got := strings.Count(page, "<html2pdf-redacted"); got != 7 {
+		t.Errorf("Expected 7 redaction boxes, got %d", got)
+	}
+}
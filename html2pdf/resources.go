@@ -0,0 +1,138 @@
+package html2pdf
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/chromedp/chromedp"
+)
+
+// cgroupRoot is where the cgroup filesystem is mounted.
+var cgroupRoot = "/sys/fs/cgroup"
+
+// unlimitedMemoryThreshold is the value above which a cgroup v1 memory limit is treated as unlimited.
+const unlimitedMemoryThreshold = 1 << 62
+
+// bytesPerWorker is the memory reserved for each concurrent conversion when sizing concurrency.
+const bytesPerWorker = 512 << 20
+
+// ResourceLimits describes the CPU and memory limits imposed on the process by its container.
+type ResourceLimits struct {
+	// MemoryBytes is the memory limit in bytes, or 0 when unlimited.
+	MemoryBytes int64
+	// CPUs is the CPU quota in cores, or 0 when unlimited.
+	CPUs float64
+}
+
+// Limited reports whether any limit was detected.
+func (l ResourceLimits) Limited() bool {
+	return l.MemoryBytes > 0 || l.CPUs > 0
+}
+
+// Concurrency returns the number of conversions that can safely run at once
+// within the limits. It is always at least 1.
+func (l ResourceLimits) Concurrency() int {
+	n := 0
+	if l.CPUs > 0 {
+		n = int(math.Max(1, math.Floor(l.CPUs)))
+	}
+	if l.MemoryBytes > 0 {
+		byMemory := int(l.MemoryBytes / bytesPerWorker)
+		if n == 0 || byMemory < n {
+			n = byMemory
+		}
+	}
+	if n < 1 {
+		n = 1
+	}
+	return n
+}
+
+// allocatorOptions returns Chrome flags that keep the browser within the limits.
+func (l ResourceLimits) allocatorOptions() []chromedp.ExecAllocatorOption {
+	var opts []chromedp.ExecAllocatorOption
+	if l.MemoryBytes > 0 {
+		heapMB := l.MemoryBytes / 4 >> 20
+		if heapMB < 64 {
+			heapMB = 64
+		}
+		opts = append(opts, chromedp.Flag("js-flags", fmt.Sprintf("--max-old-space-size=%d", heapMB)))
+	}
+	if l.CPUs > 0 {
+		opts = append(opts, chromedp.Flag("renderer-process-limit", l.Concurrency()))
+	}
+	return opts
+}
+
+var (
+	detectedLimits     ResourceLimits
+	detectedLimitsOnce sync.Once
+)
+
+// DetectResourceLimits reads the cgroup (v1 or v2) memory and CPU limits of
+// the current process. The result is detected once and cached.
+func DetectResourceLimits() ResourceLimits {
+	detectedLimitsOnce.Do(func() {
+		detectedLimits = readResourceLimits(cgroupRoot)
+	})
+	return detectedLimits
+}
+
+// readResourceLimits reads the limits from the cgroup filesystem mounted at root.
+func readResourceLimits(root string) ResourceLimits {
+	var l ResourceLimits
+
+	if b, err := os.ReadFile(filepath.Join(root, "memory.max")); err == nil {
+		l.MemoryBytes = parseMemoryLimit(string(b))
+	} else if b, err := os.ReadFile(filepath.Join(root, "memory", "memory.limit_in_bytes")); err == nil {
+		l.MemoryBytes = parseMemoryLimit(string(b))
+	}
+
+	if b, err := os.ReadFile(filepath.Join(root, "cpu.max")); err == nil {
+		fields := strings.Fields(string(b))
+		if len(fields) == 2 {
+			l.CPUs = parseCPUQuota(fields[0], fields[1])
+		}
+	} else {
+		quota, errQuota := os.ReadFile(filepath.Join(root, "cpu", "cpu.cfs_quota_us"))
+		period, errPeriod := os.ReadFile(filepath.Join(root, "cpu", "cpu.cfs_period_us"))
+		if errQuota == nil && errPeriod == nil {
+			l.CPUs = parseCPUQuota(strings.TrimSpace(string(quota)), strings.TrimSpace(string(period)))
+		}
+	}
+	return l
+}
+
+// parseMemoryLimit parses a cgroup memory limit, returning 0 when unlimited.
+func parseMemoryLimit(s string) int64 {
+	s = strings.TrimSpace(s)
+	if s == "max" {
+		return 0
+	}
+	v, err := strconv.ParseInt(s, 10, 64)
+	if err != nil || v <= 0 || v >= unlimitedMemoryThreshold {
+		return 0
+	}
+	return v
+}
+
+// parseCPUQuota converts a CFS quota and period into cores, returning 0 when unlimited.
+func parseCPUQuota(quota, period string) float64 {
+	if quota == "max" || quota == "-1" {
+		return 0
+	}
+	q, err := strconv.ParseFloat(quota, 64)
+	if err != nil || q <= 0 {
+		return 0
+	}
+	p, err := strconv.ParseFloat(period, 64)
+	if err != nil || p <= 0 {
+		return 0
+	}
+	return q / p
+}
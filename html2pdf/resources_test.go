@@ -0,0 +1,97 @@
+package html2pdf
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestReadResourceLimits(t *testing.T) {
+	tests := []struct {
+		name  string
+		files map[string]string
+		want  ResourceLimits
+	}{
+		{
+			name: "cgroup v2 limited",
+			files: map[string]string{
+				"memory.max": "2147483648\n",
+				"cpu.max":    "200000 100000\n",
+			},
+			want: ResourceLimits{MemoryBytes: 2 << 30, CPUs: 2},
+		},
+		{
+			name: "cgroup v2 unlimited",
+			files: map[string]string{
+				"memory.max": "max\n",
+				"cpu.max":    "max 100000\n",
+			},
+			want: ResourceLimits{},
+		},
+		{
+			name: "cgroup v1 limited",
+			files: map[string]string{
+				"memory/memory.limit_in_bytes": "1073741824\n",
+				"cpu/cpu.cfs_quota_us":         "50000\n",
+				"cpu/cpu.cfs_period_us":        "100000\n",
+			},
+			want: ResourceLimits{MemoryBytes: 1 << 30, CPUs: 0.5},
+		},
+		{
+			name: "cgroup v1 unlimited",
+			files: map[string]string{
+				"memory/memory.limit_in_bytes": "9223372036854771712\n",
+				"cpu/cpu.cfs_quota_us":         "-1\n",
+				"cpu/cpu.cfs_period_us":        "100000\n",
+			},
+			want: ResourceLimits{},
+		},
+		{
+			name:  "no cgroup filesystem",
+			files: map[string]string{},
+			want:  ResourceLimits{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := t.TempDir()
+			for name, content := range tt.files {
+				path := filepath.Join(root, name)
+				if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+					t.Fatalf("Failed to create dir: %v", err)
+				}
+				if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+					t.Fatalf("Failed to write file: %v", err)
+				}
+			}
+
+			got := readResourceLimits(root)
+			if got != tt.want {
+				t.Errorf("readResourceLimits() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResourceLimitsConcurrency(t *testing.T) {
+	tests := []struct {
+		name   string
+		limits ResourceLimits
+		want   int
+	}{
+		{name: "unlimited", limits: ResourceLimits{}, want: 1},
+		{name: "cpu bound", limits: ResourceLimits{MemoryBytes: 8 << 30, CPUs: 4}, want: 4},
+		{name: "memory bound", limits: ResourceLimits{MemoryBytes: 1 << 30, CPUs: 8}, want: 2},
+		{name: "fractional cpu", limits: ResourceLimits{CPUs: 0.5}, want: 1},
+		{name: "tiny memory", limits: ResourceLimits{MemoryBytes: 128 << 20}, want: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.limits.Concurrency(); got != tt.want {
+				t.Errorf("Concurrency() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
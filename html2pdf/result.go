@@ -0,0 +1,128 @@
+package html2pdf
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+
+	"github.com/patipolchat/html2pdf/html2pdf/internal/pdf"
+)
+
+// checksumMetadataKey is the document information entry written by WithChecksumMetadata.
+const checksumMetadataKey = "ContentSHA256"
+
+// Result holds a generated PDF along with integrity details about it.
+type Result struct {
+	// PDF is the generated document.
+	PDF []byte
+	// SHA256 is the hex-encoded SHA-256 checksum of PDF.
+	SHA256 string
+	// Size is the length of PDF in bytes.
+	Size int
+	// Signature is the hex-encoded HMAC-SHA256 of PDF; it is set only with
+	// WithResultHMAC.
+	Signature string
+	// Timings breaks down how long each phase of the conversion took.
+	Timings Timings
+	// FontReport lists the fonts requested and used; it is set only with WithFontReport.
+	FontReport *FontReport
+	// Quality scores the document for review; it is set only with WithQualityReport.
+	Quality *QualityReport
+	// Environment describes the browser, package, fonts and options the
+	// document was rendered with.
+	Environment Environment
+	// Warnings lists non-fatal problems noticed while rendering, such as
+	// resources that failed to load, at most 100.
+	Warnings []Warning
+
+	// pooled reports whether Release may hand PDF back to the buffer pool.
+	pooled bool
+}
+
+// WriteTo writes PDF to w in a single call, so io.Copy to a file or HTTP
+// response needs no intermediate buffer. It implements io.WriterTo.
+func (r *Result) WriteTo(w io.Writer) (int64, error) {
+	n, err := w.Write(r.PDF)
+	return int64(n), err
+}
+
+// ReadAt reads PDF from offset off, e.g. for multipart uploads that read
+// parts of Size bytes concurrently. It implements io.ReaderAt.
+func (r *Result) ReadAt(p []byte, off int64) (int, error) {
+	return bytes.NewReader(r.PDF).ReadAt(p, off)
+}
+
+// PageInfo describes the geometry of a page. Width and Height are in inches,
+// like PaperSize, and describe the visible page area before Rotation is applied.
+type PageInfo struct {
+	Width  float64
+	Height float64
+	// Rotation is the clockwise rotation in degrees: 0, 90, 180 or 270.
+	Rotation int
+}
+
+// Landscape reports whether the page is wider than it is tall when displayed.
+func (p PageInfo) Landscape() bool {
+	if p.Rotation == 90 || p.Rotation == 270 {
+		return p.Height > p.Width
+	}
+	return p.Width > p.Height
+}
+
+// Matches reports whether the displayed page has the given paper size in
+// either orientation, within half a point.
+func (p PageInfo) Matches(size PaperSize) bool {
+	w, h := p.Width, p.Height
+	return (nearlyEqual(w*pointsPerInch, size.Width*pointsPerInch) && nearlyEqual(h*pointsPerInch, size.Height*pointsPerInch)) ||
+		(nearlyEqual(w*pointsPerInch, size.Height*pointsPerInch) && nearlyEqual(h*pointsPerInch, size.Width*pointsPerInch))
+}
+
+// newResult wraps a finished PDF in a Result.
+func newResult(buf []byte) *Result {
+	sum := sha256.Sum256(buf)
+	return &Result{
+		PDF:    buf,
+		SHA256: hex.EncodeToString(sum[:]),
+		Size:   len(buf),
+	}
+}
+
+// PageInfos describes the geometry of each page of PDF, in order. It parses
+// the document on every call, so conversions that never look at their pages
+// do not pay for it.
+func (r *Result) PageInfos() ([]PageInfo, error) {
+	return pageInfos(r.PDF)
+}
+
+// pageInfos reads the page geometry of a PDF.
+func pageInfos(buf []byte) ([]PageInfo, error) {
+	doc, err := pdf.Parse(buf)
+	if err != nil {
+		return nil, err
+	}
+	pages, err := doc.Pages()
+	if err != nil {
+		return nil, err
+	}
+	infos := make([]PageInfo, len(pages))
+	for i, p := range pages {
+		box := doc.PageBox(p, "CropBox")
+		infos[i] = PageInfo{
+			Width:    box.Width() / pointsPerInch,
+			Height:   box.Height() / pointsPerInch,
+			Rotation: doc.PageRotation(p),
+		}
+	}
+	return infos, nil
+}
+
+// WithChecksumMetadata embeds the SHA-256 checksum of the rendered document in
+// the PDF information dictionary under the ContentSHA256 key. The embedded
+// value covers the document before the entry was added, so it differs from
+// Result.SHA256.
+func WithChecksumMetadata(enabled bool) Option {
+	return func(o *options) {
+		o.checksumMetadata = enabled
+	}
+}
@@ -0,0 +1,146 @@
+package html2pdf
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/patipolchat/html2pdf/html2pdf/internal/pdf"
+)
+
+// testPDF returns a minimal PDF with the given number of Letter pages.
+func testPDF(t *testing.T, pages int) []byte {
+	t.Helper()
+	contents := make([]string, pages)
+	for i := range contents {
+		contents[i] = fmt.Sprintf("BT /F1 12 Tf 72 720 Td (Page %d) Tj ET", i+1)
+	}
+	return testPDFWithContents(t, contents...)
+}
+
+// testPDFWithContents returns a minimal PDF with one Letter page per content stream.
+func testPDFWithContents(t *testing.T, contents ...string) []byte {
+	t.Helper()
+	doc := pdf.New()
+	pages := make([]pdf.Ref, len(contents))
+	for i, content := range contents {
+		pages[i] = doc.Add(pdf.Dict{
+			"Type":     pdf.Name("Page"),
+			"MediaBox": pdf.Array{0, 0, 612, 792},
+			"Contents": doc.Add(pdf.Encode(nil, []byte(content))),
+		})
+	}
+	doc.SetPages(pages)
+	return doc.Bytes()
+}
+
+func TestNewResult(t *testing.T) {
+	buf := testPDF(t, 1)
+	res := newResult(buf)
+
+	sum := sha256.Sum256(buf)
+	if res.SHA256 != hex.EncodeToString(sum[:]) {
+		t.Errorf("Expected SHA256 %x, got %s", sum, res.SHA256)
+	}
+	if res.Size != len(buf) {
+		t.Errorf("Expected size %d, got %d", len(buf), res.Size)
+	}
+}
+
+func TestResultReaders(t *testing.T) {
+	res := newResult([]byte("%PDF-1.4 body %%EOF"))
+
+	var _ io.WriterTo = res
+	var _ io.ReaderAt = res
+
+	var w bytes.Buffer
+	n, err := res.WriteTo(&w)
+	if err != nil || n != int64(res.Size) || !bytes.Equal(w.Bytes(), res.PDF) {
+		t.Errorf("Expected %d bytes copied, got %d, %q, %v", res.Size, n, w.Bytes(), err)
+	}
+
+	tests := []struct {
+		name    string
+		off     int64
+		size    int
+		want    string
+		wantErr error
+	}{
+		{name: "start", off: 0, size: 4, want: "%PDF"},
+		{name: "middle", off: 9, size: 4, want: "body"},
+		{name: "past end", off: 14, size: 10, want: "%%EOF", wantErr: io.EOF},
+		{name: "after end", off: 100, size: 1, wantErr: io.EOF},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := make([]byte, tt.size)
+			n, err := res.ReadAt(p, tt.off)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ReadAt() error = %v, want %v", err, tt.wantErr)
+			}
+			if string(p[:n]) != tt.want {
+				t.Errorf("Expected %q, got %q", tt.want, p[:n])
+			}
+		})
+	}
+}
+
+func TestTimingsTotal(t *testing.T) {
+	timings := Timings{
+		Allocate:    1 * time.Millisecond,
+		Navigate:    2 * time.Millisecond,
+		SetContent:  3 * time.Millisecond,
+		WaitReady:   4 * time.Millisecond,
+		Print:       5 * time.Millisecond,
+		PostProcess: 6 * time.Millisecond,
+	}
+	if got := timings.Total(); got != 21*time.Millisecond {
+		t.Errorf("Total() = %v, want 21ms", got)
+	}
+}
+
+func TestPageInfos(t *testing.T) {
+	infos, err := pageInfos(mixedSizePDF(t))
+	if err != nil {
+		t.Fatalf("pageInfos() error = %v", err)
+	}
+	want := []struct {
+		rotation  int
+		landscape bool
+		matches   PaperSize
+	}{
+		{rotation: 0, landscape: false, matches: Letter},
+		{rotation: 0, landscape: true},
+		{rotation: 90, landscape: true, matches: Letter},
+	}
+	if len(infos) != len(want) {
+		t.Fatalf("Expected %d pages, got %d", len(want), len(infos))
+	}
+	for i, w := range want {
+		info := infos[i]
+		if info.Rotation != w.rotation {
+			t.Errorf("Page %d: expected rotation %d, got %d", i+1, w.rotation, info.Rotation)
+		}
+		if info.Landscape() != w.landscape {
+			t.Errorf("Page %d: expected landscape %v, got %v", i+1, w.landscape, info.Landscape())
+		}
+		if w.matches != (PaperSize{}) && !info.Matches(w.matches) {
+			t.Errorf("Page %d: expected %+v to match %+v", i+1, info, w.matches)
+		}
+		if info.Matches(A4) {
+			t.Errorf("Page %d: expected %+v not to match A4", i+1, info)
+		}
+	}
+	if infos[0].Width != 8.5 || infos[0].Height != 11 {
+		t.Errorf("Expected Letter page in inches, got %+v", infos[0])
+	}
+
+	if _, err := pageInfos([]byte("not a pdf")); err == nil {
+		t.Error("Expected an error for invalid input")
+	}
+}
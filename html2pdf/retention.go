@@ -0,0 +1,42 @@
+package html2pdf
+
+import (
+	"time"
+
+	"github.com/patipolchat/html2pdf/html2pdf/internal/pdf"
+)
+
+// Document information entries written by WithRetentionPolicy.
+const (
+	retentionExpiryKey   = "RetentionExpiry"
+	retentionPolicyIDKey = "RetentionPolicyID"
+)
+
+// WithRetentionPolicy records when the document may be deleted and the policy
+// that requires it in the PDF document information, as RetentionExpiry (a
+// PDF date) and RetentionPolicyID, so document management systems can
+// enforce deletion schedules. A zero expiry and empty policy ID disable it.
+func WithRetentionPolicy(expiry time.Time, policyID string) Option {
+	return func(o *options) {
+		o.retentionExpiry = expiry
+		o.retentionPolicyID = policyID
+	}
+}
+
+// pdfDate formats t as a PDF date string in UTC.
+func pdfDate(t time.Time) string {
+	return t.UTC().Format("D:20060102150405Z")
+}
+
+// addRetentionPolicy writes the retention entries into the document information.
+func addRetentionPolicy(expiry time.Time, policyID string) func(*pdf.Document) error {
+	return func(doc *pdf.Document) error {
+		if !expiry.IsZero() {
+			doc.SetInfo(retentionExpiryKey, pdfDate(expiry))
+		}
+		if policyID != "" {
+			doc.SetInfo(retentionPolicyIDKey, policyID)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,68 @@
+package html2pdf
+
+import (
+	"testing"
+	"time"
+
+	"github.com/patipolchat/html2pdf/html2pdf/internal/pdf"
+)
+
+func TestPdfDate(t *testing.T) {
+	tz := time.FixedZone("ICT", 7*60*60)
+	got := pdfDate(time.Date(2031, 3, 1, 9, 30, 0, 0, tz))
+	if want := "D:20310301023000Z"; got != want {
+		t.Errorf("pdfDate() = %q, want %q", got, want)
+	}
+}
+
+func TestWithRetentionPolicy(t *testing.T) {
+	expiry := time.Date(2031, 12, 31, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name       string
+		opts       []Option
+		wantExpiry string
+		wantPolicy string
+	}{
+		{name: "disabled"},
+		{
+			name:       "expiry and policy",
+			opts:       []Option{WithRetentionPolicy(expiry, "FIN-7Y")},
+			wantExpiry: "D:20311231000000Z",
+			wantPolicy: "FIN-7Y",
+		},
+		{
+			name:       "policy only",
+			opts:       []Option{WithRetentionPolicy(time.Time{}, "LEGAL-HOLD")},
+			wantPolicy: "LEGAL-HOLD",
+		},
+		{
+			name:       "with checksum metadata",
+			opts:       []Option{WithRetentionPolicy(expiry, "FIN-7Y"), WithChecksumMetadata(true)},
+			wantExpiry: "D:20311231000000Z",
+			wantPolicy: "FIN-7Y",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := postProcess(testPDF(t, 1), newOptions(tt.opts))
+			if err != nil {
+				t.Fatalf("postProcess() error = %v", err)
+			}
+			doc, err := pdf.Parse(out)
+			if err != nil {
+				t.Fatalf("Failed to parse output: %v", err)
+			}
+			info := doc.Info()
+			if got, _ := info[retentionExpiryKey].(pdf.String); string(got) != tt.wantExpiry {
+				t.Errorf("Expected %s %q, got %q", retentionExpiryKey, tt.wantExpiry, got)
+			}
+			if got, _ := info[retentionPolicyIDKey].(pdf.String); string(got) != tt.wantPolicy {
+				t.Errorf("Expected %s %q, got %q", retentionPolicyIDKey, tt.wantPolicy, got)
+			}
+		})
+	}
+
+	if newOptions([]Option{WithRetentionPolicy(expiry, "FIN-7Y")}).fingerprint() == getDefaultOptions().fingerprint() {
+		t.Error("Expected the retention policy to change the options fingerprint")
+	}
+}
@@ -0,0 +1,78 @@
+package html2pdf
+
+import (
+	"context"
+	"math"
+
+	"github.com/chromedp/cdproto/emulation"
+	"github.com/chromedp/cdproto/page"
+	"github.com/chromedp/chromedp"
+)
+
+// WithRollPaper prints on continuous roll paper widthMM millimetres wide,
+// such as 80mm thermal receipt paper. The page height is set to the height of
+// the rendered content so everything fits on a single page. A zero width
+// disables roll paper.
+func WithRollPaper(widthMM float64) Option {
+	return func(o *options) {
+		o.rollPaperWidth = widthMM / mmPerInch
+	}
+}
+
+// fitRollPaper lays the page out at the printable width of params and sets
+// params.PaperHeight to fit the content.
+func fitRollPaper(ctx context.Context, params *page.PrintToPDFParams) error {
+	width := (params.PaperWidth - params.MarginLeft - params.MarginRight) * cssPixelsPerInch / printScale(params)
+	_, height, err := measureContent(ctx, width)
+	if err != nil {
+		return err
+	}
+	params.PaperHeight = rollPaperHeight(height, params)
+	return nil
+}
+
+// measureContent lays the page out for print at the given viewport width in
+// CSS pixels and returns the size of the content. The device pixel ratio is
+// kept, so pages rendered for high-DPI output are not re-rendered at 1x.
+func measureContent(ctx context.Context, widthPx float64) (width, height float64, err error) {
+	var dpr float64
+	if err := chromedp.Evaluate(`window.devicePixelRatio`, &dpr).Do(ctx); err != nil {
+		return 0, 0, err
+	}
+	if err := emulation.SetDeviceMetricsOverride(int64(math.Max(1, math.Round(widthPx))), 800, dpr, false).Do(ctx); err != nil {
+		return 0, 0, err
+	}
+	if err := emulation.SetEmulatedMedia().WithMedia("print").Do(ctx); err != nil {
+		return 0, 0, err
+	}
+	var size struct {
+		Width  float64 `json:"width"`
+		Height float64 `json:"height"`
+	}
+	if err := chromedp.Evaluate(`(() => {
+	const root = document.documentElement, body = document.body;
+	return {
+		width: Math.max(root.scrollWidth, body ? body.scrollWidth : 0),
+		height: Math.max(root.scrollHeight, body ? body.scrollHeight : 0),
+	};
+})()`, &size).Do(ctx); err != nil {
+		return 0, 0, err
+	}
+	return size.Width, size.Height, nil
+}
+
+// rollPaperHeight returns the paper height in inches that fits content of the
+// given CSS pixel height, including the vertical margins.
+func rollPaperHeight(contentPx float64, params *page.PrintToPDFParams) float64 {
+	// One extra pixel keeps rounding from spilling the last line onto a second page.
+	content := (math.Ceil(contentPx) + 1) * printScale(params) / cssPixelsPerInch
+	return content + params.MarginTop + params.MarginBottom
+}
+
+// printScale returns the effective print scale of params.
+func printScale(params *page.PrintToPDFParams) float64 {
+	if params.Scale > 0 {
+		return params.Scale
+	}
+	return 1
+}
@@ -0,0 +1,56 @@
+package html2pdf
+
+import (
+	"math"
+	"testing"
+
+	"github.com/chromedp/cdproto/page"
+)
+
+func TestWithRollPaper(t *testing.T) {
+	params := newOptions([]Option{WithRollPaper(80)}).printToPDFParams()
+	if want := 80 / mmPerInch; math.Abs(params.PaperWidth-want) > 1e-9 {
+		t.Errorf("Expected paper width %v, got %v", want, params.PaperWidth)
+	}
+
+	params = newOptions(nil).printToPDFParams()
+	if params.PaperWidth != 0 {
+		t.Errorf("Expected default paper width, got %v", params.PaperWidth)
+	}
+}
+
+func TestRollPaperHeight(t *testing.T) {
+	tests := []struct {
+		name      string
+		contentPx float64
+		params    *page.PrintToPDFParams
+		want      float64
+	}{
+		{
+			name:      "no margins",
+			contentPx: 959.2,
+			params:    page.PrintToPDF(),
+			want:      10.01,
+		},
+		{
+			name:      "margins",
+			contentPx: 95,
+			params:    page.PrintToPDF().WithMarginTop(0.25).WithMarginBottom(0.5),
+			want:      1.75,
+		},
+		{
+			name:      "scaled",
+			contentPx: 191,
+			params:    page.PrintToPDF().WithScale(0.5),
+			want:      1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := rollPaperHeight(tt.contentPx, tt.params); math.Abs(got-tt.want) > 0.005 {
+				t.Errorf("Expected height %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,44 @@
+package html2pdf
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestHardenedSandboxFlags(t *testing.T) {
+	flags := map[string]interface{}{}
+	for _, f := range hardenedSandboxFlags {
+		if _, dup := flags[f.name]; dup {
+			t.Errorf("Flag %q is listed twice", f.name)
+		}
+		flags[f.name] = f.value
+	}
+
+	tests := []struct {
+		name string
+		want interface{}
+	}{
+		{name: "no-sandbox", want: false},
+		{name: "site-per-process", want: true},
+		{name: "disable-extensions", want: true},
+		{name: "no-first-run", want: true},
+		{name: "disable-sync", want: true},
+		{name: "disable-background-networking", want: true},
+	}
+	for _, tt := range tests {
+		if got := flags[tt.name]; got != tt.want {
+			t.Errorf("Expected %s = %v, got %v", tt.name, tt.want, got)
+		}
+	}
+	if features, _ := flags["disable-features"].(string); strings.Contains(features, "site-per-process") {
+		t.Errorf("Expected site isolation not to be disabled, got disable-features=%q", features)
+	}
+}
+
+func TestAllocatorOptionsWithHardenedSandbox(t *testing.T) {
+	base := len(allocatorOptions(getDefaultOptions()))
+	hardened := len(allocatorOptions(newOptions([]Option{WithHardenedSandbox(true)})))
+	if hardened != base+len(hardenedSandboxFlags) {
+		t.Errorf("Expected %d hardened options on top of %d, got %d", len(hardenedSandboxFlags), base, hardened)
+	}
+}
@@ -0,0 +1,115 @@
+package html2pdf
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+)
+
+// ErrInvalidData is matched by the DataError returned when template data
+// does not match the schema set with WithDataSchema.
+var ErrInvalidData = fmt.Errorf("template data does not match schema")
+
+// FieldError describes one schema violation.
+type FieldError struct {
+	// Field is the JSON pointer of the offending value, e.g. "/items/0/price",
+	// or "" for the data as a whole.
+	Field   string
+	Message string
+}
+
+// DataError lists every schema violation found in one data item.
+type DataError struct {
+	Fields []FieldError
+}
+
+func (e *DataError) Error() string {
+	parts := make([]string, len(e.Fields))
+	for i, f := range e.Fields {
+		field := f.Field
+		if field == "" {
+			field = "/"
+		}
+		parts[i] = field + ": " + f.Message
+	}
+	return fmt.Sprintf("%v: %s", ErrInvalidData, strings.Join(parts, "; "))
+}
+
+// Is makes errors.Is(err, ErrInvalidData) match a DataError.
+func (e *DataError) Is(target error) bool {
+	return target == ErrInvalidData
+}
+
+// WithDataSchema validates the data passed to MailMerge, ConvertLabelsToPdf
+// and ConvertTemplateToPdf against a JSON Schema before anything is rendered.
+// Invalid data returns a *DataError listing each offending field.
+func WithDataSchema(schema []byte) Option {
+	return func(o *options) {
+		o.dataSchema = schema
+	}
+}
+
+// dataValidator validates template data against a compiled schema.
+type dataValidator struct {
+	schema *jsonschema.Schema
+}
+
+// newDataValidator compiles schema, returning nil when schema is empty.
+func newDataValidator(schema []byte) (*dataValidator, error) {
+	if len(schema) == 0 {
+		return nil, nil
+	}
+	doc, err := jsonschema.UnmarshalJSON(bytes.NewReader(schema))
+	if err != nil {
+		return nil, fmt.Errorf("invalid data schema: %w", err)
+	}
+	c := jsonschema.NewCompiler()
+	if err := c.AddResource("schema.json", doc); err != nil {
+		return nil, fmt.Errorf("invalid data schema: %w", err)
+	}
+	compiled, err := c.Compile("schema.json")
+	if err != nil {
+		return nil, fmt.Errorf("invalid data schema: %w", err)
+	}
+	return &dataValidator{schema: compiled}, nil
+}
+
+// validate checks data, which is converted to its JSON form first so Go
+// values such as structs and integers are seen as the schema describes them.
+func (v *dataValidator) validate(data interface{}) error {
+	if v == nil {
+		return nil
+	}
+	b, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("failed to encode data for validation: %w", err)
+	}
+	inst, err := jsonschema.UnmarshalJSON(bytes.NewReader(b))
+	if err != nil {
+		return fmt.Errorf("failed to encode data for validation: %w", err)
+	}
+	err = v.schema.Validate(inst)
+	verr, ok := err.(*jsonschema.ValidationError)
+	if !ok {
+		return err
+	}
+	dataErr := &DataError{}
+	collectFieldErrors(verr.DetailedOutput(), dataErr)
+	return dataErr
+}
+
+// collectFieldErrors appends the leaf errors of unit to dataErr.
+func collectFieldErrors(unit *jsonschema.OutputUnit, dataErr *DataError) {
+	if len(unit.Errors) == 0 {
+		if unit.Error != nil {
+			dataErr.Fields = append(dataErr.Fields, FieldError{Field: unit.InstanceLocation, Message: unit.Error.String()})
+		}
+		return
+	}
+	for i := range unit.Errors {
+		collectFieldErrors(&unit.Errors[i], dataErr)
+	}
+}
@@ -0,0 +1,124 @@
+package html2pdf
+
+import (
+	"context"
+	"errors"
+	"html/template"
+	"strings"
+	"testing"
+)
+
+const invoiceSchema = `{
+	"type": "object",
+	"required": ["customer", "total"],
+	"properties": {
+		"customer": {"type": "string", "minLength": 1},
+		"total": {"type": "number", "minimum": 0},
+		"items": {"type": "array", "items": {"type": "object", "required": ["price"]}}
+	}
+}`
+
+func TestDataValidator(t *testing.T) {
+	v, err := newDataValidator([]byte(invoiceSchema))
+	if err != nil {
+		t.Fatalf("newDataValidator() error = %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		data       interface{}
+		wantFields []string
+	}{
+		{
+			name: "valid",
+			data: map[string]interface{}{"customer": "Acme", "total": 12.5},
+		},
+		{
+			name:       "missing total",
+			data:       map[string]interface{}{"customer": "Acme"},
+			wantFields: []string{""},
+		},
+		{
+			name:       "wrong types",
+			data:       map[string]interface{}{"customer": "", "total": "12"},
+			wantFields: []string{"/customer", "/total"},
+		},
+		{
+			name:       "nested item",
+			data:       map[string]interface{}{"customer": "Acme", "total": 1, "items": []interface{}{map[string]interface{}{"name": "x"}}},
+			wantFields: []string{"/items/0"},
+		},
+		{
+			name: "struct data",
+			data: struct {
+				Customer string  `json:"customer"`
+				Total    float64 `json:"total"`
+			}{"Acme", 3},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := v.validate(tt.data)
+			if len(tt.wantFields) == 0 {
+				if err != nil {
+					t.Errorf("validate() error = %v", err)
+				}
+				return
+			}
+			var dataErr *DataError
+			if !errors.As(err, &dataErr) || !errors.Is(err, ErrInvalidData) {
+				t.Fatalf("Expected a DataError matching ErrInvalidData, got %v", err)
+			}
+			got := map[string]bool{}
+			for _, f := range dataErr.Fields {
+				got[f.Field] = true
+			}
+			for _, field := range tt.wantFields {
+				if !got[field] {
+					t.Errorf("Expected an error for %q, got %+v", field, dataErr.Fields)
+				}
+			}
+		})
+	}
+}
+
+func TestNewDataValidator(t *testing.T) {
+	if v, err := newDataValidator(nil); v != nil || err != nil {
+		t.Errorf("Expected no validator without a schema, got %v, %v", v, err)
+	}
+	if _, err := newDataValidator([]byte(`{"type": `)); err == nil {
+		t.Error("Expected an error for malformed JSON")
+	}
+	if _, err := newDataValidator([]byte(`{"type": "no-such-type"}`)); err == nil {
+		t.Error("Expected an error for an invalid schema")
+	}
+	// A nil validator accepts anything.
+	var v *dataValidator
+	if err := v.validate(map[string]interface{}{}); err != nil {
+		t.Errorf("nil validator returned %v", err)
+	}
+}
+
+func TestMailMergeWithDataSchema(t *testing.T) {
+	tmpl := template.Must(template.New("invoice").Parse("<p>{{.customer}}: {{.total}}</p>"))
+	rows := []map[string]interface{}{
+		{"customer": "Acme", "total": 10},
+		{"customer": "Globex"},
+	}
+	_, err := MailMerge(context.Background(), tmpl, rows, WithDataSchema([]byte(invoiceSchema)))
+	if !errors.Is(err, ErrInvalidData) {
+		t.Fatalf("Expected ErrInvalidData, got %v", err)
+	}
+	if !strings.HasPrefix(err.Error(), "row 2: ") {
+		t.Errorf("Expected the error to name row 2, got %q", err)
+	}
+}
+
+func TestConvertLabelsToPdfWithDataSchema(t *testing.T) {
+	schema := []byte(`{"type": "object", "required": ["name"]}`)
+	data := []interface{}{map[string]interface{}{"city": "Springfield"}}
+	_, err := ConvertLabelsToPdf(context.Background(), Avery5160, "<p>{{.name}}</p>", data, WithDataSchema(schema))
+	if !errors.Is(err, ErrInvalidData) {
+		t.Errorf("Expected ErrInvalidData, got %v", err)
+	}
+}
@@ -0,0 +1,61 @@
+package html2pdf
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/chromedp/cdproto/page"
+	"github.com/chromedp/chromedp"
+)
+
+// WithScreencast records JPEG frames of the page to dir while it loads and
+// until it is printed, so template authors can replay what the page looked
+// like leading up to the print. Frames are named frame-00001.jpg and so on.
+// An empty dir disables recording.
+func WithScreencast(dir string) Option {
+	return func(o *options) {
+		o.screencastDir = dir
+	}
+}
+
+// startScreencast starts writing screencast frames to dir. Frames that fail
+// to save are reported to logger.
+func startScreencast(dir string, logger func(string, ...interface{})) chromedp.Action {
+	return chromedp.ActionFunc(func(ctx context.Context) error {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return fmt.Errorf("failed to create screencast directory: %w", err)
+		}
+		var mu sync.Mutex
+		n := 0
+		chromedp.ListenTarget(ctx, func(ev interface{}) {
+			frame, ok := ev.(*page.EventScreencastFrame)
+			if !ok {
+				return
+			}
+			mu.Lock()
+			n++
+			err := writeScreencastFrame(dir, n, frame.Data)
+			mu.Unlock()
+			if err != nil {
+				logger("screencast: %v", err)
+			}
+			// Chrome sends the next frame only after the previous one is
+			// acknowledged. Commands cannot be run from the listener itself.
+			go page.ScreencastFrameAck(frame.SessionID).Do(ctx)
+		})
+		return page.StartScreencast().WithFormat(page.ScreencastFormatJpeg).Do(ctx)
+	})
+}
+
+// writeScreencastFrame decodes a base64 frame and saves it as frame n in dir.
+func writeScreencastFrame(dir string, n int, data string) error {
+	b, err := base64.StdEncoding.DecodeString(data)
+	if err != nil {
+		return fmt.Errorf("failed to decode frame %d: %w", n, err)
+	}
+	return os.WriteFile(filepath.Join(dir, fmt.Sprintf("frame-%05d.jpg", n)), b, 0o644)
+}
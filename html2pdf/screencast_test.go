@@ -0,0 +1,27 @@
+package html2pdf
+
+import (
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWriteScreencastFrame(t *testing.T) {
+	dir := t.TempDir()
+	frame := []byte{0xff, 0xd8, 0xff, 0xd9}
+	if err := writeScreencastFrame(dir, 7, base64.StdEncoding.EncodeToString(frame)); err != nil {
+		t.Fatalf("writeScreencastFrame() error = %v", err)
+	}
+	got, err := os.ReadFile(filepath.Join(dir, "frame-00007.jpg"))
+	if err != nil {
+		t.Fatalf("Failed to read frame: %v", err)
+	}
+	if string(got) != string(frame) {
+		t.Errorf("Expected frame bytes %x, got %x", frame, got)
+	}
+
+	if err := writeScreencastFrame(dir, 8, "not base64!"); err == nil {
+		t.Error("Expected an error for invalid frame data")
+	}
+}
@@ -0,0 +1,152 @@
+package html2pdf
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/chromedp/cdproto/page"
+	"github.com/patipolchat/html2pdf/html2pdf/internal/pdf"
+)
+
+// ErrInvalidPageRange is returned when a page range is malformed.
+var ErrInvalidPageRange = fmt.Errorf("invalid page range")
+
+// emptyTemplate is used for a missing header or footer; Chrome prints its
+// default header when given an empty template.
+const emptyTemplate = "<span></span>"
+
+// PageRange is an inclusive, 1-based range of pages. A To of 0 means the
+// range runs through the last page.
+type PageRange struct {
+	From int
+	To   int
+}
+
+// String returns the range in Chrome's page range syntax.
+func (r PageRange) String() string {
+	if r.To == 0 {
+		return fmt.Sprintf("%d-", r.From)
+	}
+	return fmt.Sprintf("%d-%d", r.From, r.To)
+}
+
+// HeaderFooter holds the Chrome header and footer templates for a set of
+// pages. An empty template leaves that area blank.
+type HeaderFooter struct {
+	Header string
+	Footer string
+}
+
+// WithHeaderTemplate prints template at the top of every page. Chrome fills
+// elements with the classes date, title, url, pageNumber and totalPages,
+// e.g. <span class="pageNumber"></span>. Templates are rendered in isolation:
+// styles must be inline and the default font size is tiny, so set one. Leave
+// room with WithMargins, as the header is drawn inside the top margin.
+func WithHeaderTemplate(template string) Option {
+	return func(o *options) {
+		o.headerTemplate = template
+	}
+}
+
+// WithFooterTemplate prints template at the bottom of every page, like
+// WithHeaderTemplate.
+func WithFooterTemplate(template string) Option {
+	return func(o *options) {
+		o.footerTemplate = template
+	}
+}
+
+// WithSectionHeaders prints the given page ranges with their own header and
+// footer, e.g. to omit the header on the first page or give appendix pages
+// a different footer. Pages outside every range use the document-wide settings.
+// Ranges must not overlap.
+func WithSectionHeaders(sections map[PageRange]HeaderFooter) Option {
+	return func(o *options) {
+		o.sections = sections
+	}
+}
+
+// sortedSections validates sections and returns their ranges in page order.
+func sortedSections(sections map[PageRange]HeaderFooter) ([]PageRange, error) {
+	ranges := make([]PageRange, 0, len(sections))
+	for r := range sections {
+		if r.From < 1 || (r.To != 0 && r.To < r.From) {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidPageRange, r)
+		}
+		ranges = append(ranges, r)
+	}
+	sort.Slice(ranges, func(i, j int) bool { return ranges[i].From < ranges[j].From })
+	for i := 1; i < len(ranges); i++ {
+		prev := ranges[i-1]
+		if prev.To == 0 || prev.To >= ranges[i].From {
+			return nil, fmt.Errorf("%w: %s overlaps %s", ErrInvalidPageRange, prev, ranges[i])
+		}
+	}
+	return ranges, nil
+}
+
+// printSections prints each section of the loaded page with its own header
+// and footer and splices the pages into base, which holds the whole document.
+func printSections(ctx context.Context, base []byte, params *page.PrintToPDFParams, sections map[PageRange]HeaderFooter) ([]byte, error) {
+	ranges, err := sortedSections(sections)
+	if err != nil {
+		return nil, err
+	}
+	if params.PageRanges != "" {
+		return nil, fmt.Errorf("%w: section headers cannot be combined with page ranges", ErrInvalidPageRange)
+	}
+
+	doc, err := pdf.Parse(base)
+	if err != nil {
+		return nil, err
+	}
+	pages, err := doc.Pages()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, r := range ranges {
+		from, to := r.From, r.To
+		if from > len(pages) {
+			break
+		}
+		if to == 0 || to > len(pages) {
+			to = len(pages)
+		}
+
+		hf := sections[r]
+		p := *params
+		p.PageRanges = PageRange{From: from, To: to}.String()
+		p.DisplayHeaderFooter = true
+		p.HeaderTemplate = orEmptyTemplate(hf.Header)
+		p.FooterTemplate = orEmptyTemplate(hf.Footer)
+		buf, err := printToPDF(ctx, &p, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to print pages %s: %w", r, err)
+		}
+
+		section, err := pdf.Parse(buf)
+		if err != nil {
+			return nil, err
+		}
+		sectionPages, err := section.Pages()
+		if err != nil {
+			return nil, err
+		}
+		if len(sectionPages) != to-from+1 {
+			return nil, fmt.Errorf("pages %s printed as %d pages instead of %d", r, len(sectionPages), to-from+1)
+		}
+		copy(pages[from-1:to], doc.ImportPages(section, sectionPages))
+	}
+
+	doc.SetPages(pages)
+	return doc.Bytes(), nil
+}
+
+func orEmptyTemplate(template string) string {
+	if template == "" {
+		return emptyTemplate
+	}
+	return template
+}
@@ -0,0 +1,159 @@
+package html2pdf
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/patipolchat/html2pdf/html2pdf/internal/pdf"
+)
+
+func TestPageRangeString(t *testing.T) {
+	if got := (PageRange{From: 2, To: 5}).String(); got != "2-5" {
+		t.Errorf("Expected '2-5', got %q", got)
+	}
+	if got := (PageRange{From: 3}).String(); got != "3-" {
+		t.Errorf("Expected '3-', got %q", got)
+	}
+}
+
+func TestSortedSections(t *testing.T) {
+	tests := []struct {
+		name     string
+		sections map[PageRange]HeaderFooter
+		want     []PageRange
+		wantErr  bool
+	}{
+		{
+			name: "sorted by first page",
+			sections: map[PageRange]HeaderFooter{
+				{From: 5}:        {Footer: "Appendix"},
+				{From: 1, To: 1}: {},
+			},
+			want: []PageRange{{From: 1, To: 1}, {From: 5}},
+		},
+		{
+			name: "overlapping ranges",
+			sections: map[PageRange]HeaderFooter{
+				{From: 1, To: 3}: {},
+				{From: 3, To: 4}: {},
+			},
+			wantErr: true,
+		},
+		{
+			name: "open range followed by another",
+			sections: map[PageRange]HeaderFooter{
+				{From: 2}:        {},
+				{From: 4, To: 5}: {},
+			},
+			wantErr: true,
+		},
+		{
+			name:     "zero first page",
+			sections: map[PageRange]HeaderFooter{{From: 0, To: 2}: {}},
+			wantErr:  true,
+		},
+		{
+			name:     "end before start",
+			sections: map[PageRange]HeaderFooter{{From: 3, To: 2}: {}},
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := sortedSections(tt.sections)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidPageRange) {
+					t.Errorf("Expected ErrInvalidPageRange, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("sortedSections() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("sortedSections() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHeaderFooterTemplates(t *testing.T) {
+	header := `<div style="font-size:8px"><span class="title"></span></div>`
+	footer := `<div style="font-size:8px"><span class="pageNumber"></span> / <span class="totalPages"></span></div>`
+	tests := []struct {
+		name        string
+		opts        []Option
+		wantDisplay bool
+		wantHeader  string
+		wantFooter  string
+	}{
+		{name: "default"},
+		{name: "header only", opts: []Option{WithHeaderTemplate(header)}, wantDisplay: true, wantHeader: header, wantFooter: emptyTemplate},
+		{name: "footer only", opts: []Option{WithFooterTemplate(footer)}, wantDisplay: true, wantHeader: emptyTemplate, wantFooter: footer},
+		{name: "both", opts: []Option{WithHeaderTemplate(header), WithFooterTemplate(footer)}, wantDisplay: true, wantHeader: header, wantFooter: footer},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params := newOptions(tt.opts).printToPDFParams()
+			if params.DisplayHeaderFooter != tt.wantDisplay || params.HeaderTemplate != tt.wantHeader || params.FooterTemplate != tt.wantFooter {
+				t.Errorf("Expected display=%v header=%q footer=%q, got display=%v header=%q footer=%q",
+					tt.wantDisplay, tt.wantHeader, tt.wantFooter, params.DisplayHeaderFooter, params.HeaderTemplate, params.FooterTemplate)
+			}
+		})
+	}
+}
+
+func TestConvertHtmlToPdfWithSectionHeaders(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	htmlContent := `<html><body>
+<p>Cover</p><div data-html2pdf-break></div>
+<p>Body</p><div data-html2pdf-break></div>
+<p>Appendix</p>
+</body></html>`
+
+	got, err := ConvertHtmlToPdf(ctx, htmlContent,
+		WithHeaderTemplate(`<div style="font-size:8px">Report</div>`),
+		WithSectionHeaders(map[PageRange]HeaderFooter{
+			{From: 1, To: 1}: {},
+			{From: 3}:        {Footer: `<div style="font-size:8px">Appendix</div>`},
+		}),
+	)
+	if err != nil {
+		t.Fatalf("ConvertHtmlToPdf() error = %v", err)
+	}
+
+	doc, err := pdf.Parse(got)
+	if err != nil {
+		t.Fatalf("Failed to parse PDF: %v", err)
+	}
+	pages, _ := doc.Pages()
+	if len(pages) != 3 {
+		t.Errorf("Expected 3 pages, got %d", len(pages))
+	}
+}
+
+func TestConvertHtmlToPdfInvalidSections(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []Option
+	}{
+		{name: "overlapping", opts: []Option{WithSectionHeaders(map[PageRange]HeaderFooter{{From: 1, To: 3}: {}, {From: 2}: {}})}},
+		{name: "reversed", opts: []Option{WithSectionHeaders(map[PageRange]HeaderFooter{{From: 4, To: 2}: {}})}},
+		{name: "with page ranges", opts: []Option{WithSectionHeaders(map[PageRange]HeaderFooter{{From: 1}: {}}), WithPageRanges("1-2")}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// The options are rejected before Chrome is started.
+			_, err := ConvertHtmlToPdf(context.Background(), "<p>Report</p>", tt.opts...)
+			if !errors.Is(err, ErrInvalidPageRange) {
+				t.Errorf("Expected ErrInvalidPageRange, got %v", err)
+			}
+		})
+	}
+}
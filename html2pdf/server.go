@@ -0,0 +1,45 @@
+package html2pdf
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// assetServer serves content to Chrome over a loopback listener for the
+// duration of a single conversion.
+type assetServer struct {
+	listener net.Listener
+	server   *http.Server
+}
+
+// newAssetServer starts serving h on a random loopback port.
+func newAssetServer(h http.Handler) (*assetServer, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, err
+	}
+	s := &assetServer{
+		listener: listener,
+		server:   &http.Server{Handler: h},
+	}
+	go s.server.Serve(listener)
+	return s, nil
+}
+
+// URL returns the address of p on the server.
+func (s *assetServer) URL(p string) string {
+	u := url.URL{
+		Scheme: "http",
+		Host:   s.listener.Addr().String(),
+		Path:   "/" + strings.TrimPrefix(p, "/"),
+	}
+	return u.String()
+}
+
+// Close stops the server and closes open connections.
+func (s *assetServer) Close() error {
+	return s.server.Shutdown(context.Background())
+}
@@ -0,0 +1,53 @@
+package html2pdf
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+)
+
+// ErrSignatureMismatch is returned by VerifyResult when a PDF does not match
+// its signature.
+var ErrSignatureMismatch = fmt.Errorf("signature mismatch")
+
+// WithResultHMAC sets Result.Signature to the hex-encoded HMAC-SHA256 of the
+// PDF under key. Services passing the PDF and its signature along can check
+// with VerifyResult that neither was changed on the way. The key is not part
+// of the options fingerprint, so it never appears in fixtures or metadata.
+func WithResultHMAC(key []byte) Option {
+	return func(o *options) {
+		o.hmacKey = key
+	}
+}
+
+// signature returns the Result.Signature of pdf, or "" without a key.
+func (o *options) signature(pdf []byte) string {
+	if len(o.hmacKey) == 0 {
+		return ""
+	}
+	return signPDF(pdf, o.hmacKey)
+}
+
+// signPDF returns the hex-encoded HMAC-SHA256 of pdf under key.
+func signPDF(pdf, key []byte) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write(pdf)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifyResult checks that signature, as set in Result.Signature by
+// WithResultHMAC, is the signature of pdf under key. It returns
+// ErrSignatureMismatch when the PDF or the signature was altered.
+func VerifyResult(pdf []byte, signature string, key []byte) error {
+	got, err := hex.DecodeString(signature)
+	if err != nil {
+		return fmt.Errorf("%w: malformed signature", ErrSignatureMismatch)
+	}
+	mac := hmac.New(sha256.New, key)
+	mac.Write(pdf)
+	if !hmac.Equal(got, mac.Sum(nil)) {
+		return ErrSignatureMismatch
+	}
+	return nil
+}
@@ -0,0 +1,112 @@
+package html2pdf
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestVerifyResult(t *testing.T) {
+	pdf := []byte("%PDF-1.4 report")
+	key := []byte("secret")
+	sig := signPDF(pdf, key)
+
+	tampered := append([]byte(nil), pdf...)
+	tampered[len(tampered)-1] = 'X'
+
+	tests := []struct {
+		name    string
+		pdf     []byte
+		sig     string
+		key     []byte
+		wantErr bool
+	}{
+		{name: "valid", pdf: pdf, sig: sig, key: key},
+		{name: "tampered pdf", pdf: tampered, sig: sig, key: key, wantErr: true},
+		{name: "wrong key", pdf: pdf, sig: sig, key: []byte("other"), wantErr: true},
+		{name: "truncated signature", pdf: pdf, sig: sig[:32], key: key, wantErr: true},
+		{name: "malformed signature", pdf: pdf, sig: "not hex", key: key, wantErr: true},
+		{name: "empty signature", pdf: pdf, sig: "", key: key, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := VerifyResult(tt.pdf, tt.sig, tt.key)
+			if tt.wantErr {
+				if !errors.Is(err, ErrSignatureMismatch) {
+					t.Errorf("Expected ErrSignatureMismatch, got %v", err)
+				}
+			} else if err != nil {
+				t.Errorf("VerifyResult() error = %v", err)
+			}
+		})
+	}
+}
+
+func TestResultSignature(t *testing.T) {
+	if sig := newOptions(nil).signature([]byte("%PDF")); sig != "" {
+		t.Errorf("Expected no signature without a key, got %q", sig)
+	}
+
+	dir := t.TempDir()
+	html := "<html><body><h1>Signed</h1></body></html>"
+	recorded := newResult([]byte("%PDF-1.4 recorded"))
+	if err := recordFixture(document{html: html}, newOptions([]Option{WithFixtures(dir, FixtureRecord)}), recorded); err != nil {
+		t.Fatalf("recordFixture() error = %v", err)
+	}
+
+	key := []byte("secret")
+	res, err := ConvertHtmlToResult(context.Background(), html, WithFixtures(dir, FixtureReplay), WithResultHMAC(key))
+	if err != nil {
+		t.Fatalf("ConvertHtmlToResult() error = %v", err)
+	}
+	if err := VerifyResult(res.PDF, res.Signature, key); err != nil {
+		t.Errorf("VerifyResult() error = %v", err)
+	}
+}
+
+func TestResultSignatureDeduplicated(t *testing.T) {
+	dir := t.TempDir()
+	html := "<html><body><h1>Shared</h1></body></html>"
+	record := newOptions([]Option{WithFixtures(dir, FixtureRecord), WithDeduplication(true)})
+	if err := recordFixture(document{html: html}, record, newResult([]byte("%PDF-1.4 shared"))); err != nil {
+		t.Fatalf("recordFixture() error = %v", err)
+	}
+
+	leaderKey, followerKey := []byte("leader"), []byte("follower")
+	release := make(chan struct{})
+	leaderDone := make(chan *Result)
+	key := dedupKey(html, newOptions([]Option{WithFixtures(dir, FixtureReplay), WithDeduplication(true)}))
+	go func() {
+		// Hold the conversion open so the follower joins it.
+		res, _ := conversions.do(context.Background(), key, func(context.Context) (*Result, error) {
+			<-release
+			return convert(context.Background(), document{html: html}, newOptions([]Option{WithFixtures(dir, FixtureReplay), WithDeduplication(true), WithResultHMAC(leaderKey)}))
+		})
+		leaderDone <- res
+	}()
+	time.Sleep(20 * time.Millisecond)
+
+	followerDone := make(chan *Result)
+	go func() {
+		res, err := ConvertHtmlToResult(context.Background(), html, WithFixtures(dir, FixtureReplay), WithDeduplication(true), WithResultHMAC(followerKey))
+		if err != nil {
+			t.Errorf("ConvertHtmlToResult() error = %v", err)
+		}
+		followerDone <- res
+	}()
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+
+	leader, follower := <-leaderDone, <-followerDone
+	if follower == nil {
+		return
+	}
+	if err := VerifyResult(follower.PDF, follower.Signature, followerKey); err != nil {
+		t.Errorf("Expected the follower's own signature, got %v", err)
+	}
+	if leader.Signature != "" {
+		t.Errorf("Expected the shared result to be unsigned, got %q", leader.Signature)
+	}
+}
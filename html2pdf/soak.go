@@ -0,0 +1,150 @@
+package html2pdf
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrResourceLeak is returned by Soak when goroutines, temp files or browser
+// processes are not released after the conversions finish.
+var ErrResourceLeak = fmt.Errorf("resource leak detected")
+
+var (
+	// procRoot is where the proc filesystem is mounted.
+	procRoot = "/proc"
+	// tempDir is where Chrome profile directories are created.
+	tempDir = os.TempDir()
+	// soakSettleTimeout is how long Soak waits for resources to be released.
+	soakSettleTimeout = 10 * time.Second
+)
+
+// tempProfilePrefix is the prefix of the temporary Chrome profile directories.
+const tempProfilePrefix = "chromedp-runner"
+
+// ResourceUsage counts the resources held by the process.
+type ResourceUsage struct {
+	Goroutines       int
+	TempFiles        int
+	BrowserProcesses int
+}
+
+// leaks describes the resources in u that exceed baseline.
+func (u ResourceUsage) leaks(baseline ResourceUsage) []string {
+	var out []string
+	if u.Goroutines > baseline.Goroutines {
+		out = append(out, fmt.Sprintf("%d goroutines", u.Goroutines-baseline.Goroutines))
+	}
+	if u.TempFiles > baseline.TempFiles {
+		out = append(out, fmt.Sprintf("%d temp files", u.TempFiles-baseline.TempFiles))
+	}
+	if u.BrowserProcesses > baseline.BrowserProcesses {
+		out = append(out, fmt.Sprintf("%d browser processes", u.BrowserProcesses-baseline.BrowserProcesses))
+	}
+	return out
+}
+
+// currentUsage returns the resources currently held.
+func currentUsage() ResourceUsage {
+	return ResourceUsage{
+		Goroutines:       runtime.NumGoroutine(),
+		TempFiles:        countTempFiles(tempDir),
+		BrowserProcesses: countBrowserProcesses(procRoot),
+	}
+}
+
+// SoakReport summarizes a soak run.
+type SoakReport struct {
+	// Conversions is the number of completed conversions.
+	Conversions int
+	// Baseline is the usage before the first conversion, Final the usage
+	// after the last one once resources had time to be released.
+	Baseline, Final ResourceUsage
+}
+
+// Soak repeatedly converts docs, rounds times over the whole list or until
+// ctx is done when rounds is 0, and then checks that goroutines, temporary
+// Chrome profiles and browser processes return to their baseline. A failed
+// conversion stops the run with its error; leftover resources are reported
+// with ErrResourceLeak. The report is returned in both cases.
+func Soak(ctx context.Context, docs []string, rounds int, opts ...Option) (*SoakReport, error) {
+	if len(docs) == 0 {
+		return nil, fmt.Errorf("soak needs at least one document")
+	}
+
+	report := &SoakReport{Baseline: currentUsage()}
+	var runErr error
+	for i := 0; rounds <= 0 || i < rounds*len(docs); i++ {
+		if ctx.Err() != nil {
+			break
+		}
+		if _, err := ConvertHtmlToPdf(ctx, docs[i%len(docs)], opts...); err != nil {
+			if ctx.Err() != nil && errors.Is(err, ctx.Err()) {
+				break
+			}
+			runErr = fmt.Errorf("soak conversion %d failed: %w", i+1, err)
+			break
+		}
+		report.Conversions++
+	}
+
+	deadline := time.Now().Add(soakSettleTimeout)
+	for {
+		report.Final = currentUsage()
+		if len(report.Final.leaks(report.Baseline)) == 0 || time.Now().After(deadline) {
+			break
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	if runErr != nil {
+		return report, runErr
+	}
+	if leaks := report.Final.leaks(report.Baseline); len(leaks) > 0 {
+		return report, fmt.Errorf("%w after %d conversions: %s", ErrResourceLeak, report.Conversions, strings.Join(leaks, ", "))
+	}
+	return report, nil
+}
+
+// countTempFiles counts the temporary Chrome profile directories in dir.
+func countTempFiles(dir string) int {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return 0
+	}
+	n := 0
+	for _, e := range entries {
+		if strings.HasPrefix(e.Name(), tempProfilePrefix) {
+			n++
+		}
+	}
+	return n
+}
+
+// countBrowserProcesses counts the Chrome processes listed under root, or
+// returns 0 where the proc filesystem is not available.
+func countBrowserProcesses(root string) int {
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		return 0
+	}
+	n := 0
+	for _, e := range entries {
+		if _, err := strconv.Atoi(e.Name()); err != nil {
+			continue
+		}
+		comm, err := os.ReadFile(filepath.Join(root, e.Name(), "comm"))
+		if err != nil {
+			continue
+		}
+		if name := strings.ToLower(strings.TrimSpace(string(comm))); strings.Contains(name, "chrom") || strings.Contains(name, "headless_shell") {
+			n++
+		}
+	}
+	return n
+}
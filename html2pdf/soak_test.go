@@ -0,0 +1,72 @@
+package html2pdf
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestResourceUsageLeaks(t *testing.T) {
+	baseline := ResourceUsage{Goroutines: 4, TempFiles: 1, BrowserProcesses: 0}
+	tests := []struct {
+		name  string
+		usage ResourceUsage
+		want  int
+	}{
+		{name: "at baseline", usage: baseline, want: 0},
+		{name: "below baseline", usage: ResourceUsage{Goroutines: 2}, want: 0},
+		{name: "leaked goroutines", usage: ResourceUsage{Goroutines: 6, TempFiles: 1}, want: 1},
+		{name: "leaked everything", usage: ResourceUsage{Goroutines: 5, TempFiles: 2, BrowserProcesses: 3}, want: 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.usage.leaks(baseline); len(got) != tt.want {
+				t.Errorf("leaks() = %v, want %d entries", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCountTempFiles(t *testing.T) {
+	dir := t.TempDir()
+	os.Mkdir(filepath.Join(dir, "chromedp-runner123"), 0o755)
+	os.Mkdir(filepath.Join(dir, "chromedp-runner456"), 0o755)
+	os.WriteFile(filepath.Join(dir, "other"), nil, 0o644)
+
+	if got := countTempFiles(dir); got != 2 {
+		t.Errorf("countTempFiles() = %d, want 2", got)
+	}
+}
+
+func TestCountBrowserProcesses(t *testing.T) {
+	root := t.TempDir()
+	for pid, comm := range map[string]string{"10": "chrome\n", "11": "bash\n", "12": "headless_shell\n", "self": "chrome\n"} {
+		os.Mkdir(filepath.Join(root, pid), 0o755)
+		os.WriteFile(filepath.Join(root, pid, "comm"), []byte(comm), 0o644)
+	}
+
+	if got := countBrowserProcesses(root); got != 2 {
+		t.Errorf("countBrowserProcesses() = %d, want 2", got)
+	}
+	if got := countBrowserProcesses(filepath.Join(root, "missing")); got != 0 {
+		t.Errorf("countBrowserProcesses() on missing root = %d, want 0", got)
+	}
+}
+
+func TestSoak(t *testing.T) {
+	if _, err := Soak(context.Background(), nil, 1); err == nil {
+		t.Error("Expected an error without documents")
+	}
+
+	// A canceled context stops the run before any conversion.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	report, err := Soak(ctx, []string{"<p>soak</p>"}, 0)
+	if err != nil {
+		t.Fatalf("Soak() error = %v", err)
+	}
+	if report.Conversions != 0 {
+		t.Errorf("Expected no conversions, got %d", report.Conversions)
+	}
+}
@@ -0,0 +1,24 @@
+package html2pdf
+
+import "github.com/patipolchat/html2pdf/html2pdf/templates"
+
+// WithStarterTemplate places the HTML content inside the named layout from
+// the templates package ("invoice", "statement", or "report"), which sets up
+// A4 pages, fonts, and pagination rules. It enables WithPreferCSSPageSize so
+// the layout's @page size applies; pass WithPreferCSSPageSize(false) after it
+// to use the paper size options instead. The content should be body markup.
+// It applies to ConvertHtmlToPdf, ConvertHtmlFileToPdf, and ConvertHtmlToResult.
+func WithStarterTemplate(name string) Option {
+	return func(o *options) {
+		o.starterTemplate = name
+		o.preferCSSPageSize = true
+	}
+}
+
+// applyStarterTemplate wraps html in the starter template set in options, if any.
+func applyStarterTemplate(html string, options *options) (string, error) {
+	if options.starterTemplate == "" {
+		return html, nil
+	}
+	return templates.Render(options.starterTemplate, html)
+}
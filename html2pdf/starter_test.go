@@ -0,0 +1,60 @@
+package html2pdf
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/patipolchat/html2pdf/html2pdf/templates"
+)
+
+func TestApplyStarterTemplate(t *testing.T) {
+	body := `<h1>Invoice 42</h1>`
+
+	got, err := applyStarterTemplate(body, newOptions(nil))
+	if err != nil || got != body {
+		t.Errorf("Expected content unchanged without a starter template, got %q, %v", got, err)
+	}
+
+	got, err = applyStarterTemplate(body, newOptions([]Option{WithStarterTemplate("invoice")}))
+	if err != nil {
+		t.Fatalf("applyStarterTemplate() error = %v", err)
+	}
+	if !strings.Contains(got, `<body class="invoice">`) || !strings.Contains(got, body) {
+		t.Errorf("Expected content inside the invoice layout, got %q", got)
+	}
+
+	_, err = applyStarterTemplate(body, newOptions([]Option{WithStarterTemplate("missing")}))
+	if !errors.Is(err, templates.ErrUnknownTemplate) {
+		t.Errorf("Expected ErrUnknownTemplate, got %v", err)
+	}
+}
+
+func TestWithStarterTemplatePageSize(t *testing.T) {
+	if !newOptions([]Option{WithStarterTemplate("invoice")}).printToPDFParams().PreferCSSPageSize {
+		t.Error("Expected the starter template to prefer the CSS page size")
+	}
+	if newOptions([]Option{WithStarterTemplate("invoice"), WithPreferCSSPageSize(false)}).printToPDFParams().PreferCSSPageSize {
+		t.Error("Expected WithPreferCSSPageSize(false) to override the starter template")
+	}
+}
+
+func TestConvertHtmlToResultWithStarterTemplate(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	// The default paper size is Letter; the template asks for A4.
+	res, err := ConvertHtmlToResult(ctx, "<h1>Invoice 42</h1>", WithStarterTemplate("invoice"))
+	if err != nil {
+		t.Fatalf("ConvertHtmlToResult() error = %v", err)
+	}
+	pages, err := res.PageInfos()
+	if err != nil {
+		t.Fatalf("PageInfos() error = %v", err)
+	}
+	if len(pages) != 1 || !pages[0].Matches(A4) {
+		t.Errorf("Expected one A4 page, got %+v", pages)
+	}
+}
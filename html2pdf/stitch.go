@@ -0,0 +1,100 @@
+package html2pdf
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"os"
+)
+
+// stitchedPDF is an existing PDF added before or after the document, given
+// either as data or as the path of a file read at conversion time.
+type stitchedPDF struct {
+	data []byte
+	path string
+}
+
+// load returns the PDF's content.
+func (s stitchedPDF) load() ([]byte, error) {
+	if s.path == "" {
+		return s.data, nil
+	}
+	b, err := os.ReadFile(s.path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read PDF %s: %w", s.path, err)
+	}
+	return b, nil
+}
+
+// stitchedHashes returns the SHA-256 of each PDF for the options
+// fingerprint. A file is hashed by its current content, so replacing it
+// changes the fingerprint; a file that cannot be read is identified by its
+// path, and the conversion reports the error.
+func stitchedHashes(pdfs []stitchedPDF) []string {
+	hashes := make([]string, len(pdfs))
+	for i, s := range pdfs {
+		b, err := s.load()
+		if err != nil {
+			hashes[i] = fmt.Sprintf("unreadable:%q", s.path)
+			continue
+		}
+		hashes[i] = fmt.Sprintf("%x", sha256.Sum256(b))
+	}
+	return hashes
+}
+
+// WithPrependPDF places the pages of an existing PDF, such as a pre-signed
+// cover sheet, before the generated document. Repeated calls add PDFs in
+// order.
+func WithPrependPDF(pdf []byte) Option {
+	return func(o *options) {
+		o.prependPDFs = append(o.prependPDFs, stitchedPDF{data: pdf})
+	}
+}
+
+// WithPrependPDFFile is WithPrependPDF for a file read at conversion time.
+func WithPrependPDFFile(path string) Option {
+	return func(o *options) {
+		o.prependPDFs = append(o.prependPDFs, stitchedPDF{path: path})
+	}
+}
+
+// WithAppendPDF places the pages of an existing PDF, such as static terms
+// and conditions, after the generated document. Repeated calls add PDFs in
+// order.
+func WithAppendPDF(pdf []byte) Option {
+	return func(o *options) {
+		o.appendPDFs = append(o.appendPDFs, stitchedPDF{data: pdf})
+	}
+}
+
+// WithAppendPDFFile is WithAppendPDF for a file read at conversion time.
+func WithAppendPDFFile(path string) Option {
+	return func(o *options) {
+		o.appendPDFs = append(o.appendPDFs, stitchedPDF{path: path})
+	}
+}
+
+// stitchPDFs merges the prepended PDFs, buf and the appended PDFs in order.
+func stitchPDFs(buf []byte, options *options) ([]byte, error) {
+	docs := make([][]byte, 0, len(options.prependPDFs)+1+len(options.appendPDFs))
+	for _, s := range options.prependPDFs {
+		b, err := s.load()
+		if err != nil {
+			return nil, err
+		}
+		docs = append(docs, b)
+	}
+	docs = append(docs, buf)
+	for _, s := range options.appendPDFs {
+		b, err := s.load()
+		if err != nil {
+			return nil, err
+		}
+		docs = append(docs, b)
+	}
+	merged, err := MergePdf(docs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to add PDFs: %w", err)
+	}
+	return merged, nil
+}
@@ -0,0 +1,85 @@
+package html2pdf
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/patipolchat/html2pdf/html2pdf/internal/pdf"
+)
+
+func TestStitchPDFs(t *testing.T) {
+	dir := t.TempDir()
+	terms := filepath.Join(dir, "terms.pdf")
+	if err := os.WriteFile(terms, testPDF(t, 3), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		opts    []Option
+		want    int
+		wantErr bool
+	}{
+		{name: "prepend", opts: []Option{WithPrependPDF(testPDF(t, 1))}, want: 3},
+		{name: "append file", opts: []Option{WithAppendPDFFile(terms)}, want: 5},
+		{name: "both", opts: []Option{WithPrependPDFFile(terms), WithPrependPDF(testPDF(t, 1)), WithAppendPDF(testPDF(t, 1))}, want: 7},
+		{name: "missing file", opts: []Option{WithAppendPDFFile(filepath.Join(dir, "missing.pdf"))}, wantErr: true},
+		{name: "not a pdf", opts: []Option{WithPrependPDF([]byte("not a pdf"))}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			options := newOptions(tt.opts)
+			out, err := stitchPDFs(testPDF(t, 2), options)
+			if tt.wantErr {
+				if err == nil {
+					t.Error("Expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("stitchPDFs() error = %v", err)
+			}
+			doc, err := pdf.Parse(out)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			if pages, _ := doc.Pages(); len(pages) != tt.want {
+				t.Errorf("Expected %d pages, got %d", tt.want, len(pages))
+			}
+			if options.streamable() {
+				t.Error("Expected the document not to be streamable")
+			}
+		})
+	}
+}
+
+func TestStitchedPDFFingerprint(t *testing.T) {
+	base := newOptions(nil).fingerprint()
+	prepend := newOptions([]Option{WithPrependPDF([]byte("%PDF-1.4 a"))}).fingerprint()
+	appended := newOptions([]Option{WithAppendPDF([]byte("%PDF-1.4 a"))}).fingerprint()
+	other := newOptions([]Option{WithPrependPDF([]byte("%PDF-1.4 b"))}).fingerprint()
+	if base == prepend || prepend == appended || prepend == other {
+		t.Error("Expected added PDFs to be part of the fingerprint")
+	}
+	if strings.Contains(prepend, "%PDF-1.4 a") {
+		t.Error("Expected added PDFs to be hashed in the fingerprint")
+	}
+
+	path := filepath.Join(t.TempDir(), "terms.pdf")
+	if err := os.WriteFile(path, []byte("%PDF-1.4 a"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	fromFile := newOptions([]Option{WithPrependPDFFile(path)}).fingerprint()
+	if fromFile != prepend {
+		t.Error("Expected a file to be fingerprinted by its content")
+	}
+	if err := os.WriteFile(path, []byte("%PDF-1.4 b"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if newOptions([]Option{WithPrependPDFFile(path)}).fingerprint() == fromFile {
+		t.Error("Expected a replaced file to change the fingerprint")
+	}
+}
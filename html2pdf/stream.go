@@ -0,0 +1,152 @@
+package html2pdf
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/chromedp/cdproto/cdp"
+	cdpio "github.com/chromedp/cdproto/io"
+	"github.com/chromedp/cdproto/page"
+)
+
+// streamChunkSize is the number of bytes requested per IO.read call when the
+// PDF is transferred as a stream.
+var streamChunkSize int64 = 1 << 20
+
+// WithStreamTransfer makes Chrome hand the PDF over as a stream read in 1 MiB
+// chunks, instead of a single base64-encoded message. The message holds the
+// whole document several times over while it is decoded, which for documents
+// of hundreds of pages can exhaust memory. The chunks are still collected
+// into one buffer, except by ConvertHtmlToPdfWriter, which writes them out
+// as they arrive when no option needs the finished document.
+func WithStreamTransfer(enabled bool) Option {
+	return func(o *options) {
+		o.streamTransfer = enabled
+	}
+}
+
+// printToPDF prints with params and returns the PDF, reading it from the
+// stream Chrome returns when params asks for stream transfer into dst.
+func printToPDF(ctx context.Context, params *page.PrintToPDFParams, dst []byte) ([]byte, error) {
+	buf, stream, err := params.Do(ctx)
+	if err != nil || stream == "" {
+		return buf, err
+	}
+	out := bytes.NewBuffer(dst)
+	if err := readStream(ctx, stream, out); err != nil {
+		return nil, fmt.Errorf("failed to read PDF stream: %w", err)
+	}
+	return out.Bytes(), nil
+}
+
+// readStream copies the stream with handle to w chunk by chunk and closes it.
+func readStream(ctx context.Context, handle cdpio.StreamHandle, w io.Writer) error {
+	defer cdpio.Close(handle).Do(ctx)
+	for {
+		var res cdpio.ReadReturns
+		if err := cdp.Execute(ctx, cdpio.CommandRead, cdpio.Read(handle).WithSize(streamChunkSize), &res); err != nil {
+			return err
+		}
+		chunk := []byte(res.Data)
+		if res.Base64encoded {
+			var err error
+			if chunk, err = base64.StdEncoding.DecodeString(res.Data); err != nil {
+				return err
+			}
+		}
+		if _, err := w.Write(chunk); err != nil {
+			return err
+		}
+		if res.EOF {
+			return nil
+		}
+	}
+}
+
+// pdfStream passes the PDF on while Chrome's stream is read.
+type pdfStream struct {
+	w     *io.PipeWriter
+	once  sync.Once
+	ready chan error
+}
+
+// start reports, once, whether the PDF is about to be written or why it
+// will not be.
+func (s *pdfStream) start(err error) {
+	s.once.Do(func() { s.ready <- err })
+}
+
+// streamable reports whether Chrome's output is the final document, so it
+// can be passed on as it is read. Options editing the finished document need
+// all of it first.
+func (o *options) streamable() bool {
+	return !o.trimBlankPages && o.pageBackground == nil &&
+		(o.classification == "" || o.classificationPosition == 0) &&
+		o.retentionExpiry.IsZero() && o.retentionPolicyID == "" &&
+		!o.checksumMetadata && !o.environmentMetadata &&
+		o.rasterDPI == 0 && len(o.sections) == 0 && o.fixtureDir == "" &&
+		o.coverHTML == "" && len(o.prependPDFs) == 0 && len(o.appendPDFs) == 0 &&
+		len(o.assertions) == 0
+}
+
+// ConvertHtmlToPdfStream converts HTML content to PDF and returns a reader of
+// the document. Chrome's output is read chunk by chunk only as fast as the
+// reader is drained, so an HTTP handler can io.Copy it to a slow client
+// without holding the whole PDF in memory. Errors before the first byte,
+// such as a page that fails to load, are returned directly; later ones by
+// Read. Options that edit or check the finished document, such as
+// WithTrimBlankPages, WithChecksumMetadata or WithPostRenderAssertion, need
+// all of it, which is then buffered. Close the reader to release the browser.
+func ConvertHtmlToPdfStream(ctx context.Context, htmlContent string, opts ...Option) (io.ReadCloser, error) {
+	options := newOptions(opts)
+	htmlContent, err := applyStarterTemplate(htmlContent, options)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	pr, pw := io.Pipe()
+	stream := &pdfStream{w: pw, ready: make(chan error, 1)}
+	streaming := options.streamable()
+	if streaming {
+		options.pdfStream = stream
+	}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		res, err := convert(ctx, document{html: htmlContent}, options)
+		if err == nil && !streaming {
+			stream.start(nil)
+			_, err = pw.Write(res.PDF)
+		}
+		stream.start(err)
+		pw.CloseWithError(err)
+	}()
+
+	if err := <-stream.ready; err != nil {
+		cancel()
+		<-done
+		return nil, err
+	}
+	return &pdfStreamReader{PipeReader: pr, cancel: cancel, done: done}, nil
+}
+
+// pdfStreamReader is the reader returned by ConvertHtmlToPdfStream.
+type pdfStreamReader struct {
+	*io.PipeReader
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// Close stops the conversion if it is still running and waits for the
+// browser to be released.
+func (r *pdfStreamReader) Close() error {
+	r.cancel()
+	r.PipeReader.Close()
+	<-r.done
+	return nil
+}
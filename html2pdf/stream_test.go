@@ -0,0 +1,148 @@
+package html2pdf
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/chromedp/cdproto/cdp"
+	cdpio "github.com/chromedp/cdproto/io"
+	"github.com/chromedp/cdproto/page"
+)
+
+// fakeStream serves IO.read and IO.close calls from chunks.
+type fakeStream struct {
+	chunks []cdpio.ReadReturns
+	reads  int
+	closed bool
+	err    error
+}
+
+func (f *fakeStream) Execute(ctx context.Context, method string, params, res interface{}) error {
+	switch method {
+	case cdpio.CommandClose:
+		f.closed = true
+	case cdpio.CommandRead:
+		if f.err != nil {
+			return f.err
+		}
+		*res.(*cdpio.ReadReturns) = f.chunks[f.reads]
+		f.reads++
+	}
+	return nil
+}
+
+func TestReadStream(t *testing.T) {
+	chunk := func(s string, eof bool) cdpio.ReadReturns {
+		return cdpio.ReadReturns{Base64encoded: true, Data: base64.StdEncoding.EncodeToString([]byte(s)), EOF: eof}
+	}
+	tests := []struct {
+		name    string
+		stream  *fakeStream
+		want    string
+		wantErr bool
+	}{
+		{name: "chunks", stream: &fakeStream{chunks: []cdpio.ReadReturns{chunk("%PDF-1.4\n", false), chunk("%%EOF", true)}}, want: "%PDF-1.4\n%%EOF"},
+		{name: "plain text", stream: &fakeStream{chunks: []cdpio.ReadReturns{{Data: "%PDF", EOF: true}}}, want: "%PDF"},
+		{name: "bad base64", stream: &fakeStream{chunks: []cdpio.ReadReturns{{Base64encoded: true, Data: "!!", EOF: true}}}, wantErr: true},
+		{name: "read error", stream: &fakeStream{err: errors.New("stream gone")}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			err := readStream(cdp.WithExecutor(context.Background(), tt.stream), "stream-1", &buf)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("readStream() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && buf.String() != tt.want {
+				t.Errorf("Expected %q, got %q", tt.want, buf.String())
+			}
+			if !tt.stream.closed {
+				t.Error("Expected the stream to be closed")
+			}
+		})
+	}
+}
+
+func TestWithStreamTransfer(t *testing.T) {
+	if mode := getDefaultOptions().printToPDFParams().TransferMode; mode != "" {
+		t.Errorf("Expected the default transfer mode, got %q", mode)
+	}
+	if mode := newOptions([]Option{WithStreamTransfer(true)}).printToPDFParams().TransferMode; mode != page.PrintToPDFTransferModeReturnAsStream {
+		t.Errorf("Expected stream transfer, got %q", mode)
+	}
+}
+
+func TestStreamable(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []Option
+		want bool
+	}{
+		{name: "default", want: true},
+		{name: "print options", opts: []Option{WithLandscape(true), WithStreamTransfer(true)}, want: true},
+		{name: "trim blank pages", opts: []Option{WithTrimBlankPages(true)}},
+		{name: "checksum", opts: []Option{WithChecksumMetadata(true)}},
+		{name: "raster", opts: []Option{WithRasterizeOutput(150)}},
+		{name: "fixtures", opts: []Option{WithFixtures(t.TempDir(), FixtureReplay)}},
+		{name: "assertions", opts: []Option{WithPostRenderAssertion(func(TextIndex) error { return nil })}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := newOptions(tt.opts).streamable(); got != tt.want {
+				t.Errorf("streamable() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConvertHtmlToPdfStreamBuffered(t *testing.T) {
+	dir := t.TempDir()
+	html := "<html><body><h1>Streamed</h1></body></html>"
+	recorded := newResult([]byte("%PDF-1.4 recorded"))
+	if err := recordFixture(document{html: html}, newOptions([]Option{WithFixtures(dir, FixtureRecord)}), recorded); err != nil {
+		t.Fatalf("recordFixture() error = %v", err)
+	}
+
+	ctx := context.Background()
+	r, err := ConvertHtmlToPdfStream(ctx, html, WithFixtures(dir, FixtureReplay))
+	if err != nil {
+		t.Fatalf("ConvertHtmlToPdfStream() error = %v", err)
+	}
+	got, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatalf("ReadAll() error = %v", err)
+	}
+	if err := r.Close(); err != nil {
+		t.Errorf("Close() error = %v", err)
+	}
+	if !bytes.Equal(got, recorded.PDF) {
+		t.Errorf("Expected %q, got %q", recorded.PDF, got)
+	}
+
+	if _, err := ConvertHtmlToPdfStream(ctx, "<p>other</p>", WithFixtures(dir, FixtureReplay)); !errors.Is(err, ErrFixtureNotFound) {
+		t.Errorf("Expected ErrFixtureNotFound, got %v", err)
+	}
+}
+
+func TestConvertHtmlToPdfStream(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	r, err := ConvertHtmlToPdfStream(ctx, "<h1>Streamed</h1>")
+	if err != nil {
+		t.Fatalf("ConvertHtmlToPdfStream() error = %v", err)
+	}
+	defer r.Close()
+	got, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatalf("ReadAll() error = %v", err)
+	}
+	if !bytes.HasPrefix(got, []byte("%PDF")) {
+		t.Errorf("Expected a PDF, got %q", got[:min(len(got), 16)])
+	}
+}
@@ -0,0 +1,26 @@
+package html2pdf
+
+// svgVectorCSS removes the SVG effects Chrome can only print as bitmaps.
+const svgVectorCSS = `svg, svg * {
+	filter: none !important;
+	will-change: auto !important;
+}`
+
+// WithSVGRasterizationDPI renders the page at a device pixel ratio of
+// dpi/96, so SVG content Chrome cannot print as vectors, such as filters and
+// drop shadows, is rasterized at dpi instead of screen resolution. A dpi of
+// 96 or less keeps Chrome's default.
+func WithSVGRasterizationDPI(dpi int) Option {
+	return func(o *options) {
+		o.svgDPI = dpi
+	}
+}
+
+// WithSVGVectors removes filter effects from inline SVG so charts print as
+// sharp vectors instead of being rasterized, at the cost of effects such as
+// drop shadows and blurs.
+func WithSVGVectors(enabled bool) Option {
+	return func(o *options) {
+		o.svgVectors = enabled
+	}
+}
@@ -0,0 +1,36 @@
+package html2pdf
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestWithSVGRasterizationDPI(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []Option
+		want float64
+	}{
+		{name: "default", want: 0},
+		{name: "screen dpi", opts: []Option{WithSVGRasterizationDPI(96)}, want: 0},
+		{name: "300 dpi", opts: []Option{WithSVGRasterizationDPI(300)}, want: 3.125},
+		{name: "print images win", opts: []Option{WithSVGRasterizationDPI(144), WithImageResolutionPolicy(ImageResolutionPrint)}, want: 2},
+		{name: "svg dpi wins", opts: []Option{WithSVGRasterizationDPI(288), WithImageResolutionPolicy(ImageResolutionPrint)}, want: 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := newOptions(tt.opts).deviceScaleFactor(); got != tt.want {
+				t.Errorf("Expected device scale factor %g, got %g", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestWithSVGVectors(t *testing.T) {
+	if strings.Contains(getDefaultOptions().injectedCSS(), svgVectorCSS) {
+		t.Error("Expected no SVG rules by default")
+	}
+	if !strings.Contains(newOptions([]Option{WithSVGVectors(true)}).injectedCSS(), svgVectorCSS) {
+		t.Error("Expected SVG rules to be injected")
+	}
+}
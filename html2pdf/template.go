@@ -0,0 +1,157 @@
+package html2pdf
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"html/template"
+	"io/fs"
+	"path/filepath"
+)
+
+// TemplateFuncs returns the functions this package provides to templates:
+//
+//	t	translates a message ID, see WithTranslations
+//	decimal	converts a number or numeric string to an exact Decimal
+//	add, sub, mul, div	exact decimal arithmetic, e.g. {{mul .Price .Qty}}
+//	round	rounds halves away from zero: {{mul .Subtotal "0.07" | round 2}}
+//	sum	adds up a list, or a field of each item: {{sum .Lines "Amount"}}
+//	barChart, lineChart, pieChart	inline SVG charts: {{barChart .Sales "Month" "Total"}}
+//
+// Templates passed to ConvertTemplateToPdf or MailMerge that use them must
+// be parsed with them, e.g. template.New("invoice").Funcs(TemplateFuncs());
+// the conversion options then decide what they do.
+func TemplateFuncs() template.FuncMap {
+	funcs := template.FuncMap{
+		"t": func(id string, data ...interface{}) (string, error) {
+			return "", fmt.Errorf("%w: %q, no translations set", ErrMissingTranslation, id)
+		},
+	}
+	for name, fn := range decimalFuncs() {
+		funcs[name] = fn
+	}
+	for name, fn := range chartFuncs() {
+		funcs[name] = fn
+	}
+	return funcs
+}
+
+// WithTemplateFuncs adds functions to the templates this package executes,
+// e.g. currency or date formatting, and may replace those of TemplateFuncs.
+// Templates parsed by the caller must already know the names; templates
+// parsed by this package, such as in ConvertTemplateFileToPdf, get them
+// automatically. Repeated calls add to the earlier functions.
+func WithTemplateFuncs(funcs template.FuncMap) Option {
+	return func(o *options) {
+		if o.customFuncs == nil {
+			o.customFuncs = template.FuncMap{}
+		}
+		for name, fn := range funcs {
+			o.customFuncs[name] = fn
+		}
+	}
+}
+
+// templateFuncs returns TemplateFuncs bound to the options, with the
+// functions of WithTemplateFuncs added last.
+func (o *options) templateFuncs() template.FuncMap {
+	funcs := TemplateFuncs()
+	if o.translations != nil {
+		funcs["t"] = translator(o.translations, o.language)
+	}
+	for name, fn := range o.customFuncs {
+		funcs[name] = fn
+	}
+	return funcs
+}
+
+// executeTemplate executes a copy of tmpl with the template functions of
+// options, leaving tmpl itself unchanged.
+func executeTemplate(tmpl *template.Template, data interface{}, options *options) (string, error) {
+	clone, err := tmpl.Clone()
+	if err != nil {
+		return "", err
+	}
+	var html bytes.Buffer
+	if err := clone.Funcs(options.templateFuncs()).Execute(&html, data); err != nil {
+		return "", err
+	}
+	return html.String(), nil
+}
+
+// ErrTemplateNotFound is returned by ConvertNamedTemplateToPdf when no
+// template of the requested name was parsed.
+var ErrTemplateNotFound = fmt.Errorf("template not found")
+
+// WithTemplateGlob sets the html/template files ConvertNamedTemplateToPdf
+// parses, e.g. "templates/*.gohtml", so a layout, its partials and the
+// pages using them are parsed together. The files are parsed with
+// TemplateFuncs and WithTemplateFuncs.
+func WithTemplateGlob(pattern string) Option {
+	return func(o *options) {
+		o.templateGlob = pattern
+	}
+}
+
+// ConvertTemplateToPdf executes tmpl with data and converts the output to
+// PDF, e.g. an invoice template with the invoice as data. With
+// WithDataSchema, data is validated before the template runs.
+func ConvertTemplateToPdf(ctx context.Context, tmpl *template.Template, data interface{}, opts ...Option) ([]byte, error) {
+	options := newOptions(opts)
+	validator, err := newDataValidator(options.dataSchema)
+	if err != nil {
+		return nil, err
+	}
+	if err := validator.validate(data); err != nil {
+		return nil, err
+	}
+	html, err := executeTemplate(tmpl, data, options)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render template: %w", err)
+	}
+	return ConvertHtmlToPdf(ctx, html, opts...)
+}
+
+// ConvertTemplateFileToPdf parses the html/template file fileName with
+// TemplateFuncs and WithTemplateFuncs, executes it with data and converts
+// the output to PDF.
+func ConvertTemplateFileToPdf(ctx context.Context, fileName string, data interface{}, opts ...Option) ([]byte, error) {
+	tmpl, err := template.New(filepath.Base(fileName)).Funcs(newOptions(opts).templateFuncs()).ParseFiles(fileName)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, ErrHTMLFileNotFound
+		}
+		return nil, fmt.Errorf("failed to parse template %s: %w", fileName, err)
+	}
+	return ConvertTemplateToPdf(ctx, tmpl, data, opts...)
+}
+
+// ConvertNamedTemplateToPdf parses the files set with WithTemplateGlob,
+// executes the template called name, e.g. "invoice.gohtml" or a name given
+// with {{define}}, with data and converts the output to PDF.
+func ConvertNamedTemplateToPdf(ctx context.Context, name string, data interface{}, opts ...Option) ([]byte, error) {
+	options := newOptions(opts)
+	tmpl, err := namedTemplate(options.templateGlob, name, options.templateFuncs())
+	if err != nil {
+		return nil, err
+	}
+	return ConvertTemplateToPdf(ctx, tmpl, data, opts...)
+}
+
+// namedTemplate parses the files matching pattern with funcs and returns
+// the template called name.
+func namedTemplate(pattern, name string, funcs template.FuncMap) (*template.Template, error) {
+	if pattern == "" {
+		return nil, fmt.Errorf("%w: %q, no template files set with WithTemplateGlob", ErrTemplateNotFound, name)
+	}
+	set, err := template.New("").Funcs(funcs).ParseGlob(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse templates %s: %w", pattern, err)
+	}
+	tmpl := set.Lookup(name)
+	if tmpl == nil {
+		return nil, fmt.Errorf("%w: %q in %s", ErrTemplateNotFound, name, pattern)
+	}
+	return tmpl, nil
+}
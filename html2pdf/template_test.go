@@ -0,0 +1,160 @@
+package html2pdf
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"html/template"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestConvertTemplateToPdfErrors(t *testing.T) {
+	ctx := context.Background()
+	tmpl := template.Must(template.New("invoice").Option("missingkey=error").Parse("<p>{{.customer}}: {{.total}}</p>"))
+
+	_, err := ConvertTemplateToPdf(ctx, tmpl, map[string]interface{}{"customer": "Acme"}, WithDataSchema([]byte(invoiceSchema)))
+	if !errors.Is(err, ErrInvalidData) {
+		t.Errorf("Expected ErrInvalidData, got %v", err)
+	}
+
+	_, err = ConvertTemplateToPdf(ctx, tmpl, map[string]interface{}{"customer": "Acme"})
+	if err == nil {
+		t.Error("Expected an error when the template fails to execute")
+	}
+
+	_, err = ConvertTemplateFileToPdf(ctx, filepath.Join(t.TempDir(), "missing.html"), nil)
+	if !errors.Is(err, ErrHTMLFileNotFound) {
+		t.Errorf("Expected ErrHTMLFileNotFound, got %v", err)
+	}
+
+	bad := filepath.Join(t.TempDir(), "bad.html")
+	if err := os.WriteFile(bad, []byte("<p>{{.customer</p>"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ConvertTemplateFileToPdf(ctx, bad, nil); err == nil || errors.Is(err, ErrHTMLFileNotFound) {
+		t.Errorf("Expected a parse error, got %v", err)
+	}
+}
+
+func TestConvertTemplateToPdf(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	file := filepath.Join(t.TempDir(), "invoice.html")
+	if err := os.WriteFile(file, []byte("<h1>Invoice for {{.Customer}}</h1>"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	data := struct{ Customer string }{Customer: "Acme <Corp>"}
+
+	fromFile, err := ConvertTemplateFileToPdf(ctx, file, data)
+	if err != nil {
+		t.Fatalf("ConvertTemplateFileToPdf() error = %v", err)
+	}
+	if !bytes.HasPrefix(fromFile, []byte("%PDF-")) {
+		t.Error("Expected a PDF document")
+	}
+
+	tmpl := template.Must(template.ParseFiles(file))
+	if _, err := ConvertTemplateToPdf(ctx, tmpl, data); err != nil {
+		t.Fatalf("ConvertTemplateToPdf() error = %v", err)
+	}
+}
+
+func TestNamedTemplate(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"layout.gohtml":  `{{define "layout"}}<html><body>{{template "header" .}}{{block "content" .}}{{end}}</body></html>{{end}}`,
+		"header.gohtml":  `{{define "header"}}<header>{{.Company}}</header>{{end}}`,
+		"invoice.gohtml": `{{template "layout" .}}{{define "content"}}<p>Total {{round 2 .Total}}</p>{{end}}`,
+	}
+	for name, src := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(src), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	pattern := filepath.Join(dir, "*.gohtml")
+
+	tmpl, err := namedTemplate(pattern, "invoice.gohtml", TemplateFuncs())
+	if err != nil {
+		t.Fatalf("namedTemplate() error = %v", err)
+	}
+	got, err := executeTemplate(tmpl, map[string]interface{}{"Company": "Acme", "Total": 12.5}, getDefaultOptions())
+	if err != nil {
+		t.Fatalf("executeTemplate() error = %v", err)
+	}
+	if want := "<html><body><header>Acme</header><p>Total 12.50</p></body></html>"; got != want {
+		t.Errorf("Expected %q, got %q", want, got)
+	}
+
+	tests := []struct {
+		name    string
+		pattern string
+		entry   string
+		wantErr error
+	}{
+		{name: "no glob", entry: "invoice.gohtml", wantErr: ErrTemplateNotFound},
+		{name: "unknown entry", pattern: pattern, entry: "receipt.gohtml", wantErr: ErrTemplateNotFound},
+		{name: "no files", pattern: filepath.Join(dir, "*.tmpl"), entry: "invoice.gohtml"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ConvertNamedTemplateToPdf(context.Background(), tt.entry, nil, WithTemplateGlob(tt.pattern))
+			if err == nil {
+				t.Fatal("Expected an error")
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("Expected %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestWithTemplateFuncs(t *testing.T) {
+	upper := func(s string) string { return strings.ToUpper(s) }
+	money := func(v interface{}) string { return fmt.Sprintf("$%v", v) }
+	options := newOptions([]Option{
+		WithTemplateFuncs(template.FuncMap{"upper": upper}),
+		WithTemplateFuncs(template.FuncMap{"money": money}),
+	})
+
+	// Callers parse with placeholders; the options decide what runs.
+	tmpl := template.Must(template.New("invoice").Funcs(TemplateFuncs()).Funcs(template.FuncMap{
+		"upper": strings.ToLower,
+		"money": fmt.Sprint,
+	}).Parse(`{{upper .Name}} {{money .Total}} {{add .Total 1}}`))
+	got, err := executeTemplate(tmpl, map[string]interface{}{"Name": "acme", "Total": 9}, options)
+	if err != nil {
+		t.Fatalf("executeTemplate() error = %v", err)
+	}
+	if want := "ACME $9 10"; got != want {
+		t.Errorf("Expected %q, got %q", want, got)
+	}
+
+	// Functions added with WithTemplateFuncs replace the built-in ones.
+	funcs := newOptions([]Option{WithTemplateFuncs(template.FuncMap{"round": upper})}).templateFuncs()
+	if reflect.ValueOf(funcs["round"]).Pointer() != reflect.ValueOf(upper).Pointer() {
+		t.Error("Expected WithTemplateFuncs to replace round")
+	}
+
+	// Templates parsed by the package see the functions without placeholders.
+	file := filepath.Join(t.TempDir(), "page.html")
+	if err := os.WriteFile(file, []byte(`{{upper .}}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ConvertTemplateFileToPdf(context.Background(), file, "x", WithTemplateFuncs(template.FuncMap{"nope": upper})); err == nil || !strings.Contains(err.Error(), "failed to parse template") {
+		t.Errorf("Expected a parse error without upper, got %v", err)
+	}
+	html, err := labelsHTML(LabelSheet{Paper: Letter, Columns: 1, Rows: 1, LabelWidth: 2, LabelHeight: 1}, `{{upper .}}`, []interface{}{"label"}, options.templateFuncs())
+	if err != nil {
+		t.Fatalf("labelsHTML() error = %v", err)
+	}
+	if !strings.Contains(html, "LABEL") {
+		t.Errorf("Expected the label to use upper, got %s", html)
+	}
+}
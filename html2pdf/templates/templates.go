@@ -0,0 +1,66 @@
+// Package templates provides print-tested starter layouts for common
+// documents. Each layout sets up page size, margins, fonts, and pagination
+// rules (repeating table headers, rows kept in one piece, headings kept with
+// their content) and documents the CSS classes it styles:
+//
+//   - invoice: .invoice-header (with .meta), .parties, table.items, .totals
+//     (with tr.total), .notes
+//   - statement: .statement-header, .account-summary, table.transactions
+//     (with td.date), .balance
+//   - report: .cover, section.chapter, figure and figcaption, blockquote
+//
+// All layouts style .num for right-aligned numbers and .muted for secondary text.
+package templates
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"html/template"
+	"io/fs"
+	"sort"
+	"strings"
+)
+
+// ErrUnknownTemplate is returned for a name that is not a starter template.
+var ErrUnknownTemplate = fmt.Errorf("unknown starter template")
+
+//go:embed base.tmpl *.html
+var files embed.FS
+
+// starters holds the parsed layouts by name.
+var starters = func() map[string]*template.Template {
+	names, err := fs.Glob(files, "*.html")
+	if err != nil {
+		panic(err)
+	}
+	m := make(map[string]*template.Template, len(names))
+	for _, file := range names {
+		m[strings.TrimSuffix(file, ".html")] = template.Must(template.ParseFS(files, "base.tmpl", file))
+	}
+	return m
+}()
+
+// Names returns the names of the starter templates in sorted order.
+func Names() []string {
+	names := make([]string, 0, len(starters))
+	for name := range starters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Render returns a complete HTML document that places body, which must be
+// trusted markup, inside the named starter layout.
+func Render(name, body string) (string, error) {
+	t, ok := starters[name]
+	if !ok {
+		return "", fmt.Errorf("%w: %q", ErrUnknownTemplate, name)
+	}
+	var buf bytes.Buffer
+	if err := t.ExecuteTemplate(&buf, name+".html", template.HTML(body)); err != nil {
+		return "", fmt.Errorf("failed to render starter template %s: %w", name, err)
+	}
+	return buf.String(), nil
+}
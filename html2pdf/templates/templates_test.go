@@ -0,0 +1,44 @@
+package templates
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestNames(t *testing.T) {
+	want := []string{"invoice", "report", "statement"}
+	if got := Names(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+}
+
+func TestRender(t *testing.T) {
+	for _, name := range Names() {
+		t.Run(name, func(t *testing.T) {
+			body := `<h1>Title &amp; more</h1><table><tr><td class="num">1</td></tr></table>`
+			html, err := Render(name, body)
+			if err != nil {
+				t.Fatalf("Render() error = %v", err)
+			}
+			for _, want := range []string{
+				"<!DOCTYPE html>",
+				`<body class="` + name + `">`,
+				body,
+				"@page",
+				"thead { display: table-header-group; }",
+			} {
+				if !strings.Contains(html, want) {
+					t.Errorf("Expected output to contain %q", want)
+				}
+			}
+		})
+	}
+}
+
+func TestRenderUnknown(t *testing.T) {
+	if _, err := Render("letter", "<p>x</p>"); !errors.Is(err, ErrUnknownTemplate) {
+		t.Errorf("Expected ErrUnknownTemplate, got %v", err)
+	}
+}
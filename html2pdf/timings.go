@@ -0,0 +1,39 @@
+package html2pdf
+
+import (
+	"context"
+	"time"
+
+	"github.com/chromedp/chromedp"
+)
+
+// Timings breaks down how long each phase of a conversion took.
+type Timings struct {
+	// Allocate is the time spent starting the browser and opening a tab.
+	Allocate time.Duration
+	// Navigate is the time spent loading the blank page the content is written into.
+	Navigate time.Duration
+	// SetContent is the time spent writing the HTML into the page.
+	SetContent time.Duration
+	// WaitReady is the time spent waiting for the page to finish loading.
+	WaitReady time.Duration
+	// Print is the time Chrome spent printing the page to PDF.
+	Print time.Duration
+	// PostProcess is the time spent applying PDF post-processing.
+	PostProcess time.Duration
+}
+
+// Total returns the sum of all phases.
+func (t Timings) Total() time.Duration {
+	return t.Allocate + t.Navigate + t.SetContent + t.WaitReady + t.Print + t.PostProcess
+}
+
+// timed wraps an action so that its duration is added to d.
+func timed(d *time.Duration, action chromedp.Action) chromedp.Action {
+	return chromedp.ActionFunc(func(ctx context.Context) error {
+		start := time.Now()
+		err := action.Do(ctx)
+		*d += time.Since(start)
+		return err
+	})
+}
@@ -0,0 +1,141 @@
+package html2pdf
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"path"
+	"strings"
+	texttemplate "text/template"
+)
+
+// ErrMissingTranslation is returned when a template asks for a message that
+// the language set with WithTranslations does not define.
+var ErrMissingTranslation = fmt.Errorf("missing translation")
+
+// Translations holds messages by language tag and message ID, like a go-i18n
+// bundle. Messages are text/template source, executed with the data passed
+// to the t template function, e.g. "Due on {{.Date}}".
+type Translations map[string]map[string]string
+
+// LoadTranslations reads the JSON message files in fsys matching pattern,
+// such as "locales/*.json". The language is the last dot-separated part of
+// the file name before ".json", so "th.json" and "active.th.json" both hold
+// Thai messages. Nested objects are flattened into dotted message IDs:
+// {"invoice": {"total": "Total"}} defines "invoice.total".
+func LoadTranslations(fsys fs.FS, pattern string) (Translations, error) {
+	files, err := fs.Glob(fsys, pattern)
+	if err != nil {
+		return nil, err
+	}
+	bundle := Translations{}
+	for _, file := range files {
+		name := strings.TrimSuffix(path.Base(file), ".json")
+		lang := name[strings.LastIndex(name, ".")+1:]
+		b, err := fs.ReadFile(fsys, file)
+		if err != nil {
+			return nil, err
+		}
+		var tree map[string]interface{}
+		if err := json.Unmarshal(b, &tree); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", file, err)
+		}
+		if bundle[lang] == nil {
+			bundle[lang] = map[string]string{}
+		}
+		if err := flattenMessages(bundle[lang], "", tree); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", file, err)
+		}
+	}
+	return bundle, nil
+}
+
+// flattenMessages adds the messages of tree to messages under dotted IDs.
+func flattenMessages(messages map[string]string, prefix string, tree map[string]interface{}) error {
+	for key, value := range tree {
+		id := prefix + key
+		switch v := value.(type) {
+		case string:
+			messages[id] = v
+		case map[string]interface{}:
+			if err := flattenMessages(messages, id+".", v); err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("message %q is not a string", id)
+		}
+	}
+	return nil
+}
+
+// WithTranslations makes the t template function translate message IDs into
+// lang, e.g. {{t "invoice.total"}} or {{t "invoice.due" .}} for messages
+// using data. Messages missing in a regional language such as "pt-BR" are
+// looked up in its base language, "pt"; messages missing in both fail the
+// conversion with ErrMissingTranslation. See TemplateFuncs.
+func WithTranslations(bundle Translations, lang string) Option {
+	return func(o *options) {
+		o.translations = bundle
+		o.language = lang
+	}
+}
+
+// translator returns the t template function for lang.
+func translator(bundle Translations, lang string) func(id string, data ...interface{}) (string, error) {
+	var chain []map[string]string
+	for _, candidate := range languageChain(lang) {
+		for tag, messages := range bundle {
+			if normalizeLanguage(tag) == candidate {
+				chain = append(chain, messages)
+			}
+		}
+	}
+	return func(id string, data ...interface{}) (string, error) {
+		for _, messages := range chain {
+			msg, ok := messages[id]
+			if !ok {
+				continue
+			}
+			if len(data) == 0 || !strings.Contains(msg, "{{") {
+				return msg, nil
+			}
+			return executeMessage(id, msg, data[0])
+		}
+		return "", fmt.Errorf("%w: %q in %s", ErrMissingTranslation, id, lang)
+	}
+}
+
+// executeMessage executes the message template msg with data.
+func executeMessage(id, msg string, data interface{}) (string, error) {
+	t, err := texttemplate.New(id).Option("missingkey=error").Parse(msg)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse message %q: %w", id, err)
+	}
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("failed to execute message %q: %w", id, err)
+	}
+	return buf.String(), nil
+}
+
+// languageChain returns lang and its less specific forms, most specific
+// first: "zh-hant-tw", "zh-hant", "zh".
+func languageChain(lang string) []string {
+	lang = normalizeLanguage(lang)
+	var chain []string
+	for lang != "" {
+		chain = append(chain, lang)
+		i := strings.LastIndex(lang, "-")
+		if i < 0 {
+			break
+		}
+		lang = lang[:i]
+	}
+	return chain
+}
+
+// normalizeLanguage lowercases a language tag and uses "-" as the separator.
+func normalizeLanguage(tag string) string {
+	return strings.ToLower(strings.ReplaceAll(tag, "_", "-"))
+}
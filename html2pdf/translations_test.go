@@ -0,0 +1,113 @@
+package html2pdf
+
+import (
+	"errors"
+	"html/template"
+	"reflect"
+	"testing"
+	"testing/fstest"
+)
+
+func TestLoadTranslations(t *testing.T) {
+	fsys := fstest.MapFS{
+		"locales/en.json":        {Data: []byte(`{"invoice": {"total": "Total", "due": "Due on {{.Date}}"}}`)},
+		"locales/active.th.json": {Data: []byte(`{"invoice": {"total": "ยอดรวม"}}`)},
+		"locales/readme.txt":     {Data: []byte("not a message file")},
+	}
+
+	got, err := LoadTranslations(fsys, "locales/*.json")
+	if err != nil {
+		t.Fatalf("LoadTranslations() error = %v", err)
+	}
+	want := Translations{
+		"en": {"invoice.total": "Total", "invoice.due": "Due on {{.Date}}"},
+		"th": {"invoice.total": "ยอดรวม"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+
+	for name, data := range map[string]string{
+		"invalid JSON":   `{"invoice": `,
+		"non-string":     `{"invoice": {"total": 1}}`,
+		"top-level list": `["Total"]`,
+	} {
+		fsys := fstest.MapFS{"en.json": {Data: []byte(data)}}
+		if _, err := LoadTranslations(fsys, "*.json"); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestTranslator(t *testing.T) {
+	bundle := Translations{
+		"en":    {"total": "Total", "due": "Due on {{.Date}}", "color": "Color"},
+		"en_GB": {"color": "Colour"},
+		"th":    {"total": "ยอดรวม"},
+	}
+
+	tests := []struct {
+		name    string
+		lang    string
+		id      string
+		data    []interface{}
+		want    string
+		wantErr error
+	}{
+		{name: "exact", lang: "en", id: "total", want: "Total"},
+		{name: "region", lang: "en-GB", id: "color", want: "Colour"},
+		{name: "region falls back to base", lang: "en-gb", id: "total", want: "Total"},
+		{name: "other language", lang: "th", id: "total", want: "ยอดรวม"},
+		{name: "data", lang: "en", id: "due", data: []interface{}{map[string]string{"Date": "1 May"}}, want: "Due on 1 May"},
+		{name: "template without data", lang: "en", id: "due", want: "Due on {{.Date}}"},
+		{name: "missing message", lang: "th", id: "color", wantErr: ErrMissingTranslation},
+		{name: "missing language", lang: "fr", id: "total", wantErr: ErrMissingTranslation},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := translator(bundle, tt.lang)(tt.id, tt.data...)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("Expected %v, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("t(%q) error = %v", tt.id, err)
+			}
+			if got != tt.want {
+				t.Errorf("Expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestLanguageChain(t *testing.T) {
+	got := languageChain("zh_Hant_TW")
+	want := []string{"zh-hant-tw", "zh-hant", "zh"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+}
+
+func TestExecuteTemplateWithTranslations(t *testing.T) {
+	tmpl := template.Must(template.New("invoice").Funcs(TemplateFuncs()).Parse(`<p>{{t "total"}}: {{.Amount}}</p>`))
+	bundle := Translations{"en": {"total": "Total"}, "th": {"total": "ยอดรวม"}}
+	data := map[string]interface{}{"Amount": 10}
+
+	for lang, want := range map[string]string{"en": "<p>Total: 10</p>", "th": "<p>ยอดรวม: 10</p>"} {
+		got, err := executeTemplate(tmpl, data, newOptions([]Option{WithTranslations(bundle, lang)}))
+		if err != nil {
+			t.Fatalf("executeTemplate(%s) error = %v", lang, err)
+		}
+		if got != want {
+			t.Errorf("Expected %q, got %q", want, got)
+		}
+	}
+
+	// The template is left unbound, so it fails without translations.
+	if _, err := executeTemplate(tmpl, data, getDefaultOptions()); !errors.Is(err, ErrMissingTranslation) {
+		t.Errorf("Expected ErrMissingTranslation, got %v", err)
+	}
+}
@@ -0,0 +1,17 @@
+package html2pdf
+
+// Mm converts millimetres to the inches used by WithPaperSize, WithMargins
+// and PaperSize, e.g. PaperSize{Width: Mm(100), Height: Mm(150)}.
+func Mm(mm float64) float64 {
+	return mm / mmPerInch
+}
+
+// Cm converts centimetres to inches.
+func Cm(cm float64) float64 {
+	return Mm(cm * 10)
+}
+
+// Pt converts PDF points (1/72 inch) to inches.
+func Pt(pt float64) float64 {
+	return pt / pointsPerInch
+}
@@ -0,0 +1,27 @@
+package html2pdf
+
+import (
+	"math"
+	"testing"
+)
+
+func TestUnits(t *testing.T) {
+	tests := []struct {
+		name string
+		got  float64
+		want float64
+	}{
+		{name: "Mm(25.4)", got: Mm(25.4), want: 1},
+		{name: "Mm(210)", got: Mm(210), want: A4.Width},
+		{name: "Cm(29.7)", got: Cm(29.7), want: A4.Height},
+		{name: "Pt(72)", got: Pt(72), want: 1},
+		{name: "Pt(612)", got: Pt(612), want: Letter.Width},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if math.Abs(tt.got-tt.want) > 1e-9 {
+				t.Errorf("Expected %g, got %g", tt.want, tt.got)
+			}
+		})
+	}
+}
@@ -0,0 +1,26 @@
+package html2pdf
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+)
+
+// ErrInvalidURL is returned when a page URL is not an absolute http or https
+// URL, or a base URL is not absolute.
+var ErrInvalidURL = fmt.Errorf("invalid url")
+
+// ConvertURLToPdf navigates Chrome to a live page, waits for it to load and
+// prints it. HTTP error pages are printed like any other page; navigation
+// failures such as DNS errors are returned.
+func ConvertURLToPdf(ctx context.Context, pageURL string, opts ...Option) ([]byte, error) {
+	u, err := url.Parse(pageURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidURL, pageURL)
+	}
+	res, err := convert(ctx, document{url: u.String()}, newOptions(opts))
+	if err != nil {
+		return nil, err
+	}
+	return res.PDF, nil
+}
@@ -0,0 +1,44 @@
+package html2pdf
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestConvertURLToPdfInvalidURL(t *testing.T) {
+	tests := []string{"", "example.com/report", "ftp://example.com/report.html", "file:///etc/passwd", "http://", "http://[::1"}
+	for _, pageURL := range tests {
+		if _, err := ConvertURLToPdf(context.Background(), pageURL); !errors.Is(err, ErrInvalidURL) {
+			t.Errorf("ConvertURLToPdf(%q) error = %v, want ErrInvalidURL", pageURL, err)
+		}
+	}
+}
+
+func TestConvertURLToPdf(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(`<html><body><h1>Status: OK</h1></body></html>`))
+	}))
+	defer srv.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	got, err := ConvertURLToPdf(ctx, srv.URL, WithPostRenderAssertion(func(doc TextIndex) error {
+		if !doc.Contains("Status: OK") {
+			return errors.New("missing status")
+		}
+		return nil
+	}))
+	if err != nil {
+		t.Fatalf("ConvertURLToPdf() error = %v", err)
+	}
+	if !strings.HasPrefix(string(got), "%PDF") {
+		t.Errorf("ConvertURLToPdf() returned non-PDF content")
+	}
+}
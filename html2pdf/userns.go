@@ -0,0 +1,42 @@
+package html2pdf
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// WithUserNamespace launches Chrome in new user, IPC and mount namespaces on
+// Linux, mapping the current user to itself, so bare-metal deployments get
+// some of the isolation a container provides. It has no effect inside a
+// container, where the runtime already isolates Chrome and usually forbids
+// creating namespaces, or on other operating systems. The network namespace
+// is shared because chromedp and the local asset server reach Chrome over
+// loopback. Conversions fail if the kernel does not allow unprivileged user
+// namespaces.
+func WithUserNamespace(enabled bool) Option {
+	return func(o *options) {
+		o.userNamespace = enabled
+	}
+}
+
+// rootDir is the root of the filesystem inspected by inContainer.
+var rootDir = "/"
+
+// inContainer reports whether the process appears to run in a Docker,
+// containerd or Kubernetes container.
+func inContainer() bool {
+	if _, err := os.Stat(filepath.Join(rootDir, ".dockerenv")); err == nil {
+		return true
+	}
+	b, err := os.ReadFile(filepath.Join(rootDir, "proc/1/cgroup"))
+	if err != nil {
+		return false
+	}
+	for _, marker := range []string{"docker", "containerd", "kubepods", "libpod"} {
+		if strings.Contains(string(b), marker) {
+			return true
+		}
+	}
+	return false
+}
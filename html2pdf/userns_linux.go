@@ -0,0 +1,37 @@
+package html2pdf
+
+import (
+	"os"
+	"os/exec"
+	"syscall"
+
+	"github.com/chromedp/chromedp"
+)
+
+// userNamespaceAllocatorOptions starts Chrome in new namespaces unless the
+// process already runs in a container.
+func userNamespaceAllocatorOptions() []chromedp.ExecAllocatorOption {
+	if inContainer() {
+		return nil
+	}
+	return []chromedp.ExecAllocatorOption{
+		// ModifyCmdFunc replaces chromedp's own Pdeathsig setup, so
+		// namespaceSysProcAttr sets it again.
+		chromedp.ModifyCmdFunc(func(cmd *exec.Cmd) {
+			cmd.SysProcAttr = namespaceSysProcAttr(os.Getuid(), os.Getgid())
+		}),
+	}
+}
+
+// namespaceSysProcAttr returns process attributes that create new user, IPC
+// and mount namespaces with uid and gid mapped to themselves. Chrome is killed
+// when the parent process dies.
+func namespaceSysProcAttr(uid, gid int) *syscall.SysProcAttr {
+	return &syscall.SysProcAttr{
+		Cloneflags:                 syscall.CLONE_NEWUSER | syscall.CLONE_NEWIPC | syscall.CLONE_NEWNS,
+		UidMappings:                []syscall.SysProcIDMap{{ContainerID: uid, HostID: uid, Size: 1}},
+		GidMappings:                []syscall.SysProcIDMap{{ContainerID: gid, HostID: gid, Size: 1}},
+		GidMappingsEnableSetgroups: false,
+		Pdeathsig:                  syscall.SIGKILL,
+	}
+}
@@ -0,0 +1,44 @@
+package html2pdf
+
+import (
+	"os"
+	"syscall"
+	"testing"
+)
+
+func TestNamespaceSysProcAttr(t *testing.T) {
+	attr := namespaceSysProcAttr(1000, 1001)
+	for _, flag := range []uintptr{syscall.CLONE_NEWUSER, syscall.CLONE_NEWIPC, syscall.CLONE_NEWNS} {
+		if attr.Cloneflags&flag == 0 {
+			t.Errorf("Expected clone flag %#x to be set", flag)
+		}
+	}
+	if attr.Cloneflags&syscall.CLONE_NEWNET != 0 {
+		t.Error("Expected the network namespace to be shared")
+	}
+	if len(attr.UidMappings) != 1 || attr.UidMappings[0] != (syscall.SysProcIDMap{ContainerID: 1000, HostID: 1000, Size: 1}) {
+		t.Errorf("Unexpected uid mappings %+v", attr.UidMappings)
+	}
+	if len(attr.GidMappings) != 1 || attr.GidMappings[0] != (syscall.SysProcIDMap{ContainerID: 1001, HostID: 1001, Size: 1}) {
+		t.Errorf("Unexpected gid mappings %+v", attr.GidMappings)
+	}
+	if attr.Pdeathsig != syscall.SIGKILL {
+		t.Errorf("Expected Pdeathsig SIGKILL, got %v", attr.Pdeathsig)
+	}
+}
+
+func TestUserNamespaceAllocatorOptions(t *testing.T) {
+	defer func(dir string) { rootDir = dir }(rootDir)
+
+	rootDir = t.TempDir()
+	if got := len(userNamespaceAllocatorOptions()); got != 1 {
+		t.Errorf("Expected 1 option outside a container, got %d", got)
+	}
+
+	if err := os.WriteFile(rootDir+"/.dockerenv", nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if got := len(userNamespaceAllocatorOptions()); got != 0 {
+		t.Errorf("Expected no options inside a container, got %d", got)
+	}
+}
@@ -0,0 +1,10 @@
+//go:build !linux
+
+package html2pdf
+
+import "github.com/chromedp/chromedp"
+
+// userNamespaceAllocatorOptions is a no-op outside Linux.
+func userNamespaceAllocatorOptions() []chromedp.ExecAllocatorOption {
+	return nil
+}
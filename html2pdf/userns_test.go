@@ -0,0 +1,40 @@
+package html2pdf
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestInContainer(t *testing.T) {
+	tests := []struct {
+		name   string
+		files  map[string]string
+		expect bool
+	}{
+		{name: "bare metal", files: map[string]string{"proc/1/cgroup": "0::/init.scope\n"}, expect: false},
+		{name: "no proc", files: map[string]string{}, expect: false},
+		{name: "dockerenv", files: map[string]string{".dockerenv": ""}, expect: true},
+		{name: "docker cgroup", files: map[string]string{"proc/1/cgroup": "0::/docker/0123abcd\n"}, expect: true},
+		{name: "kubernetes cgroup", files: map[string]string{"proc/1/cgroup": "0::/kubepods/burstable/pod1\n"}, expect: true},
+	}
+
+	defer func(dir string) { rootDir = dir }(rootDir)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rootDir = t.TempDir()
+			for name, content := range tt.files {
+				path := filepath.Join(rootDir, name)
+				if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if got := inContainer(); got != tt.expect {
+				t.Errorf("Expected inContainer() = %v, got %v", tt.expect, got)
+			}
+		})
+	}
+}
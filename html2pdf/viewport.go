@@ -0,0 +1,68 @@
+package html2pdf
+
+import (
+	"fmt"
+
+	"github.com/chromedp/cdproto/page"
+	"github.com/chromedp/chromedp"
+)
+
+// viewportUnitScript rewrites vh lengths (and the dvh, svh and lvh variants)
+// in the page's style sheets and inline styles to multiples of the
+// --html2pdf-vh variable, set to a hundredth of the printable page height.
+// Cross-origin style sheets cannot be read and are left as they are.
+const viewportUnitScript = `(vh => {
+	const root = document.documentElement;
+	root.style.setProperty("--html2pdf-vh", vh + "px");
+	root.style.setProperty("--html2pdf-page-height", vh * 100 + "px");
+	const unit = /(-?\d*\.?\d+)[dsl]?vh\b/gi;
+	const fix = style => {
+		for (const prop of Array.from(style)) {
+			const value = style.getPropertyValue(prop);
+			const fixed = value.replace(unit, "calc($1 * var(--html2pdf-vh))");
+			if (fixed !== value) {
+				style.setProperty(prop, fixed, style.getPropertyPriority(prop));
+			}
+		}
+	};
+	const walk = rules => {
+		for (const rule of rules) {
+			if (rule.style) {
+				fix(rule.style);
+			}
+			if (rule.cssRules) {
+				walk(rule.cssRules);
+			}
+		}
+	};
+	for (const sheet of document.styleSheets) {
+		try {
+			walk(sheet.cssRules);
+		} catch (e) {}
+	}
+	for (const el of document.querySelectorAll("[style]")) {
+		fix(el.style);
+	}
+})(%g)`
+
+// WithViewportUnitFix makes vh units relative to the printable page height
+// instead of the browser window, so 100vh sections such as title pages fill
+// exactly one page rather than spilling onto a blank one. The page height is
+// also available to templates as the CSS variable --html2pdf-page-height.
+func WithViewportUnitFix(enabled bool) Option {
+	return func(o *options) {
+		o.viewportUnitFix = enabled
+	}
+}
+
+// printableVH returns a hundredth of the printable page height of params in
+// CSS pixels.
+func printableVH(params *page.PrintToPDFParams) float64 {
+	_, paperH := paperDimensions(params)
+	return (paperH - params.MarginTop - params.MarginBottom) * cssPixelsPerInch / printScale(params) / 100
+}
+
+// fixViewportUnits applies WithViewportUnitFix for the page size of params.
+func fixViewportUnits(params *page.PrintToPDFParams) chromedp.Action {
+	return chromedp.Evaluate(fmt.Sprintf(viewportUnitScript, printableVH(params)), nil)
+}
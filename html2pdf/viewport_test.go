@@ -0,0 +1,50 @@
+package html2pdf
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestPrintableVH(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []Option
+		want float64
+	}{
+		{name: "default letter", want: 11.0 * cssPixelsPerInch / 100},
+		{name: "margins", opts: []Option{WithMargins(0.5, 0.5, 0, 0)}, want: 10.0 * cssPixelsPerInch / 100},
+		{name: "landscape", opts: []Option{WithLandscape(true)}, want: 8.5 * cssPixelsPerInch / 100},
+		{name: "scale", opts: []Option{WithPaperSize(PaperSize{Width: 5, Height: 5}), WithScale(0.5)}, want: 10.0 * cssPixelsPerInch / 100},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := printableVH(newOptions(tt.opts).printToPDFParams()); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("Expected %g, got %g", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestConvertHtmlToPdfWithViewportUnitFix(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	html := `<html><head><style>
+	body { margin: 0; }
+	.hero { height: 100vh; }
+	</style></head><body><section class="hero">Title</section><section style="min-height: 50dvh">Next</section></body></html>`
+	res, err := ConvertHtmlToResult(ctx, html,
+		WithPaperSize(A4), WithMargins(0.5, 0.5, 0.5, 0.5), WithViewportUnitFix(true))
+	if err != nil {
+		t.Fatalf("ConvertHtmlToResult() error = %v", err)
+	}
+	pages, err := res.PageInfos()
+	if err != nil {
+		t.Fatalf("PageInfos() error = %v", err)
+	}
+	if len(pages) != 2 {
+		t.Errorf("Expected the hero to fill exactly the first page, got %d pages", len(pages))
+	}
+}
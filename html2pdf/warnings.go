@@ -0,0 +1,205 @@
+package html2pdf
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/chromedp/cdproto/cdp"
+	"github.com/chromedp/cdproto/log"
+	"github.com/chromedp/cdproto/network"
+	"github.com/chromedp/cdproto/runtime"
+	"github.com/chromedp/cdproto/security"
+	"github.com/chromedp/chromedp"
+)
+
+// Warning kinds reported in Warning.Kind.
+const (
+	WarningMixedContent   = "mixed-content"
+	WarningBlockedRequest = "blocked-request"
+	WarningFailedRequest  = "failed-request"
+	WarningSlowResource   = "slow-resource"
+	WarningMissingFont    = "missing-font"
+	WarningConsole        = "console"
+)
+
+// maxWarnings limits how many warnings a conversion collects, so a page
+// logging in a loop cannot grow the result without bound.
+const maxWarnings = 100
+
+// slowResourceThreshold is how long a resource may take to load before it
+// is reported as slow.
+var slowResourceThreshold = 3 * time.Second
+
+// missingFontsScript lists the font families whose web fonts failed to load.
+const missingFontsScript = `[...new Set(Array.from(document.fonts).filter(f => f.status === "error").map(f => f.family))]`
+
+// Warning is a non-fatal problem noticed while rendering, such as an image
+// that failed to load. Conversions succeed despite warnings.
+type Warning struct {
+	// Kind classifies the warning, e.g. WarningFailedRequest.
+	Kind string
+	// URL is the resource the warning is about, if any.
+	URL string
+	// Message describes the problem.
+	Message string
+}
+
+// String formats the warning as "message: url (kind)".
+func (w Warning) String() string {
+	if w.URL == "" {
+		return fmt.Sprintf("%s (%s)", w.Message, w.Kind)
+	}
+	return fmt.Sprintf("%s: %s (%s)", w.Message, w.URL, w.Kind)
+}
+
+// warningCollector gathers warnings from browser events during a conversion.
+type warningCollector struct {
+	mu       sync.Mutex
+	warnings []Warning
+	requests map[network.RequestID]pendingRequest
+}
+
+// pendingRequest is a request that has not finished loading.
+type pendingRequest struct {
+	url     string
+	started time.Time
+}
+
+// add records w unless the limit is reached.
+func (c *warningCollector) add(w Warning) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.warnings) < maxWarnings {
+		c.warnings = append(c.warnings, w)
+	}
+}
+
+// list returns the collected warnings.
+func (c *warningCollector) list() []Warning {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.warnings
+}
+
+// listen starts collecting warnings from network, console and log events.
+func (c *warningCollector) listen() chromedp.ActionFunc {
+	return func(ctx context.Context) error {
+		c.requests = map[network.RequestID]pendingRequest{}
+		chromedp.ListenTarget(ctx, c.handle)
+		return nil
+	}
+}
+
+// handle turns a browser event into a warning where it indicates a problem.
+func (c *warningCollector) handle(ev interface{}) {
+	switch ev := ev.(type) {
+	case *network.EventRequestWillBeSent:
+		if strings.HasPrefix(ev.Request.URL, "data:") {
+			return
+		}
+		if t := ev.Request.MixedContentType; t != "" && t != security.MixedContentTypeNone {
+			c.add(Warning{Kind: WarningMixedContent, URL: ev.Request.URL, Message: "insecure resource on a secure page"})
+		}
+		c.mu.Lock()
+		c.requests[ev.RequestID] = pendingRequest{url: ev.Request.URL, started: monotonicTime(ev.Timestamp)}
+		c.mu.Unlock()
+	case *network.EventResponseReceived:
+		if ev.Response != nil && ev.Response.Status >= 400 {
+			c.add(Warning{Kind: WarningFailedRequest, URL: ev.Response.URL, Message: fmt.Sprintf("HTTP %d %s", ev.Response.Status, ev.Response.StatusText)})
+		}
+	case *network.EventLoadingFinished:
+		if req, ok := c.finish(ev.RequestID); ok {
+			if d := monotonicTime(ev.Timestamp).Sub(req.started); d > slowResourceThreshold {
+				c.add(Warning{Kind: WarningSlowResource, URL: req.url, Message: fmt.Sprintf("took %v to load", d.Round(time.Millisecond))})
+			}
+		}
+	case *network.EventLoadingFailed:
+		req, ok := c.finish(ev.RequestID)
+		switch {
+		case !ok || ev.Canceled:
+		case ev.BlockedReason != "":
+			c.add(Warning{Kind: WarningBlockedRequest, URL: req.url, Message: fmt.Sprintf("blocked: %s", ev.BlockedReason)})
+		default:
+			c.add(Warning{Kind: WarningFailedRequest, URL: req.url, Message: ev.ErrorText})
+		}
+	case *runtime.EventConsoleAPICalled:
+		if ev.Type == runtime.APITypeWarning || ev.Type == runtime.APITypeError {
+			c.add(Warning{Kind: WarningConsole, Message: fmt.Sprintf("console.%s: %s", ev.Type, consoleText(ev.Args))})
+		}
+	case *runtime.EventExceptionThrown:
+		if ev.ExceptionDetails == nil {
+			return
+		}
+		c.add(Warning{Kind: WarningConsole, URL: ev.ExceptionDetails.URL, Message: exceptionText(ev.ExceptionDetails)})
+	case *log.EventEntryAdded:
+		// Network entries duplicate the request warnings.
+		if ev.Entry.Source != log.SourceNetwork && (ev.Entry.Level == log.LevelWarning || ev.Entry.Level == log.LevelError) {
+			c.add(Warning{Kind: WarningConsole, URL: ev.Entry.URL, Message: ev.Entry.Text})
+		}
+	}
+}
+
+// finish removes and returns the pending request with id.
+func (c *warningCollector) finish(id network.RequestID) (pendingRequest, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	req, ok := c.requests[id]
+	delete(c.requests, id)
+	return req, ok
+}
+
+// checkFonts adds a warning for each web font that failed to load.
+func (c *warningCollector) checkFonts() chromedp.ActionFunc {
+	return func(ctx context.Context) error {
+		var families []string
+		if err := chromedp.Evaluate(missingFontsScript, &families).Do(ctx); err != nil {
+			return err
+		}
+		for _, family := range families {
+			c.add(Warning{Kind: WarningMissingFont, Message: fmt.Sprintf("web font %s failed to load", family)})
+		}
+		return nil
+	}
+}
+
+// consoleText joins console call arguments like the browser console does.
+func consoleText(args []*runtime.RemoteObject) string {
+	parts := make([]string, 0, len(args))
+	for _, arg := range args {
+		var s string
+		switch {
+		case len(arg.Value) > 0:
+			if err := json.Unmarshal(arg.Value, &s); err != nil {
+				s = string(arg.Value)
+			}
+		case arg.UnserializableValue != "":
+			s = string(arg.UnserializableValue)
+		default:
+			s = arg.Description
+		}
+		parts = append(parts, s)
+	}
+	return strings.Join(parts, " ")
+}
+
+// exceptionText describes an uncaught exception by the first line of its
+// description, leaving out the stack trace.
+func exceptionText(d *runtime.ExceptionDetails) string {
+	if d.Exception != nil && d.Exception.Description != "" {
+		first, _, _ := strings.Cut(d.Exception.Description, "\n")
+		return "uncaught " + first
+	}
+	return d.Text
+}
+
+// monotonicTime returns t as a time.Time, or the zero time when t is nil.
+func monotonicTime(t *cdp.MonotonicTime) time.Time {
+	if t == nil {
+		return time.Time{}
+	}
+	return t.Time()
+}
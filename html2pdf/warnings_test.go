@@ -0,0 +1,141 @@
+package html2pdf
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/chromedp/cdproto/cdp"
+	"github.com/chromedp/cdproto/log"
+	"github.com/chromedp/cdproto/network"
+	"github.com/chromedp/cdproto/runtime"
+	"github.com/chromedp/cdproto/security"
+)
+
+func TestWarningCollector(t *testing.T) {
+	at := func(d time.Duration) *cdp.MonotonicTime {
+		ts := cdp.MonotonicTime(time.Unix(1000, 0).Add(d))
+		return &ts
+	}
+	sent := func(id, url string, mixed security.MixedContentType) *network.EventRequestWillBeSent {
+		return &network.EventRequestWillBeSent{
+			RequestID: network.RequestID(id),
+			Request:   &network.Request{URL: url, MixedContentType: mixed},
+			Timestamp: at(0),
+		}
+	}
+
+	tests := []struct {
+		name   string
+		events []interface{}
+		want   []Warning
+	}{
+		{
+			name: "fast resource",
+			events: []interface{}{
+				sent("1", "https://example.com/a.css", security.MixedContentTypeNone),
+				&network.EventLoadingFinished{RequestID: "1", Timestamp: at(time.Second)},
+			},
+		},
+		{
+			name: "slow resource",
+			events: []interface{}{
+				sent("1", "https://example.com/big.png", ""),
+				&network.EventLoadingFinished{RequestID: "1", Timestamp: at(4 * time.Second)},
+			},
+			want: []Warning{{Kind: WarningSlowResource, URL: "https://example.com/big.png", Message: "took 4s to load"}},
+		},
+		{
+			name:   "mixed content",
+			events: []interface{}{sent("1", "http://example.com/a.png", security.MixedContentTypeOptionallyBlockable)},
+			want:   []Warning{{Kind: WarningMixedContent, URL: "http://example.com/a.png", Message: "insecure resource on a secure page"}},
+		},
+		{
+			name: "http error",
+			events: []interface{}{
+				&network.EventResponseReceived{Response: &network.Response{URL: "https://example.com/logo.png", Status: 404, StatusText: "Not Found"}},
+			},
+			want: []Warning{{Kind: WarningFailedRequest, URL: "https://example.com/logo.png", Message: "HTTP 404 Not Found"}},
+		},
+		{
+			name: "blocked and failed requests",
+			events: []interface{}{
+				sent("1", "https://ads.example.com/x.js", ""),
+				&network.EventLoadingFailed{RequestID: "1", BlockedReason: network.BlockedReasonInspector},
+				sent("2", "https://down.example.com/y.css", ""),
+				&network.EventLoadingFailed{RequestID: "2", ErrorText: "net::ERR_NAME_NOT_RESOLVED"},
+				sent("3", "https://example.com/z.png", ""),
+				&network.EventLoadingFailed{RequestID: "3", Canceled: true},
+			},
+			want: []Warning{
+				{Kind: WarningBlockedRequest, URL: "https://ads.example.com/x.js", Message: "blocked: inspector"},
+				{Kind: WarningFailedRequest, URL: "https://down.example.com/y.css", Message: "net::ERR_NAME_NOT_RESOLVED"},
+			},
+		},
+		{
+			name:   "data urls are ignored",
+			events: []interface{}{sent("1", "data:image/png;base64,AA==", security.MixedContentTypeBlockable)},
+		},
+		{
+			name: "console",
+			events: []interface{}{
+				&runtime.EventConsoleAPICalled{Type: runtime.APITypeLog, Args: []*runtime.RemoteObject{{Value: []byte(`"ignored"`)}}},
+				&runtime.EventConsoleAPICalled{Type: runtime.APITypeWarning, Args: []*runtime.RemoteObject{{Value: []byte(`"chart data missing"`)}, {Value: []byte(`42`)}}},
+				&runtime.EventExceptionThrown{ExceptionDetails: &runtime.ExceptionDetails{URL: "https://example.com/app.js", Exception: &runtime.RemoteObject{Description: "TypeError: x is undefined\n    at app.js:1:1"}}},
+				&log.EventEntryAdded{Entry: &log.Entry{Source: log.SourceNetwork, Level: log.LevelError, Text: "duplicate"}},
+				&log.EventEntryAdded{Entry: &log.Entry{Source: log.SourceIntervention, Level: log.LevelWarning, Text: "intervention"}},
+			},
+			want: []Warning{
+				{Kind: WarningConsole, Message: "console.warning: chart data missing 42"},
+				{Kind: WarningConsole, URL: "https://example.com/app.js", Message: "uncaught TypeError: x is undefined"},
+				{Kind: WarningConsole, Message: "intervention"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &warningCollector{requests: map[network.RequestID]pendingRequest{}}
+			for _, ev := range tt.events {
+				c.handle(ev)
+			}
+			got := c.list()
+			if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("Expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestWarningCollectorLimit(t *testing.T) {
+	c := &warningCollector{}
+	for i := 0; i < maxWarnings+10; i++ {
+		c.add(Warning{Kind: WarningConsole, Message: "spam"})
+	}
+	if got := len(c.list()); got != maxWarnings {
+		t.Errorf("Expected %d warnings, got %d", maxWarnings, got)
+	}
+}
+
+func TestConvertHtmlToResultWarnings(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	defer srv.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	res, err := ConvertHtmlToResult(ctx, fmt.Sprintf(`<html><body><img src="%s/logo.png"><script>console.warn("careful")</script></body></html>`, srv.URL))
+	if err != nil {
+		t.Fatalf("ConvertHtmlToResult() error = %v", err)
+	}
+	kinds := map[string]bool{}
+	for _, w := range res.Warnings {
+		kinds[w.Kind] = true
+	}
+	if !kinds[WarningFailedRequest] || !kinds[WarningConsole] {
+		t.Errorf("Expected failed-request and console warnings, got %v", res.Warnings)
+	}
+}
@@ -0,0 +1,50 @@
+package html2pdf
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"strings"
+)
+
+// ConvertZipToPdf converts a ZIP bundle holding an HTML page and its assets
+// (CSS, images, fonts) to PDF. entryHTML is the path of the page inside the
+// bundle, e.g. "index.html". The bundle is served to Chrome from memory so
+// relative asset references resolve.
+func ConvertZipToPdf(ctx context.Context, zipBytes []byte, entryHTML string, opts ...Option) ([]byte, error) {
+	zr, err := zip.NewReader(bytes.NewReader(zipBytes), int64(len(zipBytes)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read zip bundle: %w", err)
+	}
+	return convertFS(ctx, zr, entryHTML, opts...)
+}
+
+// ConvertHtmlFSToPdf converts the HTML page at path in fsys, such as an
+// embed.FS holding templates with their CSS and images. fsys is served to
+// Chrome from memory so relative asset references resolve.
+func ConvertHtmlFSToPdf(ctx context.Context, fsys fs.FS, path string, opts ...Option) ([]byte, error) {
+	return convertFS(ctx, fsys, path, opts...)
+}
+
+// convertFS serves fsys over a loopback server and converts the page at entry.
+func convertFS(ctx context.Context, fsys fs.FS, entry string, opts ...Option) ([]byte, error) {
+	entry = strings.TrimPrefix(entry, "/")
+	if _, err := fs.Stat(fsys, entry); err != nil {
+		return nil, ErrHTMLFileNotFound
+	}
+
+	srv, err := newAssetServer(http.FileServer(http.FS(fsys)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to start asset server: %w", err)
+	}
+	defer srv.Close()
+
+	res, err := convert(ctx, document{url: srv.URL(entry)}, newOptions(opts))
+	if err != nil {
+		return nil, err
+	}
+	return res.PDF, nil
+}
@@ -0,0 +1,127 @@
+package html2pdf
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+// testZip builds a ZIP bundle from a map of file names to contents.
+func testZip(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for name, content := range files {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatalf("Failed to create zip entry: %v", err)
+		}
+		if _, err := w.Write([]byte(content)); err != nil {
+			t.Fatalf("Failed to write zip entry: %v", err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatalf("Failed to close zip: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestConvertZipToPdf(t *testing.T) {
+	bundle := testZip(t, map[string]string{
+		"index.html":      `<html><head><link rel="stylesheet" href="css/style.css"></head><body><h1>Bundle</h1></body></html>`,
+		"css/style.css":   `h1 { color: #2c3e50; }`,
+		"report/one.html": `<html><body><p>Nested</p></body></html>`,
+	})
+
+	tests := []struct {
+		name    string
+		zip     []byte
+		entry   string
+		wantErr error
+	}{
+		{name: "index entry", zip: bundle, entry: "index.html"},
+		{name: "nested entry", zip: bundle, entry: "/report/one.html"},
+		{name: "missing entry", zip: bundle, entry: "missing.html", wantErr: ErrHTMLFileNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			defer cancel()
+
+			got, err := ConvertZipToPdf(ctx, tt.zip, tt.entry)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("ConvertZipToPdf() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ConvertZipToPdf() error = %v", err)
+			}
+			if !strings.HasPrefix(string(got), "%PDF") {
+				t.Errorf("ConvertZipToPdf() returned non-PDF content")
+			}
+		})
+	}
+}
+
+func TestConvertZipToPdfInvalidZip(t *testing.T) {
+	_, err := ConvertZipToPdf(context.Background(), []byte("not a zip"), "index.html")
+	if err == nil {
+		t.Error("Expected error for invalid zip bundle, got none")
+	}
+}
+
+func TestConvertHtmlFSToPdf(t *testing.T) {
+	fsys := fstest.MapFS{
+		"templates/report.html": {Data: []byte(`<html><head><link rel="stylesheet" href="style.css"></head><body><h1>Embedded</h1></body></html>`)},
+		"templates/style.css":   {Data: []byte(`h1 { color: #2c3e50; }`)},
+	}
+
+	_, err := ConvertHtmlFSToPdf(context.Background(), fsys, "templates/missing.html")
+	if !errors.Is(err, ErrHTMLFileNotFound) {
+		t.Errorf("Expected ErrHTMLFileNotFound, got %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	got, err := ConvertHtmlFSToPdf(ctx, fsys, "templates/report.html")
+	if err != nil {
+		t.Fatalf("ConvertHtmlFSToPdf() error = %v", err)
+	}
+	if !strings.HasPrefix(string(got), "%PDF") {
+		t.Errorf("ConvertHtmlFSToPdf() returned non-PDF content")
+	}
+}
+
+func TestAssetServer(t *testing.T) {
+	zipBytes := testZip(t, map[string]string{"css/style.css": "h1 { color: red; }"})
+	zr, err := zip.NewReader(bytes.NewReader(zipBytes), int64(len(zipBytes)))
+	if err != nil {
+		t.Fatalf("Failed to read zip: %v", err)
+	}
+
+	srv, err := newAssetServer(http.FileServer(http.FS(zr)))
+	if err != nil {
+		t.Fatalf("newAssetServer() error = %v", err)
+	}
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL("css/style.css"))
+	if err != nil {
+		t.Fatalf("Failed to fetch asset: %v", err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK || string(body) != "h1 { color: red; }" {
+		t.Errorf("Unexpected response %d %q", resp.StatusCode, body)
+	}
+}